@@ -41,14 +41,18 @@ type ClusterSpec struct {
 
 	// Foo is an example field of Cluster. Edit Cluster_types.go to remove/update
 	Hosts                []HostCfg            `yaml:"hosts" json:"hosts,omitempty"`
+	Bastion              BastionCfg           `yaml:"bastion,omitempty" json:"bastion,omitempty"`
 	RoleGroups           map[string][]string  `yaml:"roleGroups" json:"roleGroups,omitempty"`
 	ControlPlaneEndpoint ControlPlaneEndpoint `yaml:"controlPlaneEndpoint" json:"controlPlaneEndpoint,omitempty"`
 	System               System               `yaml:"system" json:"system,omitempty"`
+	Execution            ExecutionCfg         `yaml:"execution,omitempty" json:"execution,omitempty"`
+	Etcd                 EtcdCluster          `yaml:"etcd,omitempty" json:"etcd,omitempty"`
 	Kubernetes           Kubernetes           `yaml:"kubernetes" json:"kubernetes,omitempty"`
 	Network              NetworkConfig        `yaml:"network" json:"network,omitempty"`
 	Registry             RegistryConfig       `yaml:"registry" json:"registry,omitempty"`
 	Addons               []Addon              `yaml:"addons" json:"addons,omitempty"`
 	KubeSphere           KubeSphere           `json:"kubesphere,omitempty"`
+	Checksums            ChecksumsConfig      `yaml:"checksums,omitempty" json:"checksums,omitempty"`
 }
 
 // ClusterStatus defines the observed state of Cluster
@@ -149,9 +153,64 @@ type HostCfg struct {
 	PrivateKey      string `yaml:"privateKey,omitempty" json:"privateKey,omitempty"`
 	PrivateKeyPath  string `yaml:"privateKeyPath,omitempty" json:"privateKeyPath,omitempty"`
 	Arch            string `yaml:"arch,omitempty" json:"arch,omitempty"`
+	AgentSocket     string `yaml:"agentSocket,omitempty" json:"agentSocket,omitempty"`
+
+	// PasswordFrom and PrivateKeyFrom reference the credentials instead of writing them in plaintext,
+	// they are resolved when the config is loaded and take precedence over Password and PrivateKey.
+	PasswordFrom   *ValueSource `yaml:"passwordFrom,omitempty" json:"passwordFrom,omitempty"`
+	PrivateKeyFrom *ValueSource `yaml:"privateKeyFrom,omitempty" json:"privateKeyFrom,omitempty"`
+
+	Bastion BastionCfg        `yaml:"bastion,omitempty" json:"bastion,omitempty"`
+	Labels  map[string]string `yaml:"labels,omitempty" json:"labels,omitempty"`
+	// Taints are registered by kubelet when the node joins, the Labels and Taints are reconciled by kubekey
+	// and the ones removed from the config are removed from the node as well.
+	Taints []Taint `yaml:"taints,omitempty" json:"taints,omitempty"`
+	ID     string  `yaml:"id,omitempty" json:"id,omitempty"`
+}
+
+// Taint defines a taint of the node.
+type Taint struct {
+	Key   string `yaml:"key" json:"key"`
+	Value string `yaml:"value,omitempty" json:"value,omitempty"`
+	// Effect is one of NoSchedule, PreferNoSchedule and NoExecute.
+	Effect string `yaml:"effect" json:"effect"`
+}
+
+// ValueSource defines where a credential is read from.
+type ValueSource struct {
+	// Env is the name of the environment variable which holds the value.
+	Env string `yaml:"env,omitempty" json:"env,omitempty"`
+	// File is the path of the file which holds the value, the trailing newline is trimmed.
+	File string `yaml:"file,omitempty" json:"file,omitempty"`
+	// SopsFileRef selects a key of a sops encrypted file, it is decrypted by the sops binary with the keys
+	// (e.g. SOPS_AGE_KEY_FILE) configured on the machine running kk.
+	SopsFileRef *SopsFileSelector `yaml:"sopsFileRef,omitempty" json:"sopsFileRef,omitempty"`
+	// SecretKeyRef selects a key of a Secret in the kubekey-system namespace, it works only when kk runs inside the cluster.
+	SecretKeyRef *SecretKeySelector `yaml:"secretKeyRef,omitempty" json:"secretKeyRef,omitempty"`
+}
+
+type SopsFileSelector struct {
+	Path string `yaml:"path" json:"path"`
+	Key  string `yaml:"key" json:"key"`
+}
+
+// SecretKeySelector selects a key of a Secret.
+type SecretKeySelector struct {
+	Name string `yaml:"name" json:"name"`
+	Key  string `yaml:"key" json:"key"`
+}
 
-	Labels map[string]string `yaml:"labels,omitempty" json:"labels,omitempty"`
-	ID     string            `yaml:"id,omitempty" json:"id,omitempty"`
+// BastionCfg defines the jump host used to reach the hosts. The cluster-wide setting applies to every host without its own.
+type BastionCfg struct {
+	Address        string `yaml:"address,omitempty" json:"address,omitempty"`
+	Port           int    `yaml:"port,omitempty" json:"port,omitempty"`
+	User           string `yaml:"user,omitempty" json:"user,omitempty"`
+	Password       string `yaml:"password,omitempty" json:"password,omitempty"`
+	PrivateKey     string `yaml:"privateKey,omitempty" json:"privateKey,omitempty"`
+	PrivateKeyPath string `yaml:"privateKeyPath,omitempty" json:"privateKeyPath,omitempty"`
+
+	PasswordFrom   *ValueSource `yaml:"passwordFrom,omitempty" json:"passwordFrom,omitempty"`
+	PrivateKeyFrom *ValueSource `yaml:"privateKeyFrom,omitempty" json:"privateKeyFrom,omitempty"`
 }
 
 // ControlPlaneEndpoint defines the control plane endpoint information for cluster.
@@ -166,6 +225,53 @@ type ControlPlaneEndpoint struct {
 type System struct {
 	NtpServers []string `yaml:"ntpServers" json:"ntpServers,omitempty"`
 	Timezone   string   `yaml:"timezone" json:"timezone,omitempty"`
+	// Sysctls are merged into the default kernel parameters and written to /etc/sysctl.d/99-kubekey-sysctl.conf.
+	// An empty value removes the default parameter of the same key.
+	Sysctls map[string]string `yaml:"sysctls,omitempty" json:"sysctls,omitempty"`
+	// KernelModules are loaded in addition to the default ones and written to /etc/modules-load.d/kubekey.conf.
+	KernelModules []string `yaml:"kernelModules,omitempty" json:"kernelModules,omitempty"`
+	// Ulimits are merged into the default resource limits, e.g. nofile: "65535", and written to
+	// /etc/security/limits.d/99-kubekey-limits.conf. An empty value removes the default limit of the same item.
+	Ulimits map[string]string `yaml:"ulimits,omitempty" json:"ulimits,omitempty"`
+	// SELinux is how SELinux is handled: disabled (default), permissive or keep.
+	SELinux string `yaml:"selinux,omitempty" json:"selinux,omitempty"`
+	// Firewall is how firewalld and ufw are handled: disabled (default), keep, or managed which keeps the firewall
+	// running and opens the ports required by the roles of each host.
+	Firewall string `yaml:"firewall,omitempty" json:"firewall,omitempty"`
+}
+
+// IsFirewallManaged returns true if kubekey opens the ports of the cluster on the host firewalls.
+func (s *System) IsFirewallManaged() bool {
+	return s.Firewall == FirewallManaged
+}
+
+// Validate checks the SELinux and firewall modes of the system config.
+func (s *System) Validate() error {
+	switch s.SELinux {
+	case SELinuxDisabled, SELinuxPermissive, SELinuxKeep:
+	default:
+		return errors.Errorf("invalid selinux mode %s, it must be one of %s, %s and %s", s.SELinux, SELinuxDisabled, SELinuxPermissive, SELinuxKeep)
+	}
+	switch s.Firewall {
+	case FirewallDisabled, FirewallKeep, FirewallManaged:
+	default:
+		return errors.Errorf("invalid firewall mode %s, it must be one of %s, %s and %s", s.Firewall, FirewallDisabled, FirewallKeep, FirewallManaged)
+	}
+	for _, module := range s.KernelModules {
+		if module == "" || strings.ContainsAny(module, " \t/") {
+			return errors.Errorf("invalid kernel module name %q", module)
+		}
+	}
+	return nil
+}
+
+// ExecutionCfg defines how the tasks are executed on the hosts.
+type ExecutionCfg struct {
+	// MaxParallel is the max number of hosts which a task runs on at the same time.
+	MaxParallel int `yaml:"maxParallel,omitempty" json:"maxParallel,omitempty"`
+	// MaxUnavailable is the number (e.g. "2") or the percentage (e.g. "20%") of nodes which can be disrupted
+	// at the same time by the rolling tasks, such as restarting kubelet during upgrade.
+	MaxUnavailable string `yaml:"maxUnavailable,omitempty" json:"maxUnavailable,omitempty"`
 }
 
 // RegistryConfig defines the configuration information of the image's repository.
@@ -186,12 +292,47 @@ type KubeSphere struct {
 	Configurations string `json:"configurations,omitempty"`
 }
 
+// ChecksumsConfig defines where the SHA256 checksums of the downloaded binaries come from.
+type ChecksumsConfig struct {
+	// File is a local checksums file, which has the same layout as the built-in table
+	// (binary name -> arch -> version -> sha256). It takes precedence over the built-in table.
+	File string `yaml:"file,omitempty" json:"file,omitempty"`
+	// DisableUpstream disables fetching the checksum files published with the upstream releases
+	// for the versions which are not in the checksums file or the built-in table.
+	DisableUpstream bool `yaml:"disableUpstream,omitempty" json:"disableUpstream,omitempty"`
+}
+
+// EtcdCluster defines the etcd cluster used by the kubernetes cluster.
+type EtcdCluster struct {
+	// Type is the way to deploy etcd, kubekey installs it as a systemd service on the etcd nodes by default.
+	// It can be set to kubeadm to run etcd as static pods on the masters, or external to use an existing
+	// etcd cluster which is not managed by kubekey.
+	Type     string       `yaml:"type,omitempty" json:"type,omitempty"`
+	External ExternalEtcd `yaml:"external,omitempty" json:"external,omitempty"`
+}
+
 // ExternalEtcd defines configuration information of external etcd.
 type ExternalEtcd struct {
-	Endpoints []string
-	CaFile    string
-	CertFile  string
-	KeyFile   string
+	Endpoints []string `yaml:"endpoints,omitempty" json:"endpoints,omitempty"`
+	// CaFile, CertFile and KeyFile are the local paths of the client TLS files, they are uploaded to the masters.
+	CaFile   string `yaml:"caFile,omitempty" json:"caFile,omitempty"`
+	CertFile string `yaml:"certFile,omitempty" json:"certFile,omitempty"`
+	KeyFile  string `yaml:"keyFile,omitempty" json:"keyFile,omitempty"`
+}
+
+// IsKubeKey returns whether etcd is installed on the etcd nodes by kubekey.
+func (e *EtcdCluster) IsKubeKey() bool {
+	return e.Type == "" || e.Type == KubeKeyEtcdType
+}
+
+// IsKubeadm returns whether etcd runs as static pods on the masters which are managed by kubeadm.
+func (e *EtcdCluster) IsKubeadm() bool {
+	return e.Type == KubeadmEtcdType
+}
+
+// IsExternal returns whether the etcd cluster is an existing one which is not managed by kubekey.
+func (e *EtcdCluster) IsExternal() bool {
+	return e.Type == ExternalEtcdType
 }
 
 // GenerateCertSANs is used to generate cert sans for cluster.
@@ -209,12 +350,18 @@ func (cfg *ClusterSpec) GenerateCertSANs() []string {
 		if host.Address != cfg.ControlPlaneEndpoint.Address {
 			extraCertSANs = append(extraCertSANs, host.Address)
 		}
-		if host.InternalAddress != host.Address && host.InternalAddress != cfg.ControlPlaneEndpoint.Address {
-			extraCertSANs = append(extraCertSANs, host.InternalAddress)
+		for _, addr := range host.InternalAddresses() {
+			if addr != host.Address && addr != cfg.ControlPlaneEndpoint.Address {
+				extraCertSANs = append(extraCertSANs, addr)
+			}
 		}
 	}
 
-	extraCertSANs = append(extraCertSANs, util.ParseIp(cfg.Network.KubeServiceCIDR)[0])
+	for _, cidr := range cfg.Network.ServiceCIDRs() {
+		if ip, err := util.NthIP(cidr, 1); err == nil {
+			extraCertSANs = append(extraCertSANs, ip)
+		}
+	}
 
 	defaultCertSANs = append(defaultCertSANs, extraCertSANs...)
 
@@ -231,6 +378,11 @@ func (cfg *ClusterSpec) GroupHosts() (map[string][]*connector.BaseHost, error) {
 	for _, hostCfg := range cfg.Hosts {
 		host := toHosts(hostCfg)
 		hostMap[host.Name] = host
+		for _, taint := range hostCfg.Taints {
+			if err := taint.Validate(); err != nil {
+				logger.Log.Fatal(errors.Wrapf(err, "invalid taint of the host %s", hostCfg.Name))
+			}
+		}
 	}
 
 	roleGroups, err := cfg.ParseRolesList(hostMap)
@@ -242,7 +394,11 @@ func (cfg *ClusterSpec) GroupHosts() (map[string][]*connector.BaseHost, error) {
 	if len(roleGroups[Master]) == 0 && len(roleGroups[ControlPlane]) == 0 {
 		logger.Log.Fatal(errors.New("The number of master/control-plane cannot be 0"))
 	}
-	if len(roleGroups[Etcd]) == 0 {
+	if !cfg.Etcd.IsKubeKey() {
+		if len(roleGroups[Etcd]) != 0 {
+			logger.Log.Fatal(errors.Errorf("The etcd nodes cannot be set when the etcd type is %s", cfg.Etcd.Type))
+		}
+	} else if len(roleGroups[Etcd]) == 0 {
 		logger.Log.Fatal(errors.New("The number of etcd cannot be 0"))
 	}
 	if len(roleGroups[Registry]) > 1 {
@@ -261,24 +417,84 @@ func toHosts(cfg HostCfg) *connector.BaseHost {
 	host := connector.NewHost()
 	host.Name = cfg.Name
 	host.Address = cfg.Address
-	host.InternalAddress = cfg.InternalAddress
+	if addrs := cfg.InternalAddresses(); len(addrs) != 0 {
+		host.InternalAddress = addrs[0]
+	}
 	host.Port = cfg.Port
 	host.User = cfg.User
 	host.Password = cfg.Password
 	host.PrivateKey = cfg.PrivateKey
 	host.PrivateKeyPath = cfg.PrivateKeyPath
 	host.Arch = cfg.Arch
+	host.AgentSocket = cfg.AgentSocket
+	if cfg.Bastion.Address != "" {
+		host.Bastion = &connector.Bastion{
+			Address:        cfg.Bastion.Address,
+			Port:           cfg.Bastion.Port,
+			User:           cfg.Bastion.User,
+			Password:       cfg.Bastion.Password,
+			PrivateKey:     cfg.Bastion.PrivateKey,
+			PrivateKeyPath: cfg.Bastion.PrivateKeyPath,
+		}
+	}
 	return host
 }
 
+// InternalAddresses returns the internal addresses of the host, the InternalAddress is a comma-separated
+// IPv4/IPv6 pair on the dual-stack cluster. The first one is the primary address, which is used by the components.
+func (h HostCfg) InternalAddresses() []string {
+	return util.SplitAddresses(h.InternalAddress)
+}
+
+// FindHost returns the config of the host with the given name, or nil if it is not found.
+func (cfg *ClusterSpec) FindHost(name string) *HostCfg {
+	for i := range cfg.Hosts {
+		if cfg.Hosts[i].Name == name {
+			return &cfg.Hosts[i]
+		}
+	}
+	return nil
+}
+
+// String returns the taint in the form of key=value:effect, which is accepted by both kubectl and kubelet.
+func (t Taint) String() string {
+	if t.Value == "" {
+		return fmt.Sprintf("%s:%s", t.Key, t.Effect)
+	}
+	return fmt.Sprintf("%s=%s:%s", t.Key, t.Value, t.Effect)
+}
+
+func (t Taint) Validate() error {
+	if t.Key == "" {
+		return errors.New("the key of the taint cannot be empty")
+	}
+	switch t.Effect {
+	case TaintEffectNoSchedule, TaintEffectPreferNoSchedule, TaintEffectNoExecute:
+		return nil
+	default:
+		return errors.Errorf("unsupported effect %q of the taint %s, it should be one of %s, %s and %s",
+			t.Effect, t.Key, TaintEffectNoSchedule, TaintEffectPreferNoSchedule, TaintEffectNoExecute)
+	}
+}
+
 // ClusterIP is used to get the kube-apiserver service address inside the cluster.
 func (cfg *ClusterSpec) ClusterIP() string {
-	return util.ParseIp(cfg.Network.KubeServiceCIDR)[0]
+	cidrs := cfg.Network.ServiceCIDRs()
+	if len(cidrs) == 0 {
+		return ""
+	}
+	ip, _ := util.NthIP(cidrs[0], 1)
+	return ip
 }
 
 // CorednsClusterIP is used to get the coredns service address inside the cluster.
 func (cfg *ClusterSpec) CorednsClusterIP() string {
-	return util.ParseIp(cfg.Network.KubeServiceCIDR)[2]
+	cidrs := cfg.Network.ServiceCIDRs()
+	if len(cidrs) == 0 {
+		return ""
+	}
+	ip, _ := util.NthIP(cidrs[0], 3)
+	return ip
 }
 
 // ClusterDNS is used to get the dns server address inside the cluster.
@@ -357,3 +573,34 @@ func (c ControlPlaneEndpoint) IsInternalLBEnabled() bool {
 	}
 	return false
 }
+
+// IsInternalLBEnabledVip checks if the apiservers are exposed by the virtual IP which is advertised by kube-vip.
+func (c ControlPlaneEndpoint) IsInternalLBEnabledVip() bool {
+	return c.InternalLoadbalancer == Kubevip
+}
+
+// RollingConcurrency converts MaxUnavailable to the concurrency of a rolling task on the given number of hosts.
+// An invalid value falls back to one host at a time.
+func (e ExecutionCfg) RollingConcurrency(hosts int) float64 {
+	if hosts <= 0 {
+		return 1
+	}
+	one := 1 / float64(hosts)
+	v := strings.TrimSpace(e.MaxUnavailable)
+	if strings.HasSuffix(v, "%") {
+		percent, err := strconv.ParseFloat(strings.TrimSuffix(v, "%"), 64)
+		if err != nil || percent <= 0 {
+			return one
+		}
+		// make sure at least one host is in a batch
+		if c := percent / 100; c > one {
+			return c
+		}
+		return one
+	}
+	n, err := strconv.Atoi(v)
+	if err != nil || n <= 0 {
+		return one
+	}
+	return float64(n) / float64(hosts)
+}
@@ -0,0 +1,43 @@
+/*
+ Copyright 2021 The KubeSphere Authors.
+
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+     http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package v1alpha2
+
+import "testing"
+
+func TestSystemValidate(t *testing.T) {
+	tests := []struct {
+		name    string
+		system  System
+		wantErr bool
+	}{
+		{name: "defaults", system: System{SELinux: SELinuxDisabled, Firewall: FirewallDisabled}},
+		{name: "permissive and managed", system: System{SELinux: SELinuxPermissive, Firewall: FirewallManaged}},
+		{name: "keep", system: System{SELinux: SELinuxKeep, Firewall: FirewallKeep, KernelModules: []string{"nf_nat"}}},
+		{name: "invalid selinux", system: System{SELinux: "enforcing", Firewall: FirewallDisabled}, wantErr: true},
+		{name: "invalid firewall", system: System{SELinux: SELinuxDisabled, Firewall: "iptables"}, wantErr: true},
+		{name: "empty kernel module", system: System{SELinux: SELinuxDisabled, Firewall: FirewallDisabled, KernelModules: []string{""}}, wantErr: true},
+		{name: "kernel module with space", system: System{SELinux: SELinuxDisabled, Firewall: FirewallDisabled, KernelModules: []string{"ip_vs; reboot"}}, wantErr: true},
+		{name: "kernel module path", system: System{SELinux: SELinuxDisabled, Firewall: FirewallDisabled, KernelModules: []string{"/tmp/evil.ko"}}, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := tt.system.Validate(); (err != nil) != tt.wantErr {
+				t.Errorf("Validate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
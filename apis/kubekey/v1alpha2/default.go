@@ -20,6 +20,8 @@ import (
 	"fmt"
 	"github.com/kubesphere/kubekey/pkg/core/connector"
 	"github.com/kubesphere/kubekey/pkg/core/util"
+	"github.com/pkg/errors"
+	"net"
 	"os"
 	"strings"
 )
@@ -41,6 +43,7 @@ const (
 	DefaultEtcdPort             = "2379"
 	DefaultDockerVersion        = "20.10.8"
 	DefaultCrictlVersion        = "v1.22.0"
+	DefaultCrioVersion          = "v1.22.0"
 	DefaultKubeVersion          = "v1.21.5"
 	DefaultCalicoVersion        = "v3.20.0"
 	DefaultFlannelVersion       = "v0.12.0"
@@ -54,11 +57,13 @@ const (
 	DefaultHarborVersion        = "v2.4.1"
 	DefaultMaxPods              = 110
 	DefaultNodeCidrMaskSize     = 24
+	DefaultNodeCidrMaskSizeIPv6 = 64
 	DefaultIPIPMode             = "Always"
 	DefaultVXLANMode            = "Never"
 	DefaultVethMTU              = 1440
 	DefaultBackendMode          = "vxlan"
 	DefaultProxyMode            = "ipvs"
+	DefaultMaxUnavailable       = "1"
 	DefaultCrioEndpoint         = "unix:///var/run/crio/crio.sock"
 	DefaultContainerdEndpoint   = "unix:///run/containerd/containerd.sock"
 	DefaultIsulaEndpoint        = "unix:///var/run/isulad.sock"
@@ -85,24 +90,53 @@ const (
 	Isula      = "isula"
 
 	Haproxy = "haproxy"
+	Kubevip = "kube-vip"
+
+	KubeKeyEtcdType  = "kubekey"
+	KubeadmEtcdType  = "kubeadm"
+	ExternalEtcdType = "external"
+
+	TaintEffectNoSchedule       = "NoSchedule"
+	TaintEffectPreferNoSchedule = "PreferNoSchedule"
+	TaintEffectNoExecute        = "NoExecute"
+
+	SELinuxDisabled   = "disabled"
+	SELinuxPermissive = "permissive"
+	SELinuxKeep       = "keep"
+
+	FirewallDisabled = "disabled"
+	FirewallKeep     = "keep"
+	FirewallManaged  = "managed"
 )
 
 func (cfg *ClusterSpec) SetDefaultClusterSpec(incluster bool) (*ClusterSpec, map[string][]*connector.BaseHost, error) {
 	clusterCfg := ClusterSpec{}
 
-	clusterCfg.Hosts = SetDefaultHostsCfg(cfg)
+	clusterCfg.Bastion = cfg.Bastion
+	hostCfg, err := SetDefaultHostsCfg(cfg)
+	if err != nil {
+		return nil, nil, err
+	}
+	clusterCfg.Hosts = hostCfg
 	clusterCfg.RoleGroups = cfg.RoleGroups
+	etcdCfg, err := SetDefaultEtcdCfg(cfg)
+	if err != nil {
+		return nil, nil, err
+	}
+	clusterCfg.Etcd = etcdCfg
 	roleGroups, err := clusterCfg.GroupHosts()
 	if err != nil {
 		return nil, nil, err
 	}
 	clusterCfg.ControlPlaneEndpoint = SetDefaultLBCfg(cfg, roleGroups[Master], incluster)
 	clusterCfg.Network = SetDefaultNetworkCfg(cfg)
-	clusterCfg.System = cfg.System
+	clusterCfg.System = SetDefaultSystemCfg(cfg)
+	clusterCfg.Execution = cfg.Execution
 	clusterCfg.Kubernetes = SetDefaultClusterCfg(cfg)
 	clusterCfg.Registry = cfg.Registry
 	clusterCfg.Addons = cfg.Addons
 	clusterCfg.KubeSphere = cfg.KubeSphere
+	clusterCfg.Checksums = cfg.Checksums
 
 	if cfg.Kubernetes.ClusterName == "" {
 		clusterCfg.Kubernetes.ClusterName = DefaultClusterName
@@ -119,19 +153,78 @@ func (cfg *ClusterSpec) SetDefaultClusterSpec(incluster bool) (*ClusterSpec, map
 	if cfg.Kubernetes.ProxyMode == "" {
 		clusterCfg.Kubernetes.ProxyMode = DefaultProxyMode
 	}
+	if cfg.Kubernetes.NodeCidrMaskSizeIPv6 == 0 {
+		clusterCfg.Kubernetes.NodeCidrMaskSizeIPv6 = DefaultNodeCidrMaskSizeIPv6
+	}
+	if cfg.Execution.MaxUnavailable == "" {
+		clusterCfg.Execution.MaxUnavailable = DefaultMaxUnavailable
+	}
+	// nodelocaldns listens on an IPv4 link-local address, which the pods of the IPv6 single-stack cluster cannot reach.
+	if clusterCfg.Network.IsIPv6Only() && clusterCfg.Kubernetes.Nodelocaldns == nil {
+		disabled := false
+		clusterCfg.Kubernetes.Nodelocaldns = &disabled
+	}
+	if err := clusterCfg.ValidateNetwork(); err != nil {
+		return nil, nil, err
+	}
+	if err := clusterCfg.System.Validate(); err != nil {
+		return nil, nil, err
+	}
 	return &clusterCfg, roleGroups, nil
 }
 
-func SetDefaultHostsCfg(cfg *ClusterSpec) []HostCfg {
+func SetDefaultEtcdCfg(cfg *ClusterSpec) (EtcdCluster, error) {
+	etcdCfg := cfg.Etcd
+	switch etcdCfg.Type {
+	case "":
+		etcdCfg.Type = KubeKeyEtcdType
+	case KubeKeyEtcdType:
+	case KubeadmEtcdType:
+		if cfg.Kubernetes.Type == "k3s" || strings.HasSuffix(cfg.Kubernetes.Version, "-k3s") {
+			return etcdCfg, errors.New("the etcd type kubeadm is not supported by k3s")
+		}
+	case ExternalEtcdType:
+		external := etcdCfg.External
+		if len(external.Endpoints) == 0 {
+			return etcdCfg, errors.New("the endpoints of the external etcd cannot be empty")
+		}
+		if external.CaFile == "" || external.CertFile == "" || external.KeyFile == "" {
+			return etcdCfg, errors.New("the caFile, certFile and keyFile of the external etcd must be set")
+		}
+	default:
+		return etcdCfg, errors.Errorf("unsupported etcd type %s, it should be one of %s, %s and %s", etcdCfg.Type, KubeKeyEtcdType, KubeadmEtcdType, ExternalEtcdType)
+	}
+	return etcdCfg, nil
+}
+
+func SetDefaultSystemCfg(cfg *ClusterSpec) System {
+	systemCfg := cfg.System
+	if systemCfg.SELinux == "" {
+		systemCfg.SELinux = SELinuxDisabled
+	}
+	if systemCfg.Firewall == "" {
+		systemCfg.Firewall = FirewallDisabled
+	}
+	return systemCfg
+}
+
+func SetDefaultHostsCfg(cfg *ClusterSpec) ([]HostCfg, error) {
 	var hostCfg []HostCfg
 	if len(cfg.Hosts) == 0 {
-		return nil
+		return nil, nil
 	}
 	for _, host := range cfg.Hosts {
-		if len(host.Address) == 0 && len(host.InternalAddress) > 0 {
-			host.Address = host.InternalAddress
+		// an internalAddress of only separators, e.g. ",", is a typo rather than an empty one
+		if strings.TrimSpace(host.InternalAddress) != "" && len(host.InternalAddresses()) == 0 {
+			return nil, errors.Errorf("the internalAddress %q of the host %s is invalid", host.InternalAddress, host.Name)
+		}
+		if len(host.Address) == 0 && len(host.InternalAddresses()) == 0 {
+			return nil, errors.Errorf("the address and internalAddress of the host %s cannot be both empty", host.Name)
 		}
-		if len(host.InternalAddress) == 0 && len(host.Address) > 0 {
+		if addrs := host.InternalAddresses(); len(host.Address) == 0 && len(addrs) > 0 {
+			host.Address = addrs[0]
+		}
+		if len(host.InternalAddresses()) == 0 && len(host.Address) > 0 {
 			host.InternalAddress = host.Address
 		}
 		if host.User == "" {
@@ -140,8 +233,20 @@ func SetDefaultHostsCfg(cfg *ClusterSpec) []HostCfg {
 		if host.Port == 0 {
 			host.Port = DefaultSSHPort
 		}
+		if host.Bastion.Address == "" {
+			host.Bastion = cfg.Bastion
+		}
+		if host.Bastion.Address != "" {
+			if host.Bastion.Port == 0 {
+				host.Bastion.Port = DefaultSSHPort
+			}
+			if host.Bastion.PrivateKeyPath != "" && strings.HasPrefix(strings.TrimSpace(host.Bastion.PrivateKeyPath), "~/") {
+				homeDir, _ := util.Home()
+				host.Bastion.PrivateKeyPath = strings.Replace(host.Bastion.PrivateKeyPath, "~/", fmt.Sprintf("%s/", homeDir), 1)
+			}
+		}
 		if host.PrivateKey == "" {
-			if host.Password == "" && host.PrivateKeyPath == "" {
+			if host.Password == "" && host.PrivateKeyPath == "" && host.AgentSocket == "" {
 				host.PrivateKeyPath = "~/.ssh/id_rsa"
 			}
 			if host.PrivateKeyPath != "" && strings.HasPrefix(strings.TrimSpace(host.PrivateKeyPath), "~/") {
@@ -155,13 +260,13 @@ func SetDefaultHostsCfg(cfg *ClusterSpec) []HostCfg {
 		}
 		hostCfg = append(hostCfg, host)
 	}
-	return hostCfg
+	return hostCfg, nil
 }
 
 func SetDefaultLBCfg(cfg *ClusterSpec, masterGroup []*connector.BaseHost, incluster bool) ControlPlaneEndpoint {
 	if !incluster {
 		//The detection is not an HA environment, and the address at LB does not need input
-		if len(masterGroup) == 1 && cfg.ControlPlaneEndpoint.Address != "" {
+		if len(masterGroup) == 1 && cfg.ControlPlaneEndpoint.Address != "" && !cfg.ControlPlaneEndpoint.IsInternalLBEnabledVip() {
 			fmt.Println("When the environment is not HA, the LB address does not need to be entered, so delete the corresponding value.")
 			os.Exit(0)
 		}
@@ -177,6 +282,42 @@ func SetDefaultLBCfg(cfg *ClusterSpec, masterGroup []*connector.BaseHost, inclus
 			fmt.Println("You cannot set up the internal load balancer and the LB address at the same time.")
 			os.Exit(0)
 		}
+
+		// The LB address is the virtual IP which is advertised by kube-vip
+		if cfg.ControlPlaneEndpoint.IsInternalLBEnabledVip() {
+			if net.ParseIP(cfg.ControlPlaneEndpoint.Address) == nil {
+				fmt.Println("The LB address must be set to a free IP address as the virtual IP when the internal load balancer is kube-vip.")
+				os.Exit(0)
+			}
+			vipIPv6 := util.IsIPv6(cfg.ControlPlaneEndpoint.Address)
+			for _, host := range cfg.Hosts {
+				addrs := host.InternalAddresses()
+				if len(addrs) == 0 {
+					addrs = []string{host.Address}
+				}
+				for _, addr := range append(addrs, host.Address) {
+					if addr == cfg.ControlPlaneEndpoint.Address {
+						fmt.Printf("The virtual IP %s of kube-vip is already used by the host %s.\n", cfg.ControlPlaneEndpoint.Address, host.Name)
+						os.Exit(0)
+					}
+				}
+				// The VIP is advertised on the interface of the internal address in its IP family, and reached by the nodes through it.
+				sameFamily := false
+				for _, addr := range addrs {
+					if util.IsIPv6(addr) == vipIPv6 {
+						sameFamily = true
+					}
+				}
+				if !sameFamily {
+					fmt.Printf("The host %s has no internal address in the IP family of the virtual IP %s of kube-vip.\n", host.Name, cfg.ControlPlaneEndpoint.Address)
+					os.Exit(0)
+				}
+			}
+			if cfg.Kubernetes.Type == "k3s" || strings.HasSuffix(cfg.Kubernetes.Version, "-k3s") {
+				fmt.Println("The internal load balancer kube-vip is not supported by k3s.")
+				os.Exit(0)
+			}
+		}
 	}
 
 	if cfg.ControlPlaneEndpoint.Address == "" || cfg.ControlPlaneEndpoint.Address == "127.0.0.1" {
@@ -16,8 +16,25 @@
 
 package v1alpha2
 
+import (
+	"net"
+
+	"github.com/kubesphere/kubekey/pkg/core/util"
+	"github.com/pkg/errors"
+	versionutil "k8s.io/apimachinery/pkg/util/version"
+)
+
+const (
+	// DualStackMinVersion is the first version which enables the dual-stack by default.
+	DualStackMinVersion = "v1.21.0"
+	// IPv6MinVersion is the first version which uses the calico manifest with the IPv6 pool.
+	IPv6MinVersion = "v1.16.0"
+)
+
 type NetworkConfig struct {
-	Plugin          string     `yaml:"plugin" json:"plugin,omitempty"`
+	Plugin string `yaml:"plugin" json:"plugin,omitempty"`
+	// KubePodsCIDR and KubeServiceCIDR are either a single IPv4 or IPv6 CIDR, or a comma-separated IPv4/IPv6 pair
+	// for the dual-stack cluster. The family of the first CIDR is the primary IP family of the cluster.
 	KubePodsCIDR    string     `yaml:"kubePodsCIDR" json:"kubePodsCIDR,omitempty"`
 	KubeServiceCIDR string     `yaml:"kubeServiceCIDR" json:"kubeServiceCIDR,omitempty"`
 	Calico          CalicoCfg  `yaml:"calico" json:"calico,omitempty"`
@@ -63,3 +80,120 @@ func (n *NetworkConfig) EnableMultusCNI() bool {
 	}
 	return *n.MultusCNI.Enabled
 }
+
+// PodCIDRs returns the pod CIDRs, the primary one comes first.
+func (n *NetworkConfig) PodCIDRs() []string {
+	return util.SplitAddresses(n.KubePodsCIDR)
+}
+
+// ServiceCIDRs returns the service CIDRs, the primary one comes first.
+func (n *NetworkConfig) ServiceCIDRs() []string {
+	return util.SplitAddresses(n.KubeServiceCIDR)
+}
+
+// IsDualStack returns true if both the IPv4 and IPv6 pod CIDRs are set.
+func (n *NetworkConfig) IsDualStack() bool {
+	return len(n.PodCIDRs()) == 2
+}
+
+// IsIPv6Only returns true if the cluster is IPv6 single-stack.
+func (n *NetworkConfig) IsIPv6Only() bool {
+	cidrs := n.PodCIDRs()
+	return len(cidrs) == 1 && util.IsIPv6(cidrs[0])
+}
+
+// PodCIDR returns the pod CIDR of the IP family, or an empty string if the family is not used.
+func (n *NetworkConfig) PodCIDR(ipv6 bool) string {
+	return cidrOfFamily(n.PodCIDRs(), ipv6)
+}
+
+// ServiceCIDR returns the service CIDR of the IP family, or an empty string if the family is not used.
+func (n *NetworkConfig) ServiceCIDR(ipv6 bool) string {
+	return cidrOfFamily(n.ServiceCIDRs(), ipv6)
+}
+
+func cidrOfFamily(cidrs []string, ipv6 bool) string {
+	for _, cidr := range cidrs {
+		if util.IsIPv6(cidr) == ipv6 {
+			return cidr
+		}
+	}
+	return ""
+}
+
+// ValidateNetwork checks that the pod CIDRs, the service CIDRs and the internal addresses of the hosts
+// use the IP families consistently.
+func (cfg *ClusterSpec) ValidateNetwork() error {
+	pods, services := cfg.Network.PodCIDRs(), cfg.Network.ServiceCIDRs()
+	if err := validateCIDRs("kubePodsCIDR", pods); err != nil {
+		return err
+	}
+	if err := validateCIDRs("kubeServiceCIDR", services); err != nil {
+		return err
+	}
+	if len(pods) != len(services) || util.IsIPv6(pods[0]) != util.IsIPv6(services[0]) {
+		return errors.Errorf("the kubePodsCIDR %s and the kubeServiceCIDR %s must use the same IP families in the same order",
+			cfg.Network.KubePodsCIDR, cfg.Network.KubeServiceCIDR)
+	}
+	primaryIPv6 := util.IsIPv6(pods[0])
+
+	if cfg.Network.IsDualStack() || primaryIPv6 {
+		switch cfg.Network.Plugin {
+		case "calico", "none", "":
+		default:
+			return errors.Errorf("the network plugin %s is not supported by the IPv6 or dual-stack cluster, use calico instead", cfg.Network.Plugin)
+		}
+	}
+	if cfg.Network.IsDualStack() || primaryIPv6 {
+		minVersion := IPv6MinVersion
+		if cfg.Network.IsDualStack() {
+			minVersion = DualStackMinVersion
+		}
+		v, err := versionutil.ParseGeneric(cfg.Kubernetes.Version)
+		if err != nil {
+			return errors.Wrapf(err, "invalid kubernetes version %s", cfg.Kubernetes.Version)
+		}
+		if v.LessThan(versionutil.MustParseGeneric(minVersion)) {
+			return errors.Errorf("the IPv6 or dual-stack cluster %s requires kubernetes %s or later", cfg.Network.KubePodsCIDR, minVersion)
+		}
+	}
+	if cfg.Network.IsIPv6Only() && cfg.Kubernetes.EnableNodelocaldns() {
+		return errors.New("nodelocaldns listens on an IPv4 link-local address, it cannot be enabled on the IPv6 single-stack cluster")
+	}
+
+	for _, host := range cfg.Hosts {
+		addrs := host.InternalAddresses()
+		if len(addrs) == 0 {
+			continue
+		}
+		for _, addr := range addrs {
+			if net.ParseIP(addr) == nil {
+				return errors.Errorf("invalid internal address %s of the host %s", addr, host.Name)
+			}
+		}
+		if util.IsIPv6(addrs[0]) != primaryIPv6 {
+			return errors.Errorf("the internal address %s of the host %s does not match the primary IP family of the kubePodsCIDR %s",
+				addrs[0], host.Name, cfg.Network.KubePodsCIDR)
+		}
+		if len(addrs) > 2 || (len(addrs) == 2 && (!cfg.Network.IsDualStack() || util.IsIPv6(addrs[0]) == util.IsIPv6(addrs[1]))) {
+			return errors.Errorf("the internal address %s of the host %s should be a single address, or an IPv4/IPv6 pair on the dual-stack cluster",
+				host.InternalAddress, host.Name)
+		}
+	}
+	return nil
+}
+
+func validateCIDRs(name string, cidrs []string) error {
+	if len(cidrs) == 0 || len(cidrs) > 2 {
+		return errors.Errorf("the %s should be a single CIDR or an IPv4/IPv6 pair", name)
+	}
+	for _, cidr := range cidrs {
+		if _, _, err := net.ParseCIDR(cidr); err != nil {
+			return errors.Wrapf(err, "invalid %s", name)
+		}
+	}
+	if len(cidrs) == 2 && util.IsIPv6(cidrs[0]) == util.IsIPv6(cidrs[1]) {
+		return errors.Errorf("the two CIDRs of the %s must be an IPv4/IPv6 pair", name)
+	}
+	return nil
+}
@@ -0,0 +1,180 @@
+/*
+ Copyright 2021 The KubeSphere Authors.
+
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+     http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package v1alpha2
+
+import (
+	"testing"
+
+	"github.com/kubesphere/kubekey/pkg/core/connector"
+)
+
+func networkSpec(version, plugin, pods, services string, internalAddresses ...string) *ClusterSpec {
+	cfg := &ClusterSpec{
+		Kubernetes: Kubernetes{Version: version},
+		Network:    NetworkConfig{Plugin: plugin, KubePodsCIDR: pods, KubeServiceCIDR: services},
+	}
+	disabled := false
+	cfg.Kubernetes.Nodelocaldns = &disabled
+	for i, addr := range internalAddresses {
+		cfg.Hosts = append(cfg.Hosts, HostCfg{Name: string(rune('a' + i)), InternalAddress: addr})
+	}
+	return cfg
+}
+
+func TestValidateNetwork(t *testing.T) {
+	tests := []struct {
+		name    string
+		cfg     *ClusterSpec
+		wantErr bool
+	}{
+		{
+			name: "ipv4",
+			cfg:  networkSpec("v1.21.5", "calico", "10.233.64.0/18", "10.233.0.0/18", "172.16.0.2"),
+		},
+		{
+			name: "dual-stack",
+			cfg: networkSpec("v1.22.10", "calico", "10.233.64.0/18,fd85:ee78:d8a6:8607::1:0/112",
+				"10.233.0.0/18,fd85:ee78:d8a6:8607::1000/116", "172.16.0.2,fd00:172:16::2", "172.16.0.3"),
+		},
+		{
+			name: "ipv6",
+			cfg:  networkSpec("v1.20.4", "calico", "fd85:ee78:d8a6:8607::1:0/112", "fd85:ee78:d8a6:8607::1000/116", "fd00:172:16::2"),
+		},
+		{
+			name:    "invalid cidr",
+			cfg:     networkSpec("v1.21.5", "calico", "10.233.64.0/33", "10.233.0.0/18"),
+			wantErr: true,
+		},
+		{
+			name:    "two ipv4 cidrs",
+			cfg:     networkSpec("v1.22.10", "calico", "10.233.64.0/18,10.234.64.0/18", "10.233.0.0/18,10.234.0.0/18"),
+			wantErr: true,
+		},
+		{
+			name:    "different families of pods and services",
+			cfg:     networkSpec("v1.22.10", "calico", "10.233.64.0/18,fd85:ee78:d8a6:8607::1:0/112", "10.233.0.0/18"),
+			wantErr: true,
+		},
+		{
+			name: "different orders of pods and services",
+			cfg: networkSpec("v1.22.10", "calico", "10.233.64.0/18,fd85:ee78:d8a6:8607::1:0/112",
+				"fd85:ee78:d8a6:8607::1000/116,10.233.0.0/18"),
+			wantErr: true,
+		},
+		{
+			name: "dual-stack with flannel",
+			cfg: networkSpec("v1.22.10", "flannel", "10.233.64.0/18,fd85:ee78:d8a6:8607::1:0/112",
+				"10.233.0.0/18,fd85:ee78:d8a6:8607::1000/116"),
+			wantErr: true,
+		},
+		{
+			name: "dual-stack before v1.21",
+			cfg: networkSpec("v1.20.4", "calico", "10.233.64.0/18,fd85:ee78:d8a6:8607::1:0/112",
+				"10.233.0.0/18,fd85:ee78:d8a6:8607::1000/116"),
+			wantErr: true,
+		},
+		{
+			name:    "host of the other family",
+			cfg:     networkSpec("v1.21.5", "calico", "10.233.64.0/18", "10.233.0.0/18", "fd00:172:16::2"),
+			wantErr: true,
+		},
+		{
+			name:    "two addresses on the single-stack cluster",
+			cfg:     networkSpec("v1.21.5", "calico", "10.233.64.0/18", "10.233.0.0/18", "172.16.0.2,fd00:172:16::2"),
+			wantErr: true,
+		},
+		{
+			name:    "invalid host address",
+			cfg:     networkSpec("v1.21.5", "calico", "10.233.64.0/18", "10.233.0.0/18", "node1"),
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := tt.cfg.ValidateNetwork(); (err != nil) != tt.wantErr {
+				t.Errorf("ValidateNetwork() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+
+	cfg := networkSpec("v1.21.5", "calico", "fd85:ee78:d8a6:8607::1:0/112", "fd85:ee78:d8a6:8607::1000/116")
+	cfg.Kubernetes.Nodelocaldns = nil
+	if err := cfg.ValidateNetwork(); err == nil {
+		t.Error("nodelocaldns should not be enabled on the IPv6 single-stack cluster")
+	}
+}
+
+func TestClusterIP(t *testing.T) {
+	for services, want := range map[string]string{
+		"10.233.0.0/18":                               "10.233.0.1,10.233.0.3",
+		"fd85:ee78:d8a6:8607::1000/116":               "fd85:ee78:d8a6:8607::1001,fd85:ee78:d8a6:8607::1003",
+		"10.233.0.0/18,fd85:ee78:d8a6:8607::1000/116": "10.233.0.1,10.233.0.3",
+		"fd85:ee78:d8a6:8607::1000/116,10.233.0.0/18": "fd85:ee78:d8a6:8607::1001,fd85:ee78:d8a6:8607::1003",
+		"": ",",
+	} {
+		cfg := &ClusterSpec{Network: NetworkConfig{KubeServiceCIDR: services}}
+		if got := cfg.ClusterIP() + "," + cfg.CorednsClusterIP(); got != want {
+			t.Errorf("ClusterIP(), CorednsClusterIP() of %q = %s, want %s", services, got, want)
+		}
+	}
+}
+
+func TestSetDefaultHostsCfg(t *testing.T) {
+	cfg := &ClusterSpec{Hosts: []HostCfg{
+		{Name: "node1", Address: "192.168.0.2"},
+		{Name: "node2", Address: "192.168.0.3", InternalAddress: " 172.16.0.3 , "},
+		{Name: "node3", InternalAddress: "172.16.0.4,fd00:172:16::4"},
+	}}
+	hosts, err := SetDefaultHostsCfg(cfg)
+	if err != nil {
+		t.Fatalf("SetDefaultHostsCfg() error = %v", err)
+	}
+	for i, want := range [][2]string{
+		{"192.168.0.2", "192.168.0.2"},
+		{"192.168.0.3", " 172.16.0.3 , "},
+		{"172.16.0.4", "172.16.0.4,fd00:172:16::4"},
+	} {
+		if hosts[i].Address != want[0] || hosts[i].InternalAddress != want[1] {
+			t.Errorf("host %s = %s/%s, want %s/%s", hosts[i].Name, hosts[i].Address, hosts[i].InternalAddress, want[0], want[1])
+		}
+	}
+
+	for _, host := range []HostCfg{
+		{Name: "node1", Address: "192.168.0.2", InternalAddress: ","},
+		{Name: "node1", InternalAddress: " , "},
+		{Name: "node1"},
+	} {
+		if _, err := SetDefaultHostsCfg(&ClusterSpec{Hosts: []HostCfg{host}}); err == nil {
+			t.Errorf("SetDefaultHostsCfg() of %s/%q should fail", host.Address, host.InternalAddress)
+		}
+	}
+}
+
+func TestSetDefaultLBCfgKubevip(t *testing.T) {
+	cfg := &ClusterSpec{
+		ControlPlaneEndpoint: ControlPlaneEndpoint{InternalLoadbalancer: Kubevip, Address: "172.16.0.10"},
+		Hosts: []HostCfg{
+			{Name: "node1", Address: "172.16.0.2"},
+			{Name: "node2", Address: "172.16.0.3", InternalAddress: "172.16.0.3,fd00:172:16::3"},
+		},
+	}
+	masters := []*connector.BaseHost{{Name: "node1", InternalAddress: "172.16.0.2"}}
+	// a host with only the address set is checked against the VIP without panicking.
+	if lb := SetDefaultLBCfg(cfg, masters, false); lb.Address != "172.16.0.10" {
+		t.Errorf("the VIP should be kept, got %s", lb.Address)
+	}
+}
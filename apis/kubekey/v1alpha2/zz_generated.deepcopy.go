@@ -41,6 +41,31 @@ func (in *Addon) DeepCopy() *Addon {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *BastionCfg) DeepCopyInto(out *BastionCfg) {
+	*out = *in
+	if in.PasswordFrom != nil {
+		in, out := &in.PasswordFrom, &out.PasswordFrom
+		*out = new(ValueSource)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.PrivateKeyFrom != nil {
+		in, out := &in.PrivateKeyFrom, &out.PrivateKeyFrom
+		*out = new(ValueSource)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new BastionCfg.
+func (in *BastionCfg) DeepCopy() *BastionCfg {
+	if in == nil {
+		return nil
+	}
+	out := new(BastionCfg)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *CNI) DeepCopyInto(out *CNI) {
 	*out = *in
@@ -91,6 +116,21 @@ func (in *Chart) DeepCopy() *Chart {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ChecksumsConfig) DeepCopyInto(out *ChecksumsConfig) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ChecksumsConfig.
+func (in *ChecksumsConfig) DeepCopy() *ChecksumsConfig {
+	if in == nil {
+		return nil
+	}
+	out := new(ChecksumsConfig)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *Cluster) DeepCopyInto(out *Cluster) {
 	*out = *in
@@ -160,6 +200,7 @@ func (in *ClusterSpec) DeepCopyInto(out *ClusterSpec) {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
+	in.Bastion.DeepCopyInto(&out.Bastion)
 	if in.RoleGroups != nil {
 		in, out := &in.RoleGroups, &out.RoleGroups
 		*out = make(map[string][]string, len(*in))
@@ -177,6 +218,8 @@ func (in *ClusterSpec) DeepCopyInto(out *ClusterSpec) {
 	}
 	out.ControlPlaneEndpoint = in.ControlPlaneEndpoint
 	in.System.DeepCopyInto(&out.System)
+	out.Execution = in.Execution
+	in.Etcd.DeepCopyInto(&out.Etcd)
 	in.Kubernetes.DeepCopyInto(&out.Kubernetes)
 	in.Network.DeepCopyInto(&out.Network)
 	in.Registry.DeepCopyInto(&out.Registry)
@@ -188,6 +231,7 @@ func (in *ClusterSpec) DeepCopyInto(out *ClusterSpec) {
 		}
 	}
 	out.KubeSphere = in.KubeSphere
+	out.Checksums = in.Checksums
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ClusterSpec.
@@ -402,6 +446,22 @@ func (in *ETCD) DeepCopy() *ETCD {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *EtcdCluster) DeepCopyInto(out *EtcdCluster) {
+	*out = *in
+	in.External.DeepCopyInto(&out.External)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new EtcdCluster.
+func (in *EtcdCluster) DeepCopy() *EtcdCluster {
+	if in == nil {
+		return nil
+	}
+	out := new(EtcdCluster)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *Event) DeepCopyInto(out *Event) {
 	*out = *in
@@ -417,6 +477,21 @@ func (in *Event) DeepCopy() *Event {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ExecutionCfg) DeepCopyInto(out *ExecutionCfg) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ExecutionCfg.
+func (in *ExecutionCfg) DeepCopy() *ExecutionCfg {
+	if in == nil {
+		return nil
+	}
+	out := new(ExecutionCfg)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ExternalEtcd) DeepCopyInto(out *ExternalEtcd) {
 	*out = *in
@@ -485,6 +560,17 @@ func (in *Helm) DeepCopy() *Helm {
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *HostCfg) DeepCopyInto(out *HostCfg) {
 	*out = *in
+	if in.PasswordFrom != nil {
+		in, out := &in.PasswordFrom, &out.PasswordFrom
+		*out = new(ValueSource)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.PrivateKeyFrom != nil {
+		in, out := &in.PrivateKeyFrom, &out.PrivateKeyFrom
+		*out = new(ValueSource)
+		(*in).DeepCopyInto(*out)
+	}
+	in.Bastion.DeepCopyInto(&out.Bastion)
 	if in.Labels != nil {
 		in, out := &in.Labels, &out.Labels
 		*out = make(map[string]string, len(*in))
@@ -492,6 +578,11 @@ func (in *HostCfg) DeepCopyInto(out *HostCfg) {
 			(*out)[key] = val
 		}
 	}
+	if in.Taints != nil {
+		in, out := &in.Taints, &out.Taints
+		*out = make([]Taint, len(*in))
+		copy(*out, *in)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new HostCfg.
@@ -968,6 +1059,36 @@ func (in *Repository) DeepCopy() *Repository {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SecretKeySelector) DeepCopyInto(out *SecretKeySelector) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SecretKeySelector.
+func (in *SecretKeySelector) DeepCopy() *SecretKeySelector {
+	if in == nil {
+		return nil
+	}
+	out := new(SecretKeySelector)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SopsFileSelector) DeepCopyInto(out *SopsFileSelector) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SopsFileSelector.
+func (in *SopsFileSelector) DeepCopy() *SopsFileSelector {
+	if in == nil {
+		return nil
+	}
+	out := new(SopsFileSelector)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *Sources) DeepCopyInto(out *Sources) {
 	*out = *in
@@ -993,6 +1114,25 @@ func (in *System) DeepCopyInto(out *System) {
 		*out = make([]string, len(*in))
 		copy(*out, *in)
 	}
+	if in.Sysctls != nil {
+		in, out := &in.Sysctls, &out.Sysctls
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.KernelModules != nil {
+		in, out := &in.KernelModules, &out.KernelModules
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Ulimits != nil {
+		in, out := &in.Ulimits, &out.Ulimits
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new System.
@@ -1005,6 +1145,46 @@ func (in *System) DeepCopy() *System {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *Taint) DeepCopyInto(out *Taint) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Taint.
+func (in *Taint) DeepCopy() *Taint {
+	if in == nil {
+		return nil
+	}
+	out := new(Taint)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ValueSource) DeepCopyInto(out *ValueSource) {
+	*out = *in
+	if in.SopsFileRef != nil {
+		in, out := &in.SopsFileRef, &out.SopsFileRef
+		*out = new(SopsFileSelector)
+		**out = **in
+	}
+	if in.SecretKeyRef != nil {
+		in, out := &in.SecretKeyRef, &out.SecretKeyRef
+		*out = new(SecretKeySelector)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ValueSource.
+func (in *ValueSource) DeepCopy() *ValueSource {
+	if in == nil {
+		return nil
+	}
+	out := new(ValueSource)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *Yaml) DeepCopyInto(out *Yaml) {
 	*out = *in
@@ -20,6 +20,7 @@ import (
 	"github.com/kubesphere/kubekey/cmd/ctl/options"
 	"github.com/kubesphere/kubekey/cmd/ctl/util"
 	"github.com/kubesphere/kubekey/pkg/common"
+	"github.com/kubesphere/kubekey/pkg/core/event"
 	"github.com/kubesphere/kubekey/pkg/pipelines"
 	"github.com/spf13/cobra"
 )
@@ -32,6 +33,11 @@ type AddNodesOptions struct {
 	DownloadCmd      string
 	Artifact         string
 	InstallPackages  bool
+	Resume           bool
+	DryRun           bool
+	MaxParallel      int
+	Output           string
+	OutputFile       string
 }
 
 func NewAddNodesOptions() *AddNodesOptions {
@@ -65,6 +71,8 @@ func (o *AddNodesOptions) Complete(_ *cobra.Command, _ []string) error {
 }
 
 func (o *AddNodesOptions) Run() error {
+	defer event.Close()
+
 	arg := common.Argument{
 		FilePath:         o.ClusterCfgFile,
 		KsEnable:         false,
@@ -73,9 +81,15 @@ func (o *AddNodesOptions) Run() error {
 		SkipConfirmCheck: o.CommonOptions.SkipConfirmCheck,
 		SkipPullImages:   o.SkipPullImages,
 		InCluster:        o.CommonOptions.InCluster,
+		ClusterName:      o.CommonOptions.ClusterName,
 		ContainerManager: o.ContainerManager,
 		Artifact:         o.Artifact,
 		InstallPackages:  o.InstallPackages,
+		Resume:           o.Resume,
+		DryRun:           o.DryRun,
+		MaxParallel:      o.MaxParallel,
+		Output:           o.Output,
+		OutputFile:       o.OutputFile,
 	}
 	return pipelines.AddNodes(arg, o.DownloadCmd)
 }
@@ -88,4 +102,9 @@ func (o *AddNodesOptions) AddFlags(cmd *cobra.Command) {
 		`The user defined command to download the necessary binary files. The first param '%s' is output path, the second param '%s', is the URL`)
 	cmd.Flags().StringVarP(&o.Artifact, "artifact", "a", "", "Path to a KubeKey artifact")
 	cmd.Flags().BoolVarP(&o.InstallPackages, "with-packages", "", false, "install operation system packages by artifact")
+	cmd.Flags().BoolVarP(&o.Resume, "resume", "", false, "Resume from the checkpoint of the last failed run, skip the modules which have been completed")
+	cmd.Flags().BoolVarP(&o.DryRun, "dry-run", "", false, "Print the execution plan without changing anything on the hosts")
+	cmd.Flags().IntVarP(&o.MaxParallel, "max-parallel", "", 0, "The max number of hosts which a task runs on at the same time, it overrides the execution.maxParallel in the config file")
+	cmd.Flags().StringVarP(&o.Output, "output", "o", common.OutputText, "Output format of the progress, one of text|json. The json format writes the events to stdout as JSON lines")
+	cmd.Flags().StringVarP(&o.OutputFile, "output-file", "", "", "Path to a file which the progress events are appended to as JSON lines")
 }
@@ -0,0 +1,46 @@
+/*
+ Copyright 2021 The KubeSphere Authors.
+
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+     http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package apply
+
+import (
+	"github.com/kubesphere/kubekey/cmd/ctl/options"
+	"github.com/spf13/cobra"
+)
+
+type ApplyOptions struct {
+	CommonOptions *options.CommonOptions
+}
+
+func NewApplyOptions() *ApplyOptions {
+	return &ApplyOptions{
+		CommonOptions: options.NewCommonOptions(),
+	}
+}
+
+// NewCmdApply creates a new apply command
+func NewCmdApply() *cobra.Command {
+	o := NewApplyOptions()
+	cmd := &cobra.Command{
+		Use:   "apply",
+		Short: "Apply the configuration file to a running cluster",
+	}
+
+	o.CommonOptions.AddCommonFlag(cmd)
+
+	cmd.AddCommand(NewCmdApplyNodes())
+	return cmd
+}
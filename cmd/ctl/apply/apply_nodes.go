@@ -0,0 +1,70 @@
+/*
+ Copyright 2021 The KubeSphere Authors.
+
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+     http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package apply
+
+import (
+	"github.com/kubesphere/kubekey/cmd/ctl/options"
+	"github.com/kubesphere/kubekey/cmd/ctl/util"
+	"github.com/kubesphere/kubekey/pkg/common"
+	"github.com/kubesphere/kubekey/pkg/pipelines"
+	"github.com/spf13/cobra"
+)
+
+type ApplyNodesOptions struct {
+	CommonOptions  *options.CommonOptions
+	ClusterCfgFile string
+	Check          bool
+}
+
+func NewApplyNodesOptions() *ApplyNodesOptions {
+	return &ApplyNodesOptions{
+		CommonOptions: options.NewCommonOptions(),
+	}
+}
+
+// NewCmdApplyNodes creates a new apply nodes command
+func NewCmdApplyNodes() *cobra.Command {
+	o := NewApplyNodesOptions()
+	cmd := &cobra.Command{
+		Use:   "nodes",
+		Short: "Apply the labels and taints of the hosts in the configuration file to the kubernetes nodes",
+		Long: `Apply the labels and taints of the hosts in the configuration file to the kubernetes nodes.
+The labels and taints which were applied by kubekey but have been removed from the configuration file are removed from the nodes,
+the others are left as they are. With --check, the drift is reported and the command exits with a non-zero code if any is found.`,
+		Run: func(cmd *cobra.Command, args []string) {
+			util.CheckErr(o.Run())
+		},
+	}
+
+	o.CommonOptions.AddCommonFlag(cmd)
+	o.AddFlags(cmd)
+	return cmd
+}
+
+func (o *ApplyNodesOptions) Run() error {
+	arg := common.Argument{
+		FilePath:         o.ClusterCfgFile,
+		Debug:            o.CommonOptions.Verbose,
+		SkipConfirmCheck: o.CommonOptions.SkipConfirmCheck,
+	}
+	return pipelines.ApplyNodes(arg, o.Check)
+}
+
+func (o *ApplyNodesOptions) AddFlags(cmd *cobra.Command) {
+	cmd.Flags().StringVarP(&o.ClusterCfgFile, "filename", "f", "", "Path to a configuration file")
+	cmd.Flags().BoolVarP(&o.Check, "check", "", false, "Report the drift of the labels and taints without changing the nodes")
+}
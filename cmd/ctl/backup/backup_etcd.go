@@ -0,0 +1,87 @@
+/*
+Copyright 2020 The KubeSphere Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package backup
+
+import (
+	"github.com/kubesphere/kubekey/cmd/ctl/options"
+	"github.com/kubesphere/kubekey/cmd/ctl/util"
+	"github.com/kubesphere/kubekey/pkg/common"
+	"github.com/kubesphere/kubekey/pkg/core/event"
+	"github.com/kubesphere/kubekey/pkg/pipelines"
+	"github.com/spf13/cobra"
+)
+
+type BackupETCDOptions struct {
+	CommonOptions  *options.CommonOptions
+	ClusterCfgFile string
+	SnapshotDir    string
+	List           bool
+	DryRun         bool
+	MaxParallel    int
+	Output         string
+	OutputFile     string
+}
+
+func NewBackupETCDOptions() *BackupETCDOptions {
+	return &BackupETCDOptions{
+		CommonOptions: options.NewCommonOptions(),
+	}
+}
+
+// NewCmdBackupETCD creates a new backup etcd command
+func NewCmdBackupETCD() *cobra.Command {
+	o := NewBackupETCDOptions()
+	cmd := &cobra.Command{
+		Use:   "etcd",
+		Short: "Take a snapshot of etcd and fetch it to local",
+		Run: func(cmd *cobra.Command, args []string) {
+			util.CheckErr(o.Run())
+		},
+	}
+
+	o.CommonOptions.AddCommonFlag(cmd)
+	o.AddFlags(cmd)
+	return cmd
+}
+
+func (o *BackupETCDOptions) Run() error {
+	defer event.Close()
+
+	arg := common.Argument{
+		FilePath:    o.ClusterCfgFile,
+		Debug:       o.CommonOptions.Verbose,
+		SnapshotDir: o.SnapshotDir,
+		DryRun:      o.DryRun,
+		MaxParallel: o.MaxParallel,
+		Output:      o.Output,
+		OutputFile:  o.OutputFile,
+	}
+	if o.List {
+		return pipelines.ListETCDBackups(arg)
+	}
+	return pipelines.BackupETCD(arg)
+}
+
+func (o *BackupETCDOptions) AddFlags(cmd *cobra.Command) {
+	cmd.Flags().StringVarP(&o.ClusterCfgFile, "filename", "f", "", "Path to a configuration file")
+	cmd.Flags().StringVarP(&o.SnapshotDir, "snapshot-dir", "", "", "Local dir which the etcd snapshots are saved to, defaults to ./kubekey/etcd-backups")
+	cmd.Flags().BoolVarP(&o.List, "list", "", false, "List the etcd snapshots in the snapshot dir instead of taking a new one")
+	cmd.Flags().BoolVarP(&o.DryRun, "dry-run", "", false, "Print the execution plan without changing anything on the hosts")
+	cmd.Flags().IntVarP(&o.MaxParallel, "max-parallel", "", 0, "The max number of hosts which a task runs on at the same time, it overrides the execution.maxParallel in the config file")
+	cmd.Flags().StringVarP(&o.Output, "output", "o", common.OutputText, "Output format of the progress, one of text|json. The json format writes the events to stdout as JSON lines")
+	cmd.Flags().StringVarP(&o.OutputFile, "output-file", "", "", "Path to a file which the progress events are appended to as JSON lines")
+}
@@ -20,6 +20,7 @@ import (
 	"github.com/kubesphere/kubekey/cmd/ctl/options"
 	"github.com/kubesphere/kubekey/cmd/ctl/util"
 	"github.com/kubesphere/kubekey/pkg/common"
+	"github.com/kubesphere/kubekey/pkg/core/event"
 	"github.com/kubesphere/kubekey/pkg/pipelines"
 	"github.com/spf13/cobra"
 )
@@ -27,6 +28,10 @@ import (
 type CertRenewOptions struct {
 	CommonOptions  *options.CommonOptions
 	ClusterCfgFile string
+	DryRun         bool
+	MaxParallel    int
+	Output         string
+	OutputFile     string
 }
 
 func NewCertRenewOptions() *CertRenewOptions {
@@ -52,13 +57,23 @@ func NewCmdCertRenew() *cobra.Command {
 }
 
 func (o *CertRenewOptions) Run() error {
+	defer event.Close()
+
 	arg := common.Argument{
-		FilePath: o.ClusterCfgFile,
-		Debug:    o.CommonOptions.Verbose,
+		FilePath:    o.ClusterCfgFile,
+		Debug:       o.CommonOptions.Verbose,
+		DryRun:      o.DryRun,
+		MaxParallel: o.MaxParallel,
+		Output:      o.Output,
+		OutputFile:  o.OutputFile,
 	}
 	return pipelines.RenewCerts(arg)
 }
 
 func (o *CertRenewOptions) AddFlags(cmd *cobra.Command) {
 	cmd.Flags().StringVarP(&o.ClusterCfgFile, "filename", "f", "", "Path to a configuration file")
+	cmd.Flags().BoolVarP(&o.DryRun, "dry-run", "", false, "Print the execution plan without changing anything on the hosts")
+	cmd.Flags().IntVarP(&o.MaxParallel, "max-parallel", "", 0, "The max number of hosts which a task runs on at the same time, it overrides the execution.maxParallel in the config file")
+	cmd.Flags().StringVarP(&o.Output, "output", "o", common.OutputText, "Output format of the progress, one of text|json. The json format writes the events to stdout as JSON lines")
+	cmd.Flags().StringVarP(&o.OutputFile, "output-file", "", "", "Path to a file which the progress events are appended to as JSON lines")
 }
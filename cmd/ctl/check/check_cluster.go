@@ -0,0 +1,71 @@
+/*
+ Copyright 2021 The KubeSphere Authors.
+
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+     http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package check
+
+import (
+	"github.com/kubesphere/kubekey/cmd/ctl/options"
+	"github.com/kubesphere/kubekey/cmd/ctl/util"
+	"github.com/kubesphere/kubekey/pkg/common"
+	"github.com/kubesphere/kubekey/pkg/pipelines"
+	"github.com/spf13/cobra"
+)
+
+type CheckClusterOptions struct {
+	CommonOptions  *options.CommonOptions
+	ClusterCfgFile string
+	MaxParallel    int
+	Output         string
+}
+
+func NewCheckClusterOptions() *CheckClusterOptions {
+	return &CheckClusterOptions{
+		CommonOptions: options.NewCommonOptions(),
+	}
+}
+
+// NewCmdCheckCluster creates a new check cluster command
+func NewCmdCheckCluster() *cobra.Command {
+	o := NewCheckClusterOptions()
+	cmd := &cobra.Command{
+		Use:   "cluster",
+		Short: "Check the nodes, etcd, certificates and apiserver of a running cluster",
+		Long: `Check the nodes, etcd, certificates and apiserver of a running cluster, it changes nothing on the hosts.
+The command exits with a non-zero code if any error is found.`,
+		Run: func(cmd *cobra.Command, args []string) {
+			util.CheckErr(o.Run())
+		},
+	}
+
+	o.CommonOptions.AddCommonFlag(cmd)
+	o.AddFlags(cmd)
+	return cmd
+}
+
+func (o *CheckClusterOptions) Run() error {
+	arg := common.Argument{
+		FilePath:    o.ClusterCfgFile,
+		Debug:       o.CommonOptions.Verbose,
+		MaxParallel: o.MaxParallel,
+	}
+	return pipelines.CheckCluster(arg, o.Output)
+}
+
+func (o *CheckClusterOptions) AddFlags(cmd *cobra.Command) {
+	cmd.Flags().StringVarP(&o.ClusterCfgFile, "filename", "f", "", "Path to a configuration file")
+	cmd.Flags().IntVarP(&o.MaxParallel, "max-parallel", "", 0, "The max number of hosts which a task runs on at the same time, it overrides the execution.maxParallel in the config file")
+	cmd.Flags().StringVarP(&o.Output, "output", "o", common.OutputText, "Output format of the report, one of text|json")
+}
@@ -21,6 +21,7 @@ import (
 	"github.com/kubesphere/kubekey/cmd/ctl/options"
 	"github.com/kubesphere/kubekey/cmd/ctl/util"
 	"github.com/kubesphere/kubekey/pkg/common"
+	"github.com/kubesphere/kubekey/pkg/core/event"
 	"github.com/kubesphere/kubekey/pkg/pipelines"
 	"github.com/kubesphere/kubekey/pkg/version/kubernetes"
 	"github.com/kubesphere/kubekey/pkg/version/kubesphere"
@@ -43,6 +44,11 @@ type CreateClusterOptions struct {
 	Artifact         string
 	InstallPackages  bool
 	CertificatesDir  string
+	Resume           bool
+	DryRun           bool
+	MaxParallel      int
+	Output           string
+	OutputFile       string
 
 	localStorageChanged bool
 }
@@ -104,6 +110,8 @@ func (o *CreateClusterOptions) Validate(_ *cobra.Command, _ []string) error {
 }
 
 func (o *CreateClusterOptions) Run() error {
+	defer event.Close()
+
 	arg := common.Argument{
 		FilePath:          o.ClusterCfgFile,
 		KubernetesVersion: o.Kubernetes,
@@ -112,6 +120,7 @@ func (o *CreateClusterOptions) Run() error {
 		SkipPullImages:    o.SkipPullImages,
 		SKipPushImages:    o.SkipPushImages,
 		InCluster:         o.CommonOptions.InCluster,
+		ClusterName:       o.CommonOptions.ClusterName,
 		Debug:             o.CommonOptions.Verbose,
 		IgnoreErr:         o.CommonOptions.IgnoreErr,
 		SkipConfirmCheck:  o.CommonOptions.SkipConfirmCheck,
@@ -119,6 +128,11 @@ func (o *CreateClusterOptions) Run() error {
 		Artifact:          o.Artifact,
 		InstallPackages:   o.InstallPackages,
 		CertificatesDir:   o.CertificatesDir,
+		Resume:            o.Resume,
+		DryRun:            o.DryRun,
+		MaxParallel:       o.MaxParallel,
+		Output:            o.Output,
+		OutputFile:        o.OutputFile,
 	}
 
 	if o.localStorageChanged {
@@ -142,6 +156,11 @@ func (o *CreateClusterOptions) AddFlags(cmd *cobra.Command) {
 		`The user defined command to download the necessary binary files. The first param '%s' is output path, the second param '%s', is the URL`)
 	cmd.Flags().StringVarP(&o.Artifact, "artifact", "a", "", "Path to a KubeKey artifact")
 	cmd.Flags().BoolVarP(&o.InstallPackages, "with-packages", "", false, "install operation system packages by artifact")
+	cmd.Flags().BoolVarP(&o.Resume, "resume", "", false, "Resume from the checkpoint of the last failed run, skip the modules which have been completed")
+	cmd.Flags().BoolVarP(&o.DryRun, "dry-run", "", false, "Print the execution plan without changing anything on the hosts")
+	cmd.Flags().IntVarP(&o.MaxParallel, "max-parallel", "", 0, "The max number of hosts which a task runs on at the same time, it overrides the execution.maxParallel in the config file")
+	cmd.Flags().StringVarP(&o.Output, "output", "o", common.OutputText, "Output format of the progress, one of text|json. The json format writes the events to stdout as JSON lines")
+	cmd.Flags().StringVarP(&o.OutputFile, "output-file", "", "", "Path to a file which the progress events are appended to as JSON lines")
 }
 
 func completionSetting(cmd *cobra.Command) (err error) {
@@ -22,6 +22,7 @@ import (
 	"github.com/kubesphere/kubekey/cmd/ctl/options"
 	"github.com/kubesphere/kubekey/cmd/ctl/util"
 	"github.com/kubesphere/kubekey/pkg/common"
+	"github.com/kubesphere/kubekey/pkg/core/event"
 	"github.com/kubesphere/kubekey/pkg/pipelines"
 	"github.com/pkg/errors"
 	"github.com/spf13/cobra"
@@ -31,6 +32,10 @@ type DeleteNodeOptions struct {
 	CommonOptions  *options.CommonOptions
 	ClusterCfgFile string
 	nodeName       string
+	DryRun         bool
+	MaxParallel    int
+	Output         string
+	OutputFile     string
 }
 
 func NewDeleteNodeOptions() *DeleteNodeOptions {
@@ -70,15 +75,25 @@ func (o *DeleteNodeOptions) Validate() error {
 }
 
 func (o *DeleteNodeOptions) Run() error {
+	defer event.Close()
+
 	arg := common.Argument{
-		FilePath: o.ClusterCfgFile,
-		Debug:    o.CommonOptions.Verbose,
-		NodeName: o.nodeName,
+		FilePath:         o.ClusterCfgFile,
+		Debug:            o.CommonOptions.Verbose,
+		SkipConfirmCheck: o.CommonOptions.SkipConfirmCheck,
+		NodeName:         o.nodeName,
+		DryRun:           o.DryRun,
+		MaxParallel:      o.MaxParallel,
+		Output:           o.Output,
+		OutputFile:       o.OutputFile,
 	}
 	return pipelines.DeleteNode(arg)
 }
 
 func (o *DeleteNodeOptions) AddFlags(cmd *cobra.Command) {
 	cmd.Flags().StringVarP(&o.ClusterCfgFile, "filename", "f", "", "Path to a configuration file")
-
+	cmd.Flags().BoolVarP(&o.DryRun, "dry-run", "", false, "Print the execution plan without changing anything on the hosts")
+	cmd.Flags().IntVarP(&o.MaxParallel, "max-parallel", "", 0, "The max number of hosts which a task runs on at the same time, it overrides the execution.maxParallel in the config file")
+	cmd.Flags().StringVarP(&o.Output, "output", "o", common.OutputText, "Output format of the progress, one of text|json. The json format writes the events to stdout as JSON lines")
+	cmd.Flags().StringVarP(&o.OutputFile, "output-file", "", "", "Path to a file which the progress events are appended to as JSON lines")
 }
@@ -20,6 +20,7 @@ import (
 	"github.com/kubesphere/kubekey/cmd/ctl/options"
 	"github.com/kubesphere/kubekey/cmd/ctl/util"
 	"github.com/kubesphere/kubekey/pkg/common"
+	"github.com/kubesphere/kubekey/pkg/core/event"
 	"github.com/kubesphere/kubekey/pkg/pipelines"
 	"github.com/spf13/cobra"
 )
@@ -29,6 +30,10 @@ type InitRegistryOptions struct {
 	ClusterCfgFile string
 	DownloadCmd    string
 	Artifact       string
+	DryRun         bool
+	MaxParallel    int
+	Output         string
+	OutputFile     string
 }
 
 func NewInitRegistryOptions() *InitRegistryOptions {
@@ -59,10 +64,16 @@ func (o *InitRegistryOptions) Complete(_ *cobra.Command, _ []string) error {
 }
 
 func (o *InitRegistryOptions) Run() error {
+	defer event.Close()
+
 	arg := common.Argument{
-		FilePath: o.ClusterCfgFile,
-		Debug:    o.CommonOptions.Verbose,
-		Artifact: o.Artifact,
+		FilePath:    o.ClusterCfgFile,
+		Debug:       o.CommonOptions.Verbose,
+		Artifact:    o.Artifact,
+		DryRun:      o.DryRun,
+		MaxParallel: o.MaxParallel,
+		Output:      o.Output,
+		OutputFile:  o.OutputFile,
 	}
 	return pipelines.InitRegistry(arg, o.DownloadCmd)
 }
@@ -72,4 +83,8 @@ func (o *InitRegistryOptions) AddFlags(cmd *cobra.Command) {
 	cmd.Flags().StringVarP(&o.DownloadCmd, "download-cmd", "", "curl -L -o %s %s",
 		`The user defined command to download the necessary files. The first param '%s' is output path, the second param '%s', is the URL`)
 	cmd.Flags().StringVarP(&o.Artifact, "artifact", "a", "", "Path to a KubeKey artifact")
+	cmd.Flags().BoolVarP(&o.DryRun, "dry-run", "", false, "Print the execution plan without changing anything on the hosts")
+	cmd.Flags().IntVarP(&o.MaxParallel, "max-parallel", "", 0, "The max number of hosts which a task runs on at the same time, it overrides the execution.maxParallel in the config file")
+	cmd.Flags().StringVarP(&o.Output, "output", "o", common.OutputText, "Output format of the progress, one of text|json. The json format writes the events to stdout as JSON lines")
+	cmd.Flags().StringVarP(&o.OutputFile, "output-file", "", "", "Path to a file which the progress events are appended to as JSON lines")
 }
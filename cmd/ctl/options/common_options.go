@@ -22,6 +22,7 @@ import (
 
 type CommonOptions struct {
 	InCluster        bool
+	ClusterName      string
 	Verbose          bool
 	SkipConfirmCheck bool
 	IgnoreErr        bool
@@ -33,6 +34,7 @@ func NewCommonOptions() *CommonOptions {
 
 func (o *CommonOptions) AddCommonFlag(cmd *cobra.Command) {
 	cmd.Flags().BoolVar(&o.InCluster, "in-cluster", false, "Running inside the cluster")
+	cmd.Flags().StringVar(&o.ClusterName, "cluster-name", "", "The name of the Cluster whose config is loaded from the ConfigMap when running inside the cluster without a configuration file")
 	cmd.Flags().BoolVar(&o.Verbose, "debug", false, "Print detailed information")
 	cmd.Flags().BoolVarP(&o.SkipConfirmCheck, "yes", "y", false, "Skip confirm check")
 	cmd.Flags().BoolVar(&o.IgnoreErr, "ignore-err", false, "Ignore the error message, remove the host which reported error and force to continue")
@@ -0,0 +1,97 @@
+/*
+Copyright 2020 The KubeSphere Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package restore
+
+import (
+	"os"
+
+	"github.com/kubesphere/kubekey/cmd/ctl/options"
+	"github.com/kubesphere/kubekey/cmd/ctl/util"
+	"github.com/kubesphere/kubekey/pkg/common"
+	"github.com/kubesphere/kubekey/pkg/core/event"
+	"github.com/kubesphere/kubekey/pkg/pipelines"
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+)
+
+type RestoreETCDOptions struct {
+	CommonOptions  *options.CommonOptions
+	ClusterCfgFile string
+	Snapshot       string
+	DryRun         bool
+	MaxParallel    int
+	Output         string
+	OutputFile     string
+}
+
+func NewRestoreETCDOptions() *RestoreETCDOptions {
+	return &RestoreETCDOptions{
+		CommonOptions: options.NewCommonOptions(),
+	}
+}
+
+// NewCmdRestoreETCD creates a new restore etcd command
+func NewCmdRestoreETCD() *cobra.Command {
+	o := NewRestoreETCDOptions()
+	cmd := &cobra.Command{
+		Use:   "etcd",
+		Short: "Restore etcd from a snapshot and restart the control plane",
+		Run: func(cmd *cobra.Command, args []string) {
+			util.CheckErr(o.Validate())
+			util.CheckErr(o.Run())
+		},
+	}
+
+	o.CommonOptions.AddCommonFlag(cmd)
+	o.AddFlags(cmd)
+	return cmd
+}
+
+func (o *RestoreETCDOptions) Validate() error {
+	if o.Snapshot == "" {
+		return errors.New("the etcd snapshot file must be specified by --snapshot")
+	}
+	if _, err := os.Stat(o.Snapshot); err != nil {
+		return errors.Wrapf(err, "failed to stat the etcd snapshot file %s", o.Snapshot)
+	}
+	return nil
+}
+
+func (o *RestoreETCDOptions) Run() error {
+	defer event.Close()
+
+	arg := common.Argument{
+		FilePath:         o.ClusterCfgFile,
+		Debug:            o.CommonOptions.Verbose,
+		SkipConfirmCheck: o.CommonOptions.SkipConfirmCheck,
+		Snapshot:         o.Snapshot,
+		DryRun:           o.DryRun,
+		MaxParallel:      o.MaxParallel,
+		Output:           o.Output,
+		OutputFile:       o.OutputFile,
+	}
+	return pipelines.RestoreETCD(arg)
+}
+
+func (o *RestoreETCDOptions) AddFlags(cmd *cobra.Command) {
+	cmd.Flags().StringVarP(&o.ClusterCfgFile, "filename", "f", "", "Path to a configuration file")
+	cmd.Flags().StringVarP(&o.Snapshot, "snapshot", "", "", "Path to a local etcd snapshot file, it can be taken by 'kk backup etcd'")
+	cmd.Flags().BoolVarP(&o.DryRun, "dry-run", "", false, "Print the execution plan without changing anything on the hosts")
+	cmd.Flags().IntVarP(&o.MaxParallel, "max-parallel", "", 0, "The max number of hosts which a task runs on at the same time, it overrides the execution.maxParallel in the config file")
+	cmd.Flags().StringVarP(&o.Output, "output", "o", common.OutputText, "Output format of the progress, one of text|json. The json format writes the events to stdout as JSON lines")
+	cmd.Flags().StringVarP(&o.OutputFile, "output-file", "", "", "Path to a file which the progress events are appended to as JSON lines")
+}
@@ -19,14 +19,19 @@ package ctl
 import (
 	"fmt"
 	"github.com/kubesphere/kubekey/cmd/ctl/add"
+	"github.com/kubesphere/kubekey/cmd/ctl/apply"
 	"github.com/kubesphere/kubekey/cmd/ctl/artifact"
+	"github.com/kubesphere/kubekey/cmd/ctl/backup"
 	"github.com/kubesphere/kubekey/cmd/ctl/cert"
+	"github.com/kubesphere/kubekey/cmd/ctl/check"
 	"github.com/kubesphere/kubekey/cmd/ctl/completion"
 	"github.com/kubesphere/kubekey/cmd/ctl/create"
 	"github.com/kubesphere/kubekey/cmd/ctl/delete"
 	initOs "github.com/kubesphere/kubekey/cmd/ctl/init"
 	"github.com/kubesphere/kubekey/cmd/ctl/options"
 	"github.com/kubesphere/kubekey/cmd/ctl/plugin"
+	"github.com/kubesphere/kubekey/cmd/ctl/restore"
+	"github.com/kubesphere/kubekey/cmd/ctl/supportbundle"
 	"github.com/kubesphere/kubekey/cmd/ctl/upgrade"
 	"github.com/kubesphere/kubekey/cmd/ctl/version"
 	"github.com/spf13/cobra"
@@ -107,9 +112,14 @@ func NewKubeKeyCommand(o KubeKeyOptions) *cobra.Command {
 	cmds.AddCommand(create.NewCmdCreate())
 	cmds.AddCommand(delete.NewCmdDelete())
 	cmds.AddCommand(add.NewCmdAdd())
+	cmds.AddCommand(apply.NewCmdApply())
 	cmds.AddCommand(upgrade.NewCmdUpgrade())
 	cmds.AddCommand(cert.NewCmdCerts())
 	cmds.AddCommand(artifact.NewCmdArtifact())
+	cmds.AddCommand(backup.NewCmdBackup())
+	cmds.AddCommand(restore.NewCmdRestore())
+	cmds.AddCommand(check.NewCmdCheck())
+	cmds.AddCommand(supportbundle.NewCmdSupportBundle())
 
 	cmds.AddCommand(plugin.NewCmdPlugin(o.IOStreams))
 
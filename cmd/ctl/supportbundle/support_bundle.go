@@ -0,0 +1,75 @@
+/*
+ Copyright 2021 The KubeSphere Authors.
+
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+     http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package supportbundle
+
+import (
+	"github.com/kubesphere/kubekey/cmd/ctl/options"
+	"github.com/kubesphere/kubekey/cmd/ctl/util"
+	"github.com/kubesphere/kubekey/pkg/common"
+	"github.com/kubesphere/kubekey/pkg/pipelines"
+	"github.com/spf13/cobra"
+	"time"
+)
+
+type SupportBundleOptions struct {
+	CommonOptions  *options.CommonOptions
+	ClusterCfgFile string
+	Since          time.Duration
+	BundleFile     string
+	MaxParallel    int
+}
+
+func NewSupportBundleOptions() *SupportBundleOptions {
+	return &SupportBundleOptions{
+		CommonOptions: options.NewCommonOptions(),
+	}
+}
+
+// NewCmdSupportBundle creates a new support-bundle command
+func NewCmdSupportBundle() *cobra.Command {
+	o := NewSupportBundleOptions()
+	cmd := &cobra.Command{
+		Use:   "support-bundle",
+		Short: "Collect the logs, configs and status of all nodes into a tarball",
+		Long: `Collect the journald logs and status of the kubelet, container runtime and etcd services, the configs
+generated by kubekey, the system info of all nodes and the logs of kk into a tarball. The secrets in the
+configs and logs are redacted, and the private keys are never collected.`,
+		Run: func(cmd *cobra.Command, args []string) {
+			util.CheckErr(o.Run())
+		},
+	}
+
+	o.CommonOptions.AddCommonFlag(cmd)
+	o.AddFlags(cmd)
+	return cmd
+}
+
+func (o *SupportBundleOptions) Run() error {
+	arg := common.Argument{
+		FilePath:    o.ClusterCfgFile,
+		Debug:       o.CommonOptions.Verbose,
+		MaxParallel: o.MaxParallel,
+	}
+	return pipelines.SupportBundle(arg, o.Since, o.BundleFile)
+}
+
+func (o *SupportBundleOptions) AddFlags(cmd *cobra.Command) {
+	cmd.Flags().StringVarP(&o.ClusterCfgFile, "filename", "f", "", "Path to a configuration file")
+	cmd.Flags().DurationVarP(&o.Since, "since", "", 24*time.Hour, "Only collect the journald logs newer than a relative duration like 2h")
+	cmd.Flags().StringVarP(&o.BundleFile, "bundle", "", "", "Path of the tarball, defaults to ./support-bundle-<timestamp>.tar.gz")
+	cmd.Flags().IntVarP(&o.MaxParallel, "max-parallel", "", 0, "The max number of hosts which a task runs on at the same time, it overrides the execution.maxParallel in the config file")
+}
@@ -21,6 +21,7 @@ import (
 	"github.com/kubesphere/kubekey/cmd/ctl/options"
 	"github.com/kubesphere/kubekey/cmd/ctl/util"
 	"github.com/kubesphere/kubekey/pkg/common"
+	"github.com/kubesphere/kubekey/pkg/core/event"
 	"github.com/kubesphere/kubekey/pkg/pipelines"
 	"github.com/kubesphere/kubekey/pkg/version/kubernetes"
 	"github.com/kubesphere/kubekey/pkg/version/kubesphere"
@@ -37,6 +38,11 @@ type UpgradeOptions struct {
 	SkipPullImages   bool
 	DownloadCmd      string
 	Artifact         string
+	Resume           bool
+	DryRun           bool
+	MaxParallel      int
+	Output           string
+	OutputFile       string
 }
 
 func NewUpgradeOptions() *UpgradeOptions {
@@ -77,6 +83,8 @@ func (o *UpgradeOptions) Complete(cmd *cobra.Command, args []string) error {
 }
 
 func (o *UpgradeOptions) Run() error {
+	defer event.Close()
+
 	arg := common.Argument{
 		FilePath:          o.ClusterCfgFile,
 		KubernetesVersion: o.Kubernetes,
@@ -86,6 +94,11 @@ func (o *UpgradeOptions) Run() error {
 		Debug:             o.CommonOptions.Verbose,
 		SkipConfirmCheck:  o.CommonOptions.SkipConfirmCheck,
 		Artifact:          o.Artifact,
+		Resume:            o.Resume,
+		DryRun:            o.DryRun,
+		MaxParallel:       o.MaxParallel,
+		Output:            o.Output,
+		OutputFile:        o.OutputFile,
 	}
 	return pipelines.UpgradeCluster(arg, o.DownloadCmd)
 }
@@ -98,6 +111,11 @@ func (o *UpgradeOptions) AddFlags(cmd *cobra.Command) {
 	cmd.Flags().StringVarP(&o.DownloadCmd, "download-cmd", "", "curl -L -o %s %s",
 		`The user defined command to download the necessary binary files. The first param '%s' is output path, the second param '%s', is the URL`)
 	cmd.Flags().StringVarP(&o.Artifact, "artifact", "a", "", "Path to a KubeKey artifact")
+	cmd.Flags().BoolVarP(&o.Resume, "resume", "", false, "Resume from the checkpoint of the last failed run, skip the modules which have been completed")
+	cmd.Flags().BoolVarP(&o.DryRun, "dry-run", "", false, "Print the execution plan without changing anything on the hosts")
+	cmd.Flags().IntVarP(&o.MaxParallel, "max-parallel", "", 0, "The max number of hosts which a task runs on at the same time, it overrides the execution.maxParallel in the config file")
+	cmd.Flags().StringVarP(&o.Output, "output", "o", common.OutputText, "Output format of the progress, one of text|json. The json format writes the events to stdout as JSON lines")
+	cmd.Flags().StringVarP(&o.OutputFile, "output-file", "", "", "Path to a file which the progress events are appended to as JSON lines")
 }
 
 func completionSetting(cmd *cobra.Command) (err error) {
@@ -386,10 +386,10 @@ func (r *ClusterReconciler) jobForCluster(c *kubekeyv1alpha2.Cluster, action str
 	)
 	if action == CreateCluster {
 		name = fmt.Sprintf("%s-create-cluster", c.Name)
-		args = []string{"create", "cluster", "-f", "/home/kubekey/config/cluster.yaml", "-y", "--in-cluster", "true"}
+		args = []string{"create", "cluster", "--cluster-name", c.Name, "-y", "--in-cluster", "true"}
 	} else if action == AddNodes {
 		name = fmt.Sprintf("%s-add-nodes", c.Name)
-		args = []string{"add", "nodes", "-f", "/home/kubekey/config/cluster.yaml", "-y", "--in-cluster", "true", "--ignore-err", "true"}
+		args = []string{"add", "nodes", "--cluster-name", c.Name, "-y", "--in-cluster", "true", "--ignore-err", "true"}
 	}
 
 	podlist := &corev1.PodList{}
@@ -432,20 +432,6 @@ func (r *ClusterReconciler) jobForCluster(c *kubekeyv1alpha2.Cluster, action str
 				ObjectMeta: metav1.ObjectMeta{},
 				Spec: corev1.PodSpec{
 					Volumes: []corev1.Volume{
-						{
-							Name: "config",
-							VolumeSource: corev1.VolumeSource{
-								ConfigMap: &corev1.ConfigMapVolumeSource{
-									LocalObjectReference: corev1.LocalObjectReference{
-										Name: c.Name,
-									},
-									Items: []corev1.KeyToPath{{
-										Key:  "cluster.yaml",
-										Path: "cluster.yaml",
-									}},
-								},
-							},
-						},
 						{
 							Name: "kube-binaries",
 							VolumeSource: corev1.VolumeSource{
@@ -481,10 +467,6 @@ func (r *ClusterReconciler) jobForCluster(c *kubekeyv1alpha2.Cluster, action str
 						Command:         []string{"/home/kubekey/kk"},
 						Args:            args,
 						VolumeMounts: []corev1.VolumeMount{
-							{
-								Name:      "config",
-								MountPath: "/home/kubekey/config",
-							},
 							{
 								Name:      "kube-binaries",
 								MountPath: "/home/kubekey/kubekey",
@@ -30,6 +30,7 @@ import (
 	"io/ioutil"
 	versionutil "k8s.io/apimachinery/pkg/util/version"
 	"os"
+	"regexp"
 	"sort"
 	"strings"
 
@@ -82,23 +83,7 @@ func CreateManifest(arg common.Argument, name string) error {
 			}
 		}
 
-		// todo: for now, the cases only have ubuntu, centos. Ant it need to check all linux distribution.
-		var (
-			id, version string
-		)
-		osImageArr := strings.Split(node.Status.NodeInfo.OSImage, " ")
-		switch strings.ToLower(osImageArr[0]) {
-		case "ubuntu":
-			id = "ubuntu"
-			v := strings.Split(osImageArr[1], ".")
-			version = fmt.Sprintf("%s.%s", v[0], v[1])
-		case "centos":
-			id = "centos"
-			version = osImageArr[2]
-		default:
-			id = strings.ToLower(osImageArr[0])
-			version = "Didn't get the os version. Please edit it manually."
-		}
+		id, version := osIDAndVersion(node.Status.NodeInfo.OSImage)
 
 		osObj := kubekeyv1alpha2.OperationSystem{
 			Arch:    node.Status.NodeInfo.Architecture,
@@ -179,6 +164,55 @@ func CreateManifest(arg common.Argument, name string) error {
 	return nil
 }
 
+var (
+	// osImages maps the OS image reported by the kubelet, i.e. the PRETTY_NAME of /etc/os-release, to the ID of the os.
+	osImages = []struct {
+		prefix string
+		id     string
+	}{
+		{"ubuntu", "ubuntu"},
+		{"debian", "debian"},
+		{"centos", "centos"},
+		{"red hat enterprise linux", "rhel"},
+		{"rocky linux", "rocky"},
+		{"almalinux", "almalinux"},
+		{"openeuler", "openeuler"},
+		{"kylin", "kylin"},
+		{"uniontech os", "uos"},
+		{"uos", "uos"},
+	}
+	osImageVersion = regexp.MustCompile(`\b[vV]?\d+(\.\d+)*\b`)
+)
+
+// osIDAndVersion returns the ID and the VERSION_ID of the os running the node, they are used to match the iso
+// of the os on the node.
+func osIDAndVersion(osImage string) (string, string) {
+	image := strings.ToLower(osImage)
+	for _, o := range osImages {
+		if !strings.HasPrefix(image, o.prefix) {
+			continue
+		}
+
+		version := osImageVersion.FindString(osImage)
+		if version == "" {
+			break
+		}
+		// the VERSION_ID of ubuntu only has the major and the minor version, e.g. 20.04 of 20.04.3 LTS.
+		if o.id == "ubuntu" {
+			if v := strings.Split(version, "."); len(v) > 2 {
+				version = strings.Join(v[:2], ".")
+			}
+		}
+		return o.id, version
+	}
+
+	id := ""
+	if fields := strings.Fields(image); len(fields) > 0 {
+		id = fields[0]
+	}
+	return id, "Didn't get the os version. Please edit it manually."
+}
+
 func checkFileExists(fileName string) {
 	if util.IsExist(fileName) {
 		reader := bufio.NewReader(os.Stdin)
@@ -0,0 +1,46 @@
+/*
+ Copyright 2021 The KubeSphere Authors.
+
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+     http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package artifact
+
+import "testing"
+
+func TestOsIDAndVersion(t *testing.T) {
+	tests := []struct {
+		osImage string
+		id      string
+		version string
+	}{
+		{"Ubuntu 20.04.3 LTS", "ubuntu", "20.04"},
+		{"Debian GNU/Linux 11 (bullseye)", "debian", "11"},
+		{"CentOS Linux 7 (Core)", "centos", "7"},
+		{"Red Hat Enterprise Linux 8.6 (Ootpa)", "rhel", "8.6"},
+		{"Rocky Linux 8.6 (Green Obsidian)", "rocky", "8.6"},
+		{"AlmaLinux 9.0 (Emerald Puma)", "almalinux", "9.0"},
+		{"openEuler 22.03 (LTS-SP1)", "openeuler", "22.03"},
+		{"Kylin Linux Advanced Server V10 (Sword)", "kylin", "V10"},
+		{"UnionTech OS Server 20", "uos", "20"},
+		{"Arch Linux", "arch", "Didn't get the os version. Please edit it manually."},
+	}
+	for _, tt := range tests {
+		t.Run(tt.osImage, func(t *testing.T) {
+			id, version := osIDAndVersion(tt.osImage)
+			if id != tt.id || version != tt.version {
+				t.Errorf("osIDAndVersion() = %s, %s, want %s, %s", id, version, tt.id, tt.version)
+			}
+		})
+	}
+}
@@ -251,12 +251,14 @@ func (l *LocalCopy) Execute(runtime connector.Runtime) error {
 			continue
 		}
 
-		dir := filepath.Join(runtime.GetWorkDir(), common.Artifact, "repository", sys.Arch, sys.Id, sys.Version)
+		// the nodes look up the iso by the lowercase ID of /etc/os-release, e.g. openeuler for openEuler.
+		id := strings.ToLower(sys.Id)
+		dir := filepath.Join(runtime.GetWorkDir(), common.Artifact, "repository", sys.Arch, id, sys.Version)
 		if err := coreutil.Mkdir(dir); err != nil {
 			return errors.Wrapf(errors.WithStack(err), "mkdir %s failed", dir)
 		}
 
-		path := filepath.Join(dir, fmt.Sprintf("%s-%s-%s.iso", sys.Id, sys.Version, sys.Arch))
+		path := filepath.Join(dir, fmt.Sprintf("%s-%s-%s.iso", id, sys.Version, sys.Arch))
 		if err := exec.Command("/bin/sh", "-c", fmt.Sprintf("sudo cp -f %s %s", sys.Repository.Iso.LocalPath, path)).Run(); err != nil {
 			return errors.Wrapf(errors.WithStack(err), "copy %s to %s failed", sys.Repository.Iso.LocalPath, path)
 		}
@@ -37,8 +37,13 @@ func K3sFilesDownloadHTTP(kubeConf *common.KubeConf, path, version, arch string,
 	k3s := files.NewKubeBinary("k3s", arch, version, path, kubeConf.Arg.DownloadCommand)
 
 	binaries := []*files.KubeBinary{k3s, helm, kubecni, etcd}
+	sources, err := checksumSources(kubeConf)
+	if err != nil {
+		return err
+	}
 	binariesMap := make(map[string]*files.KubeBinary)
 	for _, binary := range binaries {
+		binary.ChecksumSources = sources
 		if err := binary.CreateBaseDir(); err != nil {
 			return errors.Wrapf(errors.WithStack(err), "create file %s base dir failed", binary.FileName)
 		}
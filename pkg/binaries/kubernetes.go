@@ -41,8 +41,17 @@ func K8sFilesDownloadHTTP(kubeConf *common.KubeConf, path, version, arch string,
 	crictl := files.NewKubeBinary("crictl", arch, kubekeyapiv1alpha2.DefaultCrictlVersion, path, kubeConf.Arg.DownloadCommand)
 
 	binaries := []*files.KubeBinary{kubeadm, kubelet, kubectl, helm, kubecni, docker, crictl, etcd}
+	if kubeConf.Cluster.Kubernetes.ContainerManager == common.Crio {
+		crio := files.NewKubeBinary("crio", arch, kubekeyapiv1alpha2.DefaultCrioVersion, path, kubeConf.Arg.DownloadCommand)
+		binaries = append(binaries, crio)
+	}
+	sources, err := checksumSources(kubeConf)
+	if err != nil {
+		return err
+	}
 	binariesMap := make(map[string]*files.KubeBinary)
 	for _, binary := range binaries {
+		binary.ChecksumSources = sources
 		if err := binary.CreateBaseDir(); err != nil {
 			return errors.Wrapf(errors.WithStack(err), "create file %s base dir failed", binary.FileName)
 		}
@@ -97,6 +106,11 @@ func KubernetesArtifactBinariesDownload(manifest *common.ArtifactManifest, path,
 	dockerArr := make([]*files.KubeBinary, 0, 0)
 	dockerVersionMap := make(map[string]struct{})
 	for _, c := range m.Components.ContainerRuntimes {
+		if c.Type == common.Crio {
+			crio := files.NewKubeBinary("crio", arch, c.Version, path, manifest.Arg.DownloadCommand)
+			binaries = append(binaries, crio)
+			continue
+		}
 		var dockerVersion string
 		if c.Type == common.Docker {
 			dockerVersion = c.Version
@@ -138,3 +152,9 @@ func KubernetesArtifactBinariesDownload(manifest *common.ArtifactManifest, path,
 
 	return nil
 }
+
+// checksumSources returns the checksum sources configured in the cluster config.
+func checksumSources(kubeConf *common.KubeConf) ([]files.ChecksumSource, error) {
+	c := kubeConf.Cluster.Checksums
+	return files.NewChecksumSources(c.File, !c.DisableUpstream)
+}
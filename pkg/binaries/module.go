@@ -27,6 +27,10 @@ type NodeBinariesModule struct {
 	common.KubeModule
 }
 
+func (n *NodeBinariesModule) IsAlwaysRun() bool {
+	return true
+}
+
 func (n *NodeBinariesModule) Init() {
 	n.Name = "NodeBinariesModule"
 	n.Desc = "Download installation binaries"
@@ -46,6 +50,10 @@ type K3sNodeBinariesModule struct {
 	common.KubeModule
 }
 
+func (k *K3sNodeBinariesModule) IsAlwaysRun() bool {
+	return true
+}
+
 func (k *K3sNodeBinariesModule) Init() {
 	k.Name = "K3sNodeBinariesModule"
 	k.Desc = "Download installation binaries"
@@ -44,8 +44,13 @@ func RegistryPackageDownloadHTTP(kubeConf *common.KubeConf, path, arch string, p
 		binaries = []*files.KubeBinary{registry}
 	}
 
+	sources, err := checksumSources(kubeConf)
+	if err != nil {
+		return err
+	}
 	binariesMap := make(map[string]*files.KubeBinary)
 	for _, binary := range binaries {
+		binary.ChecksumSources = sources
 		if err := binary.CreateBaseDir(); err != nil {
 			return errors.Wrapf(errors.WithStack(err), "create file %s base dir failed", binary.FileName)
 		}
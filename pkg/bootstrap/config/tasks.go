@@ -17,13 +17,17 @@
 package config
 
 import (
+	"bytes"
 	"fmt"
 	"github.com/kubesphere/kubekey/pkg/common"
 	"github.com/kubesphere/kubekey/pkg/core/connector"
 	"github.com/pkg/errors"
-	"os/exec"
+	"gopkg.in/yaml.v3"
+	"io/ioutil"
 	"path/filepath"
+	"regexp"
 	"strconv"
+	"strings"
 	"unicode"
 )
 
@@ -32,60 +36,183 @@ type ModifyConfig struct {
 }
 
 func (m *ModifyConfig) Execute(runtime connector.Runtime) error {
-	fp, _ := filepath.Abs(m.KubeConf.Arg.FilePath)
-	cmd0 := fmt.Sprintf("cat %s | grep %s | wc -l", fp, m.KubeConf.Arg.NodeName)
-	nodeNameNum, err0 := exec.Command("/bin/sh", "-c", cmd0).CombinedOutput()
-	if err0 != nil {
-		return errors.Wrap(err0, "Failed to get node num")
-	}
-
-	host := &connector.BaseHost{Name: m.KubeConf.Arg.NodeName}
-	if string(nodeNameNum) == "2\n" {
-		cmd := fmt.Sprintf("sed -i /%s/d %s", m.KubeConf.Arg.NodeName, fp)
-		_ = exec.Command("/bin/sh", "-c", cmd).Run()
-		runtime.DeleteHost(host)
-	} else if string(nodeNameNum) == "1\n" {
-		cmd := fmt.Sprintf("sed -i /%s/d %s", m.KubeConf.Arg.NodeName, fp)
-		_ = exec.Command("/bin/sh", "-c", cmd).Run()
-		runtime.DeleteHost(host)
-
-		var newNodeName []string
-		for i := 0; i < len(runtime.GetHostsByRole(common.Worker)); i++ {
-			name := runtime.GetHostsByRole(common.Worker)[i].GetName()
-			if m.KubeConf.Arg.NodeName == name {
-				continue
-			} else {
-				newNodeName = append(newNodeName, name)
-			}
+	var host connector.Host
+	for _, h := range runtime.GetAllHosts() {
+		if h.GetName() == m.KubeConf.Arg.NodeName {
+			host = h
 		}
-		var connNodeName []string
-		for j := 0; j < len(newNodeName); j++ {
-			t := j
-			nodename1 := newNodeName[t]
-			for t+1 < len(newNodeName) && IsAdjoin(newNodeName[t], newNodeName[t+1]) {
-				t++
-			}
-			if t == j {
-				connNodeName = append(connNodeName, nodename1)
-			} else {
-				connNodeName = append(connNodeName, Merge(nodename1, newNodeName[t]))
-				j = t
+	}
+	if host == nil {
+		return errors.Errorf("node %s is not found in the config", m.KubeConf.Arg.NodeName)
+	}
+
+	if m.KubeConf.Arg.FilePath != "" {
+		fp, err := filepath.Abs(m.KubeConf.Arg.FilePath)
+		if err != nil {
+			return errors.Wrap(err, "Failed to look up current directory")
+		}
+		content, err := ioutil.ReadFile(fp)
+		if err != nil {
+			return errors.Wrapf(err, "Failed to read the config file %s", fp)
+		}
+		newContent, err := RemoveNode(content, host.GetName())
+		if err != nil {
+			return err
+		}
+		if err := ioutil.WriteFile(fp, newContent, 0644); err != nil {
+			return errors.Wrapf(err, "Failed to write the config file %s", fp)
+		}
+	}
+
+	runtime.DeleteHost(host)
+	return nil
+}
+
+var docSeparator = regexp.MustCompile(`(?m)^---[ \t]*$`)
+
+// RemoveNode removes the node from the hosts and the roleGroups of the Cluster in the config file.
+// The comments are kept and the other documents in the file are not changed.
+func RemoveNode(content []byte, nodeName string) ([]byte, error) {
+	docs := docSeparator.Split(string(content), -1)
+	found := false
+	for i, doc := range docs {
+		var root yaml.Node
+		if err := yaml.Unmarshal([]byte(doc), &root); err != nil {
+			return nil, errors.Wrap(err, "Failed to parse the config file")
+		}
+		if len(root.Content) == 0 || root.Content[0].Kind != yaml.MappingNode {
+			continue
+		}
+		if kind := mappingValue(root.Content[0], "kind"); kind == nil || kind.Value != "Cluster" {
+			continue
+		}
+
+		spec := mappingValue(root.Content[0], "spec")
+		if spec == nil {
+			continue
+		}
+		if removeHost(mappingValue(spec, "hosts"), nodeName) {
+			found = true
+		}
+		if roleGroups := mappingValue(spec, "roleGroups"); roleGroups != nil && roleGroups.Kind == yaml.MappingNode {
+			for j := 1; j < len(roleGroups.Content); j += 2 {
+				removeFromRoleGroup(roleGroups.Content[j], nodeName)
 			}
 		}
-		cmd1 := fmt.Sprintf("sed -i -n '1,/worker/p;/controlPlaneEndpoint/,$p' %s", fp)
-		_ = exec.Command("/bin/sh", "-c", cmd1).Run()
-		for k := 0; k < len(connNodeName); k++ {
-			workPar := connNodeName[k]
-			workPar1 := fmt.Sprintf("%s", workPar)
-			cmd2 := fmt.Sprintf("sed -i '/worker/a\\ \\ \\ \\ \\- %s' %s", workPar1, fp)
-			_ = exec.Command("/bin/sh", "-c", cmd2).Run()
+
+		buf := &bytes.Buffer{}
+		if i > 0 {
+			buf.WriteString("\n")
+		}
+		encoder := yaml.NewEncoder(buf)
+		encoder.SetIndent(2)
+		if err := encoder.Encode(&root); err != nil {
+			return nil, errors.Wrap(err, "Failed to generate the config file")
+		}
+		if err := encoder.Close(); err != nil {
+			return nil, errors.Wrap(err, "Failed to generate the config file")
+		}
+		docs[i] = buf.String()
+	}
+
+	if !found {
+		return nil, errors.Errorf("Please check the node name %s in the config file", nodeName)
+	}
+	return []byte(strings.Join(docs, "---")), nil
+}
+
+func mappingValue(node *yaml.Node, key string) *yaml.Node {
+	if node == nil || node.Kind != yaml.MappingNode {
+		return nil
+	}
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		if node.Content[i].Value == key {
+			return node.Content[i+1]
 		}
-	} else {
-		return errors.New("Please check the node name in the config-sample.yaml or only support to delete worker")
 	}
 	return nil
 }
 
+func removeHost(hosts *yaml.Node, nodeName string) bool {
+	if hosts == nil || hosts.Kind != yaml.SequenceNode {
+		return false
+	}
+	removed := false
+	content := make([]*yaml.Node, 0, len(hosts.Content))
+	for _, host := range hosts.Content {
+		if name := mappingValue(host, "name"); name != nil && name.Value == nodeName {
+			removed = true
+			continue
+		}
+		content = append(content, host)
+	}
+	hosts.Content = content
+	return removed
+}
+
+func removeFromRoleGroup(group *yaml.Node, nodeName string) {
+	if group == nil || group.Kind != yaml.SequenceNode {
+		return
+	}
+	content := make([]*yaml.Node, 0, len(group.Content))
+	for _, item := range group.Content {
+		if item.Kind != yaml.ScalarNode {
+			content = append(content, item)
+			continue
+		}
+		if item.Value == nodeName {
+			continue
+		}
+		names, ok := removeFromRange(item.Value, nodeName)
+		if !ok {
+			content = append(content, item)
+			continue
+		}
+		for k, name := range names {
+			node := &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Value: name}
+			if k == 0 {
+				node.LineComment = item.LineComment
+				node.HeadComment = item.HeadComment
+			}
+			content = append(content, node)
+		}
+	}
+	group.Content = content
+}
+
+var hostRange = regexp.MustCompile(`^(.*)\[(\d+):(\d+)\]$`)
+
+// removeFromRange splits the range such as node[2:10] if the node is in it,
+// and returns the names of the remaining nodes which are merged to ranges.
+func removeFromRange(rangeStr, nodeName string) ([]string, bool) {
+	match := hostRange.FindStringSubmatch(rangeStr)
+	if match == nil {
+		return nil, false
+	}
+	prefix := match[1]
+	start, _ := strconv.Atoi(match[2])
+	end, _ := strconv.Atoi(match[3])
+	if !strings.HasPrefix(nodeName, prefix) {
+		return nil, false
+	}
+	num, err := strconv.Atoi(strings.TrimPrefix(nodeName, prefix))
+	if err != nil || fmt.Sprintf("%s%d", prefix, num) != nodeName || num < start || num > end {
+		return nil, false
+	}
+
+	var names []string
+	for _, part := range [][2]int{{start, num - 1}, {num + 1, end}} {
+		switch {
+		case part[0] > part[1]:
+		case part[0] == part[1]:
+			names = append(names, fmt.Sprintf("%s%d", prefix, part[0]))
+		default:
+			names = append(names, Merge(fmt.Sprintf("%s%d", prefix, part[0]), fmt.Sprintf("%s%d", prefix, part[1])))
+		}
+	}
+	return names, true
+}
+
 func Merge(name1, name2 string) (endName string) {
 	par1, par2 := SplitNum(name1)
 	_, par4 := SplitNum(name2)
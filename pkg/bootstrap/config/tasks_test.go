@@ -0,0 +1,100 @@
+/*
+ Copyright 2021 The KubeSphere Authors.
+
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+     http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package config
+
+import (
+	"strings"
+	"testing"
+)
+
+const clusterConfig = `apiVersion: kubekey.kubesphere.io/v1alpha2
+kind: Cluster
+metadata:
+  name: sample
+spec:
+  hosts:
+  - {name: node1, address: 172.16.0.2, internalAddress: 172.16.0.2}
+  - {name: node2, address: 172.16.0.3, internalAddress: 172.16.0.3}
+  - {name: node3, address: 172.16.0.4, internalAddress: 172.16.0.4}
+  - {name: node10, address: 172.16.0.11, internalAddress: 172.16.0.11}
+  roleGroups:
+    etcd:
+    - node1
+    - node2 # etcd
+    master:
+    - node[1:3]
+    worker:
+    - node2
+    - node10
+---
+apiVersion: installer.kubesphere.io/v1alpha1
+kind: ClusterConfiguration
+metadata:
+  name: ks-installer
+spec:
+  persistence:
+    storageClass: ""        # node2
+`
+
+func TestRemoveNode(t *testing.T) {
+	out, err := RemoveNode([]byte(clusterConfig), "node2")
+	if err != nil {
+		t.Fatal(err)
+	}
+	res := string(out)
+
+	for _, s := range []string{
+		"{name: node1, address: 172.16.0.2, internalAddress: 172.16.0.2}",
+		"{name: node10, address: 172.16.0.11, internalAddress: 172.16.0.11}",
+		"master:\n      - node1\n      - node3\n",
+		"worker:\n      - node10\n",
+		"storageClass: \"\"        # node2\n",
+	} {
+		if !strings.Contains(res, s) {
+			t.Errorf("expected %q in:\n%s", s, res)
+		}
+	}
+	if strings.Contains(res, "name: node2,") || strings.Contains(res, "- node2") || strings.Contains(res, "node[1:3]") {
+		t.Errorf("node2 is not removed:\n%s", res)
+	}
+
+	if _, err := RemoveNode([]byte(clusterConfig), "node4"); err == nil {
+		t.Error("expected an error for the node which is not in the config")
+	}
+}
+
+func TestRemoveFromRange(t *testing.T) {
+	tests := []struct {
+		rangeStr string
+		node     string
+		want     []string
+		ok       bool
+	}{
+		{rangeStr: "node[2:10]", node: "node2", want: []string{"node[3:10]"}, ok: true},
+		{rangeStr: "node[2:10]", node: "node5", want: []string{"node[2:4]", "node[6:10]"}, ok: true},
+		{rangeStr: "node[2:10]", node: "node9", want: []string{"node[2:8]", "node10"}, ok: true},
+		{rangeStr: "node[2:10]", node: "node1", ok: false},
+		{rangeStr: "node[2:10]", node: "node05", ok: false},
+		{rangeStr: "node2", node: "node2", ok: false},
+	}
+	for _, tt := range tests {
+		got, ok := removeFromRange(tt.rangeStr, tt.node)
+		if ok != tt.ok || strings.Join(got, ",") != strings.Join(tt.want, ",") {
+			t.Errorf("removeFromRange(%s, %s) = %v, %v, want %v, %v", tt.rangeStr, tt.node, got, ok, tt.want, tt.ok)
+		}
+	}
+}
@@ -67,6 +67,11 @@ func (d *DeleteClusterConfirmModule) Init() {
 
 type DeleteNodeConfirmModule struct {
 	common.KubeModule
+	Skip bool
+}
+
+func (d *DeleteNodeConfirmModule) IsSkip() bool {
+	return d.Skip
 }
 
 func (d *DeleteNodeConfirmModule) Init() {
@@ -108,6 +113,31 @@ func (u *UpgradeConfirmModule) Init() {
 	}
 }
 
+type RestoreETCDConfirmModule struct {
+	common.KubeModule
+	Skip     bool
+	Snapshot string
+}
+
+func (r *RestoreETCDConfirmModule) IsSkip() bool {
+	return r.Skip
+}
+
+func (r *RestoreETCDConfirmModule) Init() {
+	r.Name = "RestoreETCDConfirmModule"
+	r.Desc = "Display restore etcd confirmation form"
+
+	display := &task.LocalTask{
+		Name:   "ConfirmForm",
+		Desc:   "Display confirmation form",
+		Action: &RestoreConfirm{Snapshot: r.Snapshot},
+	}
+
+	r.Tasks = []task.Interface{
+		display,
+	}
+}
+
 type CheckFileExistModule struct {
 	module.BaseTaskModule
 	FileName string
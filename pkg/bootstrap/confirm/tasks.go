@@ -45,6 +45,7 @@ type PreCheckResults struct {
 	Conntrack string `table:"conntrack"`
 	Chronyd   string `table:"chrony"`
 	Docker    string `table:"docker"`
+	Isula     string `table:"isula"`
 	Nfs       string `table:"nfs client"`
 	Ceph      string `table:"ceph client"`
 	Glusterfs string `table:"glusterfs client"`
@@ -148,6 +149,36 @@ func (d *DeleteConfirm) Execute(runtime connector.Runtime) error {
 	return nil
 }
 
+type RestoreConfirm struct {
+	common.KubeAction
+	Snapshot string
+}
+
+func (r *RestoreConfirm) Execute(runtime connector.Runtime) error {
+	reader := bufio.NewReader(os.Stdin)
+
+	var res string
+	for {
+		fmt.Printf("The etcd data of this cluster will be replaced by the snapshot %s and the control plane will be restarted.\n", r.Snapshot)
+		fmt.Printf("Are you sure to restore etcd? [yes/no]: ")
+		input, err := reader.ReadString('\n')
+		if err != nil {
+			return err
+		}
+		input = strings.TrimSpace(input)
+
+		if input != "" && (input == "yes" || input == "no") {
+			res = input
+			break
+		}
+	}
+
+	if res == "no" {
+		os.Exit(0)
+	}
+	return nil
+}
+
 type UpgradeConfirm struct {
 	common.KubeAction
 }
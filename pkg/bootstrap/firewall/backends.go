@@ -0,0 +1,312 @@
+/*
+ Copyright 2021 The KubeSphere Authors.
+
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+     http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package firewall
+
+import (
+	"fmt"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/kubesphere/kubekey/pkg/core/connector"
+	"github.com/kubesphere/kubekey/pkg/core/util"
+	"github.com/pkg/errors"
+)
+
+const (
+	// Name is the name of the firewalld service and zone, and the comment of the ufw rules created by kubekey.
+	Name = "kubekey"
+
+	nftInputChain   = "kubekey-input"
+	nftForwardChain = "kubekey-forward"
+	nftRulesFile    = "/etc/kubekey/firewall.nft"
+)
+
+// nftConfigFiles are the nftables configurations loaded at boot by debian and the redhat family.
+var nftConfigFiles = []string{"/etc/nftables.conf", "/etc/sysconfig/nftables.conf"}
+
+// Backend opens the ports of the cluster on the host firewall and removes them.
+type Backend interface {
+	Name() string
+	Open(runtime connector.Runtime, rules []Rule, sources, peers []string) error
+	Close(runtime connector.Runtime) error
+}
+
+// DetectBackend returns the backend of the active firewall on the remote host, or nil if there is none.
+func DetectBackend(runtime connector.Runtime) Backend {
+	if out, _ := runtime.GetRunner().SudoCmd("systemctl is-active firewalld", false); strings.TrimSpace(out) == "active" {
+		return &Firewalld{}
+	}
+	if out, _ := runtime.GetRunner().SudoCmd("ufw status", false); strings.Contains(out, "Status: active") {
+		return &Ufw{}
+	}
+	if out, err := runtime.GetRunner().SudoCmd("nft list chain inet filter input", false); err == nil && out != "" {
+		return &Nftables{}
+	}
+	return nil
+}
+
+func run(runtime connector.Runtime, backend string, cmds ...string) error {
+	for _, cmd := range cmds {
+		if _, err := runtime.GetRunner().SudoCmd(cmd, false); err != nil {
+			return errors.Wrapf(errors.WithStack(err), "configure %s failed: %s", backend, cmd)
+		}
+	}
+	return nil
+}
+
+// Firewalld adds the ports to the kubekey service, which is enabled in the zone of the internal address, and the
+// pod and service CIDRs to the kubekey zone which accepts all the traffic.
+type Firewalld struct{}
+
+func (f *Firewalld) Name() string {
+	return "firewalld"
+}
+
+func (f *Firewalld) Open(runtime connector.Runtime, rules []Rule, sources, _ []string) error {
+	zone, err := f.zone(runtime)
+	if err != nil {
+		return err
+	}
+
+	cmds := []string{fmt.Sprintf("firewall-cmd --permanent --info-service=%[1]s >/dev/null 2>&1 || firewall-cmd --permanent --new-service=%[1]s", Name)}
+	for _, rule := range rules {
+		if rule.Port == "" {
+			cmds = append(cmds, fmt.Sprintf("firewall-cmd --permanent --service=%s --add-protocol=%s", Name, rule.Protocol))
+		} else {
+			cmds = append(cmds, fmt.Sprintf("firewall-cmd --permanent --service=%s --add-port=%s", Name, rule))
+		}
+	}
+	cmds = append(cmds,
+		fmt.Sprintf("firewall-cmd --permanent --zone=%s --add-service=%s", zone, Name),
+		fmt.Sprintf("firewall-cmd --permanent --info-zone=%[1]s >/dev/null 2>&1 || firewall-cmd --permanent --new-zone=%[1]s", Name),
+		fmt.Sprintf("firewall-cmd --permanent --zone=%s --set-target=ACCEPT", Name))
+	for _, source := range sources {
+		cmds = append(cmds, fmt.Sprintf("firewall-cmd --permanent --zone=%s --add-source=%s", Name, source))
+	}
+	cmds = append(cmds, "firewall-cmd --reload")
+	return run(runtime, f.Name(), cmds...)
+}
+
+func (f *Firewalld) Close(runtime connector.Runtime) error {
+	out, err := runtime.GetRunner().SudoCmd("firewall-cmd --permanent --get-zones", false)
+	if err != nil {
+		return errors.Wrap(errors.WithStack(err), "get the firewalld zones failed")
+	}
+
+	var cmds []string
+	for _, zone := range strings.Fields(out) {
+		if zone != Name {
+			cmds = append(cmds, fmt.Sprintf("firewall-cmd --permanent --zone=%s --remove-service=%s", zone, Name))
+		}
+	}
+	cmds = append(cmds,
+		fmt.Sprintf("! firewall-cmd --permanent --info-service=%[1]s >/dev/null 2>&1 || firewall-cmd --permanent --delete-service=%[1]s", Name),
+		fmt.Sprintf("! firewall-cmd --permanent --info-zone=%[1]s >/dev/null 2>&1 || firewall-cmd --permanent --delete-zone=%[1]s", Name),
+		"firewall-cmd --reload")
+	return run(runtime, f.Name(), cmds...)
+}
+
+// zone returns the zone of the interface which the internal address belongs to, or the default zone.
+func (f *Firewalld) zone(runtime connector.Runtime) (string, error) {
+	iface, _ := runtime.GetRunner().SudoCmd(fmt.Sprintf(
+		"ip -o addr show | grep -w '%s' | head -1 | cut -d' ' -f2", runtime.RemoteHost().GetInternalAddress()), false)
+	if iface != "" {
+		if zone, err := runtime.GetRunner().SudoCmd(fmt.Sprintf("firewall-cmd --get-zone-of-interface=%s", iface), false); err == nil && zone != "" {
+			return strings.TrimSpace(zone), nil
+		}
+	}
+	zone, err := runtime.GetRunner().SudoCmd("firewall-cmd --get-default-zone", false)
+	if err != nil {
+		return "", errors.Wrap(errors.WithStack(err), "get the default zone of firewalld failed")
+	}
+	return strings.TrimSpace(zone), nil
+}
+
+// Ufw adds the rules with the kubekey comment. ufw can not match the IP protocol, so the traffic from the other nodes
+// is accepted for the IP protocol rules, e.g. IPIP of calico.
+type Ufw struct{}
+
+var ufwRuleNumber = regexp.MustCompile(`^\[\s*(\d+)\].*#\s*` + Name + `\s*$`)
+
+func (u *Ufw) Name() string {
+	return "ufw"
+}
+
+func (u *Ufw) Open(runtime connector.Runtime, rules []Rule, sources, peers []string) error {
+	var cmds []string
+	allowPeers := false
+	for _, rule := range rules {
+		if rule.Port == "" {
+			allowPeers = true
+			continue
+		}
+		cmds = append(cmds, fmt.Sprintf("ufw allow %s/%s comment '%s'", strings.Replace(rule.Port, "-", ":", 1), rule.Protocol, Name))
+	}
+	if allowPeers {
+		for _, peer := range peers {
+			cmds = append(cmds, fmt.Sprintf("ufw allow from %s comment '%s'", peer, Name))
+		}
+	}
+	for _, source := range sources {
+		cmds = append(cmds,
+			fmt.Sprintf("ufw allow from %s comment '%s'", source, Name),
+			fmt.Sprintf("ufw route allow from %s comment '%s'", source, Name),
+			fmt.Sprintf("ufw route allow to %s comment '%s'", source, Name))
+	}
+	return run(runtime, u.Name(), cmds...)
+}
+
+func (u *Ufw) Close(runtime connector.Runtime) error {
+	return u.deleteRules(runtime, ufwRuleNumber)
+}
+
+// RemovePeer removes the rule which accepts the traffic from the peer, it is left on the other nodes when the peer
+// is deleted from the cluster.
+func (u *Ufw) RemovePeer(runtime connector.Runtime, peer string) error {
+	return u.deleteRules(runtime, ufwPeerRuleNumber(peer))
+}
+
+// ufwPeerRuleNumber matches the numbered rule created by "ufw allow from <peer>".
+func ufwPeerRuleNumber(peer string) *regexp.Regexp {
+	return regexp.MustCompile(`^\[\s*(\d+)\]\s+Anywhere(\s+\(v6\))?\s+ALLOW IN\s+` + regexp.QuoteMeta(peer) + `\s+#\s*` + Name + `\s*$`)
+}
+
+// deleteRules deletes the rules whose line of "ufw status numbered" is matched by the pattern, the first submatch of
+// the pattern is the number of the rule.
+func (u *Ufw) deleteRules(runtime connector.Runtime, pattern *regexp.Regexp) error {
+	out, err := runtime.GetRunner().SudoCmd("ufw status numbered", false)
+	if err != nil {
+		return errors.Wrap(errors.WithStack(err), "list the ufw rules failed")
+	}
+
+	var numbers []int
+	for _, line := range strings.Split(out, "\n") {
+		if m := pattern.FindStringSubmatch(strings.TrimSpace(line)); m != nil {
+			n, _ := strconv.Atoi(m[1])
+			numbers = append(numbers, n)
+		}
+	}
+	// delete from the last one, the rules after the deleted one are renumbered
+	sort.Sort(sort.Reverse(sort.IntSlice(numbers)))
+	var cmds []string
+	for _, n := range numbers {
+		cmds = append(cmds, fmt.Sprintf("ufw --force delete %d", n))
+	}
+	return run(runtime, u.Name(), cmds...)
+}
+
+// Nftables adds the rules to the kubekey chains, which are jumped to from the input and forward chains of the
+// inet filter table. The rules are also included by the nftables configuration to be loaded at boot.
+type Nftables struct{}
+
+var nftJumpHandle = regexp.MustCompile(`jump (` + nftInputChain + `|` + nftForwardChain + `) # handle (\d+)`)
+
+func (n *Nftables) Name() string {
+	return "nftables"
+}
+
+func (n *Nftables) Open(runtime connector.Runtime, rules []Rule, sources, _ []string) error {
+	_, err := runtime.GetRunner().SudoCmd("nft list chain inet filter forward", false)
+	forward := err == nil
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "table inet filter {\n\tchain %s {\n\t}\n", nftInputChain)
+	if forward {
+		fmt.Fprintf(&b, "\tchain %s {\n\t}\n", nftForwardChain)
+	}
+	fmt.Fprintf(&b, "}\nflush chain inet filter %s\n", nftInputChain)
+	for _, rule := range rules {
+		if rule.Port == "" {
+			fmt.Fprintf(&b, "add rule inet filter %s meta l4proto %s accept\n", nftInputChain, rule.Protocol)
+		} else {
+			fmt.Fprintf(&b, "add rule inet filter %s %s dport %s accept\n", nftInputChain, rule.Protocol, rule.Port)
+		}
+	}
+	for _, source := range sources {
+		fmt.Fprintf(&b, "add rule inet filter %s %s saddr %s accept\n", nftInputChain, nftFamily(source), source)
+	}
+	fmt.Fprintf(&b, "insert rule inet filter input jump %s\n", nftInputChain)
+	if forward {
+		fmt.Fprintf(&b, "flush chain inet filter %s\n", nftForwardChain)
+		for _, source := range sources {
+			fmt.Fprintf(&b, "add rule inet filter %s %s saddr %s accept\n", nftForwardChain, nftFamily(source), source)
+			fmt.Fprintf(&b, "add rule inet filter %s %s daddr %s accept\n", nftForwardChain, nftFamily(source), source)
+		}
+		fmt.Fprintf(&b, "insert rule inet filter forward jump %s\n", nftForwardChain)
+	}
+
+	local := filepath.Join(runtime.GetHostWorkDir(), filepath.Base(nftRulesFile))
+	if err := util.WriteFile(local, []byte(b.String())); err != nil {
+		return errors.Wrap(errors.WithStack(err), fmt.Sprintf("write file %s failed", local))
+	}
+	if err := runtime.GetRunner().SudoScp(local, nftRulesFile); err != nil {
+		return errors.Wrap(errors.WithStack(err), fmt.Sprintf("scp file %s to remote %s failed", local, nftRulesFile))
+	}
+
+	// the jumps are inserted again by the rules file
+	if err := n.deleteJumps(runtime); err != nil {
+		return err
+	}
+	cmds := []string{fmt.Sprintf("nft -f %s", nftRulesFile)}
+	for _, conf := range nftConfigFiles {
+		cmds = append(cmds, fmt.Sprintf("[ ! -f %[1]s ] || grep -qs '%[2]s' %[1]s || echo 'include \\\"%[2]s\\\"' >> %[1]s", conf, nftRulesFile))
+	}
+	return run(runtime, n.Name(), cmds...)
+}
+
+func (n *Nftables) Close(runtime connector.Runtime) error {
+	if err := n.deleteJumps(runtime); err != nil {
+		return err
+	}
+
+	var cmds []string
+	for _, chain := range []string{nftInputChain, nftForwardChain} {
+		cmds = append(cmds, fmt.Sprintf("! nft list chain inet filter %[1]s >/dev/null 2>&1 || (nft flush chain inet filter %[1]s && nft delete chain inet filter %[1]s)", chain))
+	}
+	for _, conf := range nftConfigFiles {
+		cmds = append(cmds, fmt.Sprintf("[ ! -f %s ] || sed -i '\\#%s#d' %s", conf, nftRulesFile, conf))
+	}
+	cmds = append(cmds, fmt.Sprintf("rm -f %s", nftRulesFile))
+	return run(runtime, n.Name(), cmds...)
+}
+
+func (n *Nftables) deleteJumps(runtime connector.Runtime) error {
+	for _, chain := range []string{"input", "forward"} {
+		out, err := runtime.GetRunner().SudoCmd(fmt.Sprintf("nft -a list chain inet filter %s", chain), false)
+		if err != nil {
+			continue
+		}
+		for _, line := range strings.Split(out, "\n") {
+			if m := nftJumpHandle.FindStringSubmatch(line); m != nil {
+				if err := run(runtime, n.Name(), fmt.Sprintf("nft delete rule inet filter %s handle %s", chain, m[2])); err != nil {
+					return err
+				}
+			}
+		}
+	}
+	return nil
+}
+
+func nftFamily(cidr string) string {
+	if util.IsIPv6(cidr) {
+		return "ip6"
+	}
+	return "ip"
+}
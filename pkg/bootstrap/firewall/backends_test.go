@@ -0,0 +1,90 @@
+/*
+ Copyright 2021 The KubeSphere Authors.
+
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+     http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package firewall
+
+import "testing"
+
+func TestUfwRuleNumber(t *testing.T) {
+	tests := []struct {
+		line string
+		want string
+	}{
+		{line: "[ 1] 6443/tcp                   ALLOW IN    Anywhere                   # kubekey", want: "1"},
+		{line: "[12] 30000:32767/udp (v6)       ALLOW IN    Anywhere (v6)              # kubekey", want: "12"},
+		{line: "[ 3] 22/tcp                     ALLOW IN    Anywhere"},
+		{line: "[ 4] 80/tcp                     ALLOW IN    Anywhere                   # kubekey-dashboard"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.line, func(t *testing.T) {
+			var got string
+			if m := ufwRuleNumber.FindStringSubmatch(tt.line); m != nil {
+				got = m[1]
+			}
+			if got != tt.want {
+				t.Errorf("ufwRuleNumber = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestNftJumpHandle(t *testing.T) {
+	tests := []struct {
+		line string
+		want string
+	}{
+		{line: "\t\tjump kubekey-input # handle 12", want: "12"},
+		{line: "\t\tjump kubekey-forward # handle 7", want: "7"},
+		{line: "\t\tjump kubekey-input-extra # handle 9"},
+		{line: "\t\ttcp dport 22 accept # handle 3"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.line, func(t *testing.T) {
+			var got string
+			if m := nftJumpHandle.FindStringSubmatch(tt.line); m != nil {
+				got = m[2]
+			}
+			if got != tt.want {
+				t.Errorf("nftJumpHandle = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestUfwPeerRuleNumber(t *testing.T) {
+	tests := []struct {
+		peer string
+		line string
+		want string
+	}{
+		{peer: "172.16.0.3", line: "[ 5] Anywhere                   ALLOW IN    172.16.0.3                 # kubekey", want: "5"},
+		{peer: "fd00::3", line: "[11] Anywhere (v6)              ALLOW IN    fd00::3                    # kubekey", want: "11"},
+		{peer: "172.16.0.3", line: "[ 6] Anywhere                   ALLOW IN    172.16.0.30                # kubekey"},
+		{peer: "172.16.0.3", line: "[ 7] Anywhere                   ALLOW IN    172.16.0.3"},
+		{peer: "172.16.0.3", line: "[ 8] Anywhere                   ALLOW FWD   172.16.0.3                 # kubekey"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.line, func(t *testing.T) {
+			var got string
+			if m := ufwPeerRuleNumber(tt.peer).FindStringSubmatch(tt.line); m != nil {
+				got = m[1]
+			}
+			if got != tt.want {
+				t.Errorf("ufwPeerRuleNumber(%s) = %q, want %q", tt.peer, got, tt.want)
+			}
+		})
+	}
+}
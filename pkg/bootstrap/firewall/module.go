@@ -0,0 +1,111 @@
+/*
+ Copyright 2021 The KubeSphere Authors.
+
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+     http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package firewall
+
+import (
+	"github.com/kubesphere/kubekey/pkg/common"
+	"github.com/kubesphere/kubekey/pkg/core/task"
+)
+
+type ConfigureFirewallModule struct {
+	common.KubeModule
+	Skip bool
+}
+
+func (c *ConfigureFirewallModule) IsSkip() bool {
+	return c.Skip
+}
+
+func (c *ConfigureFirewallModule) Init() {
+	c.Name = "ConfigureFirewallModule"
+	c.Desc = "Open the ports of the cluster on the host firewalls"
+
+	openPorts := &task.RemoteTask{
+		Name:     "OpenPorts",
+		Desc:     "Open the ports required by the roles of each host",
+		Hosts:    c.Runtime.GetAllHosts(),
+		Action:   new(OpenPorts),
+		Parallel: true,
+	}
+
+	c.Tasks = []task.Interface{
+		openPorts,
+	}
+}
+
+type ClearFirewallModule struct {
+	common.KubeModule
+	Skip bool
+}
+
+func (c *ClearFirewallModule) IsSkip() bool {
+	return c.Skip
+}
+
+func (c *ClearFirewallModule) Init() {
+	c.Name = "ClearFirewallModule"
+	c.Desc = "Remove the ports of the cluster from the host firewalls"
+
+	closePorts := &task.RemoteTask{
+		Name:     "ClosePorts",
+		Desc:     "Remove the firewall rules created by kubekey",
+		Hosts:    c.Runtime.GetAllHosts(),
+		Action:   new(ClosePorts),
+		Parallel: true,
+	}
+
+	c.Tasks = []task.Interface{
+		closePorts,
+	}
+}
+
+type ClearNodeFirewallModule struct {
+	common.KubeModule
+	Skip bool
+}
+
+func (c *ClearNodeFirewallModule) IsSkip() bool {
+	return c.Skip
+}
+
+func (c *ClearNodeFirewallModule) Init() {
+	c.Name = "ClearNodeFirewallModule"
+	c.Desc = "Remove the ports of the cluster from the firewall of the deleted node and its address from the others"
+
+	closePorts := &task.RemoteTask{
+		Name:     "ClosePorts",
+		Desc:     "Remove the firewall rules created by kubekey",
+		Hosts:    c.Runtime.GetAllHosts(),
+		Prepare:  new(common.OnlyNode),
+		Action:   new(ClosePorts),
+		Parallel: true,
+	}
+
+	removePeer := &task.RemoteTask{
+		Name:     "RemovePeer",
+		Desc:     "Remove the rules accepting the traffic from the deleted node",
+		Hosts:    c.Runtime.GetHostsByRole(common.K8s),
+		Prepare:  &common.OnlyNode{Not: true},
+		Action:   new(RemovePeer),
+		Parallel: true,
+	}
+
+	c.Tasks = []task.Interface{
+		closePorts,
+		removePeer,
+	}
+}
@@ -0,0 +1,126 @@
+/*
+ Copyright 2021 The KubeSphere Authors.
+
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+     http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package firewall
+
+import (
+	"strconv"
+
+	"github.com/kubesphere/kubekey/pkg/common"
+	"github.com/kubesphere/kubekey/pkg/core/connector"
+	"github.com/kubesphere/kubekey/pkg/loadbalancer"
+)
+
+const (
+	TCP = "tcp"
+	UDP = "udp"
+	// IPIP is the IP protocol number of the IP-in-IP encapsulation used by calico.
+	IPIP = "4"
+
+	NodePortRange = "30000-32767"
+)
+
+// Rule is a port range or an IP protocol accepted by the firewall.
+type Rule struct {
+	// Port is a single port or a port range, e.g. 30000-32767. It is empty for the IP protocol rules.
+	Port string
+	// Protocol is tcp or udp for the port rules, or the IP protocol number for the IP protocol rules.
+	Protocol string
+}
+
+func (r Rule) String() string {
+	if r.Port == "" {
+		return "proto " + r.Protocol
+	}
+	return r.Port + "/" + r.Protocol
+}
+
+// HostRules returns the rules required by the roles of the host.
+func HostRules(runtime connector.Runtime, kubeConf *common.KubeConf, host connector.Host) []Rule {
+	var rules []Rule
+	add := func(protocol string, ports ...string) {
+		for _, port := range ports {
+			rules = append(rules, Rule{Port: port, Protocol: protocol})
+		}
+	}
+
+	if host.IsRole(common.Master) {
+		// kube-apiserver, kube-controller-manager and kube-scheduler
+		add(TCP, strconv.Itoa(kubeConf.Cluster.ControlPlaneEndpoint.Port), "10257", "10259")
+	}
+	if host.IsRole(common.ETCD) {
+		add(TCP, "2379-2380")
+	}
+	if host.IsRole(common.Registry) {
+		add(TCP, "443")
+	}
+	if !host.IsRole(common.K8s) {
+		return rules
+	}
+
+	add(TCP, "10250", NodePortRange)
+	add(UDP, NodePortRange)
+	if kubeConf.Cluster.ControlPlaneEndpoint.IsInternalLBEnabled() && !host.IsRole(common.Master) {
+		add(TCP, strconv.Itoa(loadbalancer.HealthCheckPort))
+	}
+
+	network := kubeConf.Cluster.Network
+	switch network.Plugin {
+	case "calico":
+		add(TCP, "179")
+		if network.Calico.IPIPMode != "Never" {
+			add(IPIP, "")
+		}
+		if network.Calico.VXLANMode != "Never" {
+			add(UDP, "4789")
+		}
+		// the same condition as the typha deployment of calico
+		if len(runtime.GetHostsByRole(common.K8s)) > 50 {
+			add(TCP, "5473")
+		}
+	case "flannel":
+		if network.Flannel.BackendMode == "vxlan" {
+			add(UDP, "8472")
+		}
+	case "cilium":
+		add(UDP, "8472")
+		add(TCP, "4240")
+	case "kubeovn":
+		add(UDP, "6081")
+		if host.IsRole(common.Master) {
+			// the northbound and southbound databases of ovn-central
+			add(TCP, "6641-6644")
+		}
+	}
+	return rules
+}
+
+// SourceCIDRs returns the pod and service CIDRs, from which all the traffic is accepted.
+func SourceCIDRs(kubeConf *common.KubeConf) []string {
+	return append(kubeConf.Cluster.Network.PodCIDRs(), kubeConf.Cluster.Network.ServiceCIDRs()...)
+}
+
+// PeerAddresses returns the internal addresses of the other kubernetes nodes, which is used to accept the IP protocol
+// rules by the firewalls that do not support matching the IP protocol.
+func PeerAddresses(runtime connector.Runtime, host connector.Host) []string {
+	var addrs []string
+	for _, h := range runtime.GetHostsByRole(common.K8s) {
+		if h.GetName() != host.GetName() {
+			addrs = append(addrs, h.GetInternalAddress())
+		}
+	}
+	return addrs
+}
@@ -0,0 +1,144 @@
+/*
+ Copyright 2021 The KubeSphere Authors.
+
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+     http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package firewall
+
+import (
+	"fmt"
+	"reflect"
+	"testing"
+
+	kubekeyapiv1alpha2 "github.com/kubesphere/kubekey/apis/kubekey/v1alpha2"
+	"github.com/kubesphere/kubekey/pkg/common"
+	"github.com/kubesphere/kubekey/pkg/core/connector"
+)
+
+func TestHostRules(t *testing.T) {
+	calico := func(ipip, vxlan string) kubekeyapiv1alpha2.NetworkConfig {
+		return kubekeyapiv1alpha2.NetworkConfig{Plugin: "calico", Calico: kubekeyapiv1alpha2.CalicoCfg{IPIPMode: ipip, VXLANMode: vxlan}}
+	}
+	node := []Rule{{"10250", TCP}, {NodePortRange, TCP}, {NodePortRange, UDP}}
+	apiserver := []Rule{{"6443", TCP}, {"10257", TCP}, {"10259", TCP}}
+
+	tests := []struct {
+		name    string
+		roles   []string
+		network kubekeyapiv1alpha2.NetworkConfig
+		lb      string
+		k8s     int
+		want    []Rule
+	}{
+		{
+			name:  "etcd",
+			roles: []string{common.ETCD},
+			want:  []Rule{{"2379-2380", TCP}},
+		},
+		{
+			name:  "registry",
+			roles: []string{common.Registry},
+			want:  []Rule{{"443", TCP}},
+		},
+		{
+			name:    "master",
+			roles:   []string{common.Master, common.ETCD, common.K8s},
+			network: calico("Never", "Never"),
+			want:    append(append(apiserver, Rule{"2379-2380", TCP}), append(node, Rule{"179", TCP})...),
+		},
+		{
+			name:    "worker with the internal load balancer",
+			roles:   []string{common.Worker, common.K8s},
+			network: calico("Never", "Never"),
+			lb:      kubekeyapiv1alpha2.Haproxy,
+			want:    append(node, Rule{"8081", TCP}, Rule{"179", TCP}),
+		},
+		{
+			name:    "calico ipip",
+			roles:   []string{common.Worker, common.K8s},
+			network: calico("Always", "Never"),
+			want:    append(node, Rule{"179", TCP}, Rule{"", IPIP}),
+		},
+		{
+			name:    "calico vxlan",
+			roles:   []string{common.Worker, common.K8s},
+			network: calico("Never", "Always"),
+			want:    append(node, Rule{"179", TCP}, Rule{"4789", UDP}),
+		},
+		{
+			name:    "calico typha",
+			roles:   []string{common.Worker, common.K8s},
+			network: calico("Never", "Never"),
+			k8s:     51,
+			want:    append(node, Rule{"179", TCP}, Rule{"5473", TCP}),
+		},
+		{
+			name:    "flannel vxlan",
+			roles:   []string{common.Worker, common.K8s},
+			network: kubekeyapiv1alpha2.NetworkConfig{Plugin: "flannel", Flannel: kubekeyapiv1alpha2.FlannelCfg{BackendMode: "vxlan"}},
+			want:    append(node, Rule{"8472", UDP}),
+		},
+		{
+			name:    "flannel host-gw",
+			roles:   []string{common.Worker, common.K8s},
+			network: kubekeyapiv1alpha2.NetworkConfig{Plugin: "flannel", Flannel: kubekeyapiv1alpha2.FlannelCfg{BackendMode: "host-gw"}},
+			want:    node,
+		},
+		{
+			name:    "cilium",
+			roles:   []string{common.Worker, common.K8s},
+			network: kubekeyapiv1alpha2.NetworkConfig{Plugin: "cilium"},
+			want:    append(node, Rule{"8472", UDP}, Rule{"4240", TCP}),
+		},
+		{
+			name:    "kubeovn worker",
+			roles:   []string{common.Worker, common.K8s},
+			network: kubekeyapiv1alpha2.NetworkConfig{Plugin: "kubeovn"},
+			want:    append(node, Rule{"6081", UDP}),
+		},
+		{
+			name:    "kubeovn master",
+			roles:   []string{common.Master, common.K8s},
+			network: kubekeyapiv1alpha2.NetworkConfig{Plugin: "kubeovn"},
+			want:    append(append(apiserver, node...), Rule{"6081", UDP}, Rule{"6641-6644", TCP}),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			runtime := connector.NewBaseRuntime("test", nil, false, false)
+			host := connector.NewHost()
+			host.SetName("node1")
+			for _, role := range tt.roles {
+				host.SetRole(role)
+			}
+			runtime.AppendHost(host)
+			runtime.AppendRoleMap(host)
+			for i := 1; i < tt.k8s; i++ {
+				h := connector.NewHost()
+				h.SetName(fmt.Sprintf("node%d", i+1))
+				h.SetRole(common.K8s)
+				runtime.AppendHost(h)
+				runtime.AppendRoleMap(h)
+			}
+
+			kubeConf := &common.KubeConf{Cluster: &kubekeyapiv1alpha2.ClusterSpec{
+				ControlPlaneEndpoint: kubekeyapiv1alpha2.ControlPlaneEndpoint{Port: 6443, InternalLoadbalancer: tt.lb},
+				Network:              tt.network,
+			}}
+			if got := HostRules(&runtime, kubeConf, host); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("HostRules() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,81 @@
+/*
+ Copyright 2021 The KubeSphere Authors.
+
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+     http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package firewall
+
+import (
+	"strings"
+
+	"github.com/kubesphere/kubekey/pkg/common"
+	"github.com/kubesphere/kubekey/pkg/core/connector"
+	"github.com/kubesphere/kubekey/pkg/core/logger"
+)
+
+type OpenPorts struct {
+	common.KubeAction
+}
+
+func (o *OpenPorts) Execute(runtime connector.Runtime) error {
+	host := runtime.RemoteHost()
+	backend := DetectBackend(runtime)
+	if backend == nil {
+		logger.Log.Messagef(host.GetName(), "no active firewalld, ufw or nftables is found, skip opening the ports")
+		return nil
+	}
+
+	rules := HostRules(runtime, o.KubeConf, host)
+	var ports []string
+	for _, rule := range rules {
+		ports = append(ports, rule.String())
+	}
+	logger.Log.Messagef(host.GetName(), "open %s on %s", strings.Join(ports, ", "), backend.Name())
+	return backend.Open(runtime, rules, SourceCIDRs(o.KubeConf), PeerAddresses(runtime, host))
+}
+
+type ClosePorts struct {
+	common.KubeAction
+}
+
+func (c *ClosePorts) Execute(runtime connector.Runtime) error {
+	backend := DetectBackend(runtime)
+	if backend == nil {
+		return nil
+	}
+	return backend.Close(runtime)
+}
+
+// RemovePeer removes the rule which accepts the traffic from the deleted node on the remaining nodes. Only ufw accepts
+// the traffic by the addresses of the peers, the other backends match the IP protocol instead.
+type RemovePeer struct {
+	common.KubeAction
+}
+
+func (r *RemovePeer) Execute(runtime connector.Runtime) error {
+	var peer string
+	for _, host := range runtime.GetHostsByRole(common.K8s) {
+		if host.GetName() == r.KubeConf.Arg.NodeName {
+			peer = host.GetInternalAddress()
+		}
+	}
+	if peer == "" {
+		return nil
+	}
+
+	if backend, ok := DetectBackend(runtime).(*Ufw); ok {
+		return backend.RemovePeer(runtime, peer)
+	}
+	return nil
+}
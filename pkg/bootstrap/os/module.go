@@ -41,6 +41,7 @@ func (c *ConfigureOSModule) Init() {
 		Parallel: true,
 	}
 
+	kernelModules, extraKernelModules := templates.GenerateKernelModules(c.KubeConf)
 	GenerateScript := &task.RemoteTask{
 		Name:  "GenerateScript",
 		Desc:  "Generate init os script",
@@ -49,7 +50,41 @@ func (c *ConfigureOSModule) Init() {
 			Template: templates.InitOsScriptTmpl,
 			Dst:      filepath.Join(common.KubeScriptDir, "initOS.sh"),
 			Data: util.Data{
-				"Hosts": templates.GenerateHosts(c.Runtime, c.KubeConf),
+				"Hosts":              templates.GenerateHosts(c.Runtime, c.KubeConf),
+				"SELinux":            c.KubeConf.Cluster.System.SELinux,
+				"Firewall":           c.KubeConf.Cluster.System.Firewall,
+				"KernelModules":      kernelModules,
+				"ExtraKernelModules": extraKernelModules,
+				"SysctlKeys":         templates.GenerateSysctlKeys(c.KubeConf),
+				"LegacySysctls":      templates.GenerateLegacySysctls(c.KubeConf),
+			},
+		},
+		Parallel: true,
+	}
+
+	generateSysctlConf := &task.RemoteTask{
+		Name:  "GenerateSysctlConf",
+		Desc:  "Generate the kernel parameters",
+		Hosts: c.Runtime.GetAllHosts(),
+		Action: &action.Template{
+			Template: templates.SysctlConf,
+			Dst:      filepath.Join("/etc/sysctl.d", templates.SysctlConf.Name()),
+			Data: util.Data{
+				"Sysctls": templates.GenerateSysctls(c.KubeConf),
+			},
+		},
+		Parallel: true,
+	}
+
+	generateLimitsConf := &task.RemoteTask{
+		Name:  "GenerateLimitsConf",
+		Desc:  "Generate the resource limits",
+		Hosts: c.Runtime.GetAllHosts(),
+		Action: &action.Template{
+			Template: templates.LimitsConf,
+			Dst:      filepath.Join("/etc/security/limits.d", templates.LimitsConf.Name()),
+			Data: util.Data{
+				"Ulimits": templates.GenerateUlimits(c.KubeConf),
 			},
 		},
 		Parallel: true,
@@ -75,6 +110,8 @@ func (c *ConfigureOSModule) Init() {
 	c.Tasks = []task.Interface{
 		initOS,
 		GenerateScript,
+		generateSysctlConf,
+		generateLimitsConf,
 		ExecScript,
 		ConfigureNtpServer,
 	}
@@ -127,10 +164,66 @@ func (c *ClearOSEnvironmentModule) Init() {
 	}
 }
 
+type ClearNodeOSModule struct {
+	common.KubeModule
+}
+
+func (c *ClearNodeOSModule) Init() {
+	c.Name = "ClearNodeOSModule"
+	c.Desc = "Clear the os environment of the deleted node"
+
+	resetNetworkConfig := &task.RemoteTask{
+		Name:     "ResetNetworkConfig",
+		Desc:     "Reset os network config",
+		Hosts:    c.Runtime.GetHostsByRole(common.K8s),
+		Prepare:  new(common.OnlyNode),
+		Action:   new(ResetNetworkConfig),
+		Parallel: true,
+	}
+
+	stopETCD := &task.RemoteTask{
+		Name:     "StopETCDService",
+		Desc:     "Stop etcd service",
+		Hosts:    c.Runtime.GetHostsByRole(common.ETCD),
+		Prepare:  new(common.OnlyNode),
+		Action:   new(StopETCDService),
+		Parallel: true,
+	}
+
+	removeFiles := &task.RemoteTask{
+		Name:     "RemoveNodeFiles",
+		Desc:     "Remove node files",
+		Hosts:    c.Runtime.GetAllHosts(),
+		Prepare:  new(common.OnlyNode),
+		Action:   new(RemoveFiles),
+		Parallel: true,
+	}
+
+	daemonReload := &task.RemoteTask{
+		Name:     "DaemonReload",
+		Desc:     "Systemd daemon reload",
+		Hosts:    c.Runtime.GetAllHosts(),
+		Prepare:  new(common.OnlyNode),
+		Action:   new(DaemonReload),
+		Parallel: true,
+	}
+
+	c.Tasks = []task.Interface{
+		resetNetworkConfig,
+		stopETCD,
+		removeFiles,
+		daemonReload,
+	}
+}
+
 type InitDependenciesModule struct {
 	common.KubeModule
 }
 
+func (i *InitDependenciesModule) IsAlwaysRun() bool {
+	return true
+}
+
 func (i *InitDependenciesModule) Init() {
 	i.Name = "InitDependenciesModule"
 
@@ -180,6 +273,10 @@ func (r *RepositoryModule) IsSkip() bool {
 	return r.Skip
 }
 
+func (r *RepositoryModule) IsAlwaysRun() bool {
+	return true
+}
+
 func (r *RepositoryModule) Init() {
 	r.Name = "RepositoryModule"
 	r.Desc = "Install local repository"
@@ -18,7 +18,19 @@ package repository
 
 import (
 	"fmt"
+	osrelease "github.com/dominodatalab/os-release"
 	"github.com/kubesphere/kubekey/pkg/core/connector"
+	"strings"
+)
+
+const (
+	Deb = "deb"
+	RPM = "rpm"
+)
+
+var (
+	debianFamily = []string{"debian", "ubuntu"}
+	redhatFamily = []string{"rhel", "centos", "fedora", "rocky", "almalinux", "openeuler"}
 )
 
 type Interface interface {
@@ -30,13 +42,45 @@ type Interface interface {
 	Reset() error
 }
 
-func New(os string, runtime connector.Runtime) (Interface, error) {
-	switch os {
-	case "ubuntu":
+func New(release *osrelease.Data, pkgTool string, runtime connector.Runtime) (Interface, error) {
+	switch Family(release, pkgTool) {
+	case Deb:
 		return NewDeb(runtime), nil
-	case "centos":
+	case RPM:
 		return NewRPM(runtime), nil
 	default:
-		return nil, fmt.Errorf("unsupported operation system %s", os)
+		return nil, fmt.Errorf("unsupported operation system %s", release.ID)
+	}
+}
+
+// Family returns the package family (deb or rpm) of the release, looked up by its ID first and then by its ID_LIKE.
+// Kylin and UOS ship both deb and rpm based editions, mostly without ID_LIKE, so the package tool found on the
+// host is used when the release itself is unknown.
+func Family(release *osrelease.Data, pkgTool string) string {
+	ids := append([]string{release.ID}, strings.Fields(release.IDLike)...)
+	for _, id := range ids {
+		id = strings.ToLower(strings.Trim(id, "'"))
+		if contains(debianFamily, id) {
+			return Deb
+		}
+		if contains(redhatFamily, id) {
+			return RPM
+		}
+	}
+
+	switch tool := strings.TrimSpace(pkgTool); tool {
+	case Deb, RPM:
+		return tool
+	default:
+		return ""
+	}
+}
+
+func contains(ids []string, id string) bool {
+	for _, i := range ids {
+		if i == id {
+			return true
+		}
 	}
+	return false
 }
@@ -34,7 +34,7 @@ func NewDeb(runtime connector.Runtime) Interface {
 }
 
 func (d *Debian) Backup() error {
-	if _, err := d.runtime.GetRunner().SudoCmd("mv /etc/apt/sources.list /etc/apt/sources.list.kubekey.bak", false); err != nil {
+	if _, err := d.runtime.GetRunner().SudoCmd("if [ -f /etc/apt/sources.list ]; then mv /etc/apt/sources.list /etc/apt/sources.list.kubekey.bak; fi", false); err != nil {
 		return err
 	}
 
@@ -82,7 +82,7 @@ func (d *Debian) Install(pkg ...string) error {
 	}
 
 	str := strings.Join(pkg, " ")
-	if _, err := d.runtime.GetRunner().SudoCmd(fmt.Sprintf("apt install -y %s", str), true); err != nil {
+	if _, err := d.runtime.GetRunner().SudoCmd(fmt.Sprintf("apt-get install -y %s", str), true); err != nil {
 		return err
 	}
 	return nil
@@ -93,7 +93,7 @@ func (d *Debian) Reset() error {
 		return err
 	}
 
-	if _, err := d.runtime.GetRunner().SudoCmd("mv /etc/apt/sources.list.kubekey.bak /etc/apt/sources.list", false); err != nil {
+	if _, err := d.runtime.GetRunner().SudoCmd("if [ -f /etc/apt/sources.list.kubekey.bak ]; then mv /etc/apt/sources.list.kubekey.bak /etc/apt/sources.list; fi", false); err != nil {
 		return err
 	}
 
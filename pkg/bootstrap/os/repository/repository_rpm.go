@@ -22,9 +22,12 @@ import (
 	"strings"
 )
 
+const localRepo = "kubekey-local"
+
 type RedhatPackageManager struct {
 	runtime connector.Runtime
 	backup  bool
+	tool    string
 }
 
 func NewRPM(runtime connector.Runtime) Interface {
@@ -58,18 +61,27 @@ func (r *RedhatPackageManager) Add(path string) error {
 		return err
 	}
 
-	content := fmt.Sprintf(`cat << EOF > /etc/yum.repos.d/CentOS-local.repo
-[base-local]
-name=CentOS7.6-local
+	// the packages in the iso are signed by the distribution, check them against the keys shipped with it.
+	keys, err := r.runtime.GetRunner().SudoCmd("ls /etc/pki/rpm-gpg/RPM-GPG-KEY-* 2>/dev/null || true", false)
+	if err != nil {
+		return err
+	}
+	gpg := "gpgcheck=0"
+	if fields := strings.Fields(keys); len(fields) > 0 {
+		for i := range fields {
+			fields[i] = "file://" + fields[i]
+		}
+		gpg = fmt.Sprintf("gpgcheck=1\ngpgkey=%s", strings.Join(fields, " "))
+	}
 
+	content := fmt.Sprintf(`cat << EOF > /etc/yum.repos.d/%s.repo
+[%s]
+name=KubeKey local repository
 baseurl=file://%s
-
-enabled=1 
-
-gpgcheck=1
-gpgkey=file:///etc/pki/rpm-gpg/RPM-GPG-KEY-CentOS-7
+enabled=1
+%s
 EOF
-`, path)
+`, localRepo, localRepo, path, gpg)
 	if _, err := r.runtime.GetRunner().SudoCmd(content, false); err != nil {
 		return err
 	}
@@ -78,7 +90,12 @@ EOF
 }
 
 func (r *RedhatPackageManager) Update() error {
-	if _, err := r.runtime.GetRunner().SudoCmd("yum clean all && yum makecache", true); err != nil {
+	tool, err := r.packageManager()
+	if err != nil {
+		return err
+	}
+
+	if _, err := r.runtime.GetRunner().SudoCmd(fmt.Sprintf("%s clean all && %s makecache %s", tool, tool, onlyLocalRepo()), true); err != nil {
 		return err
 	}
 	return nil
@@ -89,8 +106,13 @@ func (r *RedhatPackageManager) Install(pkg ...string) error {
 		pkg = []string{"openssl", "socat", "conntrack", "ipset", "ebtables", "chrony"}
 	}
 
+	tool, err := r.packageManager()
+	if err != nil {
+		return err
+	}
+
 	str := strings.Join(pkg, " ")
-	if _, err := r.runtime.GetRunner().SudoCmd(fmt.Sprintf("yum install -y %s", str), true); err != nil {
+	if _, err := r.runtime.GetRunner().SudoCmd(fmt.Sprintf("%s install -y %s %s", tool, onlyLocalRepo(), str), true); err != nil {
 		return err
 	}
 	return nil
@@ -107,3 +129,26 @@ func (r *RedhatPackageManager) Reset() error {
 
 	return nil
 }
+
+// packageManager returns dnf on the releases shipping it (RHEL 8+, Rocky, Alma, openEuler, Kylin V10) and yum on the older ones.
+func (r *RedhatPackageManager) packageManager() (string, error) {
+	if r.tool != "" {
+		return r.tool, nil
+	}
+
+	out, err := r.runtime.GetRunner().SudoCmd("if command -v dnf >/dev/null 2>&1; then echo dnf; else echo yum; fi", false)
+	if err != nil {
+		return "", err
+	}
+	r.tool = strings.TrimSpace(out)
+	if r.tool == "" {
+		r.tool = "yum"
+	}
+	return r.tool, nil
+}
+
+// onlyLocalRepo keeps the repositories generated on the fly, such as the redhat.repo of subscription-manager,
+// out of the offline installation.
+func onlyLocalRepo() string {
+	return fmt.Sprintf("--disablerepo='*' --enablerepo=%s", localRepo)
+}
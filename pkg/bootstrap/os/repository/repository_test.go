@@ -0,0 +1,49 @@
+/*
+ Copyright 2021 The KubeSphere Authors.
+
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+     http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package repository
+
+import (
+	"testing"
+
+	osrelease "github.com/dominodatalab/os-release"
+)
+
+func TestFamily(t *testing.T) {
+	tests := []struct {
+		name    string
+		release osrelease.Data
+		pkgTool string
+		want    string
+	}{
+		{"ubuntu", osrelease.Data{ID: "ubuntu", IDLike: "debian"}, "deb", Deb},
+		{"debian", osrelease.Data{ID: "debian"}, "deb", Deb},
+		{"rhel", osrelease.Data{ID: "rhel", IDLike: "fedora"}, "rpm", RPM},
+		{"rocky", osrelease.Data{ID: "rocky", IDLike: "rhel centos fedora"}, "rpm", RPM},
+		{"openEuler", osrelease.Data{ID: "openEuler"}, "rpm", RPM},
+		{"kylin server", osrelease.Data{ID: "kylin"}, "rpm", RPM},
+		{"kylin desktop", osrelease.Data{ID: "kylin", IDLike: "debian"}, "", Deb},
+		{"uos desktop", osrelease.Data{ID: "uos"}, "deb\n", Deb},
+		{"unknown", osrelease.Data{ID: "arch"}, "", ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Family(&tt.release, tt.pkgTool); got != tt.want {
+				t.Errorf("Family() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
@@ -22,6 +22,7 @@ import (
 	"github.com/kubesphere/kubekey/pkg/bootstrap/os/repository"
 	"github.com/kubesphere/kubekey/pkg/common"
 	"github.com/kubesphere/kubekey/pkg/core/connector"
+	"github.com/kubesphere/kubekey/pkg/core/util"
 	"github.com/kubesphere/kubekey/pkg/utils"
 	"github.com/pkg/errors"
 	"path/filepath"
@@ -343,8 +344,14 @@ func (s *SyncRepositoryFile) Execute(runtime connector.Runtime) error {
 	}
 	r := release.(*osrelease.Data)
 
-	fileName := fmt.Sprintf("%s-%s-%s.iso", r.ID, r.VersionID, host.GetArch())
-	src := filepath.Join(runtime.GetWorkDir(), "repository", host.GetArch(), r.ID, r.VersionID, fileName)
+	id := strings.ToLower(r.ID)
+	fileName, src := isoFile(runtime.GetWorkDir(), host.GetArch(), id, r.VersionID)
+	// fall back to the iso of the major version, e.g. rhel-8 for RHEL 8.6.
+	if i := strings.Index(r.VersionID, "."); i > 0 && !util.IsExist(src) {
+		if name, path := isoFile(runtime.GetWorkDir(), host.GetArch(), id, r.VersionID[:i]); util.IsExist(path) {
+			fileName, src = name, path
+		}
+	}
 	dst := filepath.Join(common.TmpDir, fileName)
 	if err := runtime.GetRunner().Scp(src, dst); err != nil {
 		return errors.Wrapf(errors.WithStack(err), "scp %s to %s failed", src, dst)
@@ -354,6 +361,12 @@ func (s *SyncRepositoryFile) Execute(runtime connector.Runtime) error {
 	return nil
 }
 
+// isoFile returns the name of the iso of the release and its path in the artifact.
+func isoFile(workDir, arch, id, version string) (string, string) {
+	fileName := fmt.Sprintf("%s-%s-%s.iso", id, version, arch)
+	return fileName, filepath.Join(workDir, "repository", arch, id, version, fileName)
+}
+
 type MountISO struct {
 	common.KubeAction
 }
@@ -385,8 +398,9 @@ func (b *BackupOriginalRepository) Execute(runtime connector.Runtime) error {
 		return errors.New("get os release failed by host cache")
 	}
 	r := release.(*osrelease.Data)
+	pkgTool, _ := host.GetCache().GetMustString(PkgTool)
 
-	repo, err := repository.New(r.ID, runtime)
+	repo, err := repository.New(r, pkgTool, runtime)
 	if err != nil {
 		return errors.Wrap(errors.WithStack(err), "new repository manager failed")
 	}
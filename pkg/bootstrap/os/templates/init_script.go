@@ -45,10 +45,11 @@ var InitOsScriptTmpl = template.Must(template.New("initOS.sh").Parse(
 
 swapoff -a
 sed -i /^[^#]*swap*/s/^/\#/g /etc/fstab
+{{- if ne .SELinux "keep" }}
 
 # See https://github.com/kubernetes/website/issues/14457
 if [ -f /etc/selinux/config ]; then 
-  sed -ri 's/SELINUX=enforcing/SELINUX=disabled/' /etc/selinux/config
+  sed -ri 's/SELINUX=enforcing/SELINUX={{ .SELinux }}/' /etc/selinux/config
 fi
 # for ubuntu: sudo apt install selinux-utils
 # for centos: yum install selinux-policy
@@ -57,71 +58,43 @@ then
   setenforce 0
   getenforce
 fi
-
-echo 'net.ipv4.ip_forward = 1' >> /etc/sysctl.conf
-echo 'net.bridge.bridge-nf-call-arptables = 1' >> /etc/sysctl.conf
-echo 'net.bridge.bridge-nf-call-ip6tables = 1' >> /etc/sysctl.conf
-echo 'net.bridge.bridge-nf-call-iptables = 1' >> /etc/sysctl.conf
-echo 'net.ipv4.ip_local_reserved_ports = 30000-32767' >> /etc/sysctl.conf
-echo 'vm.max_map_count = 262144' >> /etc/sysctl.conf
-echo 'vm.swappiness = 1' >> /etc/sysctl.conf
-echo 'fs.inotify.max_user_instances = 524288' >> /etc/sysctl.conf
-echo 'kernel.pid_max = 65535' >> /etc/sysctl.conf
-
-
-#See https://imroc.io/posts/kubernetes/troubleshooting-with-kubernetes-network/
-sed -r -i "s@#{0,}?net.ipv4.tcp_tw_recycle ?= ?(0|1)@net.ipv4.tcp_tw_recycle = 0@g" /etc/sysctl.conf
-
-sed -r -i  "s@#{0,}?net.ipv4.ip_forward ?= ?(0|1)@net.ipv4.ip_forward = 1@g" /etc/sysctl.conf
-sed -r -i  "s@#{0,}?net.bridge.bridge-nf-call-arptables ?= ?(0|1)@net.bridge.bridge-nf-call-arptables = 1@g" /etc/sysctl.conf
-sed -r -i  "s@#{0,}?net.bridge.bridge-nf-call-ip6tables ?= ?(0|1)@net.bridge.bridge-nf-call-ip6tables = 1@g" /etc/sysctl.conf
-sed -r -i  "s@#{0,}?net.bridge.bridge-nf-call-iptables ?= ?(0|1)@net.bridge.bridge-nf-call-iptables = 1@g" /etc/sysctl.conf
-sed -r -i  "s@#{0,}?net.ipv4.ip_local_reserved_ports ?= ?([0-9]{1,}-{0,1},{0,1}){1,}@net.ipv4.ip_local_reserved_ports = 30000-32767@g" /etc/sysctl.conf
-sed -r -i  "s@#{0,}?vm.max_map_count ?= ?([0-9]{1,})@vm.max_map_count = 262144@g" /etc/sysctl.conf
-sed -r -i  "s@#{0,}?vm.swappiness ?= ?([0-9]{1,})@vm.swappiness = 1@g" /etc/sysctl.conf
-sed -r -i  "s@#{0,}?fs.inotify.max_user_instances ?= ?([0-9]{1,})@fs.inotify.max_user_instances = 524288@g" /etc/sysctl.conf
-sed -r -i  "s@#{0,}?kernel.pid_max ?= ?([0-9]{1,})@kernel.pid_max = 65535@g" /etc/sysctl.conf
-
-awk ' !x[$0]++{print > "/etc/sysctl.conf"}' /etc/sysctl.conf
+{{- end }}
+{{- if eq .Firewall "disabled" }}
 
 systemctl stop firewalld 1>/dev/null 2>/dev/null
 systemctl disable firewalld 1>/dev/null 2>/dev/null
 systemctl stop ufw 1>/dev/null 2>/dev/null
 systemctl disable ufw 1>/dev/null 2>/dev/null
+{{- end }}
 
-modinfo br_netfilter > /dev/null 2>&1
-if [ $? -eq 0 ]; then
-   modprobe br_netfilter
-   mkdir -p /etc/modules-load.d
-   echo 'br_netfilter' > /etc/modules-load.d/kubekey-br_netfilter.conf
-fi
-
-modinfo overlay > /dev/null 2>&1
-if [ $? -eq 0 ]; then
-   modprobe overlay
-   echo 'overlay' > /etc/modules-load.d/kubekey-br_netfilter.conf
-fi
-
-modprobe ip_vs
-modprobe ip_vs_rr
-modprobe ip_vs_wrr
-modprobe ip_vs_sh
-
-cat > /etc/modules-load.d/kube_proxy-ipvs.conf << EOF
-ip_vs
-ip_vs_rr
-ip_vs_wrr
-ip_vs_sh
-EOF
-
-modprobe nf_conntrack_ipv4 1>/dev/null 2>/dev/null
-if [ $? -eq 0 ]; then
-   echo 'nf_conntrack_ipv4' > /etc/modules-load.d/kube_proxy-ipvs.conf
-else
-   modprobe nf_conntrack
-   echo 'nf_conntrack' > /etc/modules-load.d/kube_proxy-ipvs.conf
+# The kernel modules loaded by the earlier versions of kubekey are replaced by /etc/modules-load.d/kubekey.conf.
+rm -f /etc/modules-load.d/kubekey-br_netfilter.conf /etc/modules-load.d/kube_proxy-ipvs.conf
+mkdir -p /etc/modules-load.d
+: > /etc/modules-load.d/kubekey.conf
+for module in{{ range .KernelModules }} {{ . }}{{ end }}; do
+  if modprobe $module 1>/dev/null 2>/dev/null; then
+    echo $module >> /etc/modules-load.d/kubekey.conf
+  fi
+done
+for module in{{ range .ExtraKernelModules }} {{ . }}{{ end }}; do
+  if ! modprobe $module; then
+    echo "failed to load the kernel module $module" >&2
+    exit 1
+  fi
+  echo $module >> /etc/modules-load.d/kubekey.conf
+done
+
+# The kernel parameters are set in /etc/sysctl.d/99-kubekey-sysctl.conf, comment out the same ones in /etc/sysctl.conf
+# which is applied after the drop-in files, and the ones appended to it by the earlier versions of kubekey.
+if [ -f /etc/sysctl.conf ]; then
+{{- range .SysctlKeys }}
+  sed -r -i "s@^[[:space:]]*{{ . }}[[:space:]]*=@# &@" /etc/sysctl.conf
+{{- end }}
+{{- range .LegacySysctls }}
+  sed -r -i 's@^[[:space:]]*{{ . }}[[:space:]]*$@# &@' /etc/sysctl.conf
+{{- end }}
 fi
-sysctl -p
+sysctl --system
 
 sed -i ':a;$!{N;ba};s@# kubekey hosts BEGIN.*# kubekey hosts END@@' /etc/hosts
 sed -i '/^$/N;/\n$/N;//D' /etc/hosts
@@ -142,9 +115,6 @@ update-alternatives --set ip6tables /usr/sbin/ip6tables-legacy >/dev/null 2>&1 |
 update-alternatives --set arptables /usr/sbin/arptables-legacy >/dev/null 2>&1 || true
 update-alternatives --set ebtables /usr/sbin/ebtables-legacy >/dev/null 2>&1 || true
 
-ulimit -u 65535
-ulimit -n 65535
-
 crontab -l | grep -v '#' > /tmp/file1
 echo "0 3 * * * ps -A -ostat,ppid | grep -e '^[Zz]' | awk '{print $2}' | xargs kill -HUP > /dev/null 2>&1" >> /tmp/file1 && awk ' !x[$0]++{print > "/tmp/file1"}' /tmp/file1
 crontab /tmp/file1
@@ -0,0 +1,193 @@
+/*
+ Copyright 2021 The KubeSphere Authors.
+
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+     http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package templates
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"text/template"
+
+	"github.com/kubesphere/kubekey/pkg/common"
+	"github.com/lithammer/dedent"
+)
+
+var (
+	// defaultSysctls are the kernel parameters required by kubernetes and the network plugins.
+	defaultSysctls = map[string]string{
+		"net.ipv4.ip_forward":                 "1",
+		"net.bridge.bridge-nf-call-arptables": "1",
+		"net.bridge.bridge-nf-call-ip6tables": "1",
+		"net.bridge.bridge-nf-call-iptables":  "1",
+		"net.ipv4.ip_local_reserved_ports":    "30000-32767",
+		"vm.max_map_count":                    "262144",
+		"vm.swappiness":                       "1",
+		"fs.inotify.max_user_instances":       "524288",
+		"kernel.pid_max":                      "4194304",
+	}
+
+	// legacySysctls are the parameters which the earlier versions of kubekey appended to /etc/sysctl.conf.
+	legacySysctls = map[string]string{
+		"net.ipv4.ip_forward":                 "1",
+		"net.bridge.bridge-nf-call-arptables": "1",
+		"net.bridge.bridge-nf-call-ip6tables": "1",
+		"net.bridge.bridge-nf-call-iptables":  "1",
+		"net.ipv4.ip_local_reserved_ports":    "30000-32767",
+		"vm.max_map_count":                    "262144",
+		"vm.swappiness":                       "1",
+		"fs.inotify.max_user_instances":       "524288",
+		"kernel.pid_max":                      "65535",
+	}
+
+	// defaultKernelModules are loaded if they are available on the node, e.g. nf_conntrack_ipv4 only exists before linux 4.19.
+	defaultKernelModules = []string{
+		"br_netfilter",
+		"overlay",
+		"ip_vs",
+		"ip_vs_rr",
+		"ip_vs_wrr",
+		"ip_vs_sh",
+		"nf_conntrack_ipv4",
+		"nf_conntrack",
+	}
+
+	defaultUlimits = map[string]string{
+		"nofile": "65535",
+		"nproc":  "65535",
+	}
+)
+
+var SysctlConf = template.Must(template.New("99-kubekey-sysctl.conf").Parse(
+	dedent.Dedent(`# Managed by KubeKey, set the kernel parameters in the system section of the cluster config instead of editing this file.
+{{- range .Sysctls }}
+{{ . }}
+{{- end }}
+    `)))
+
+var LimitsConf = template.Must(template.New("99-kubekey-limits.conf").Parse(
+	dedent.Dedent(`# Managed by KubeKey, set the ulimits in the system section of the cluster config instead of editing this file.
+{{- range .Ulimits }}
+{{ . }}
+{{- end }}
+    `)))
+
+// GenerateSysctls returns the lines of the sysctl drop-in file, the parameters of the cluster config take precedence
+// over the defaults.
+func GenerateSysctls(kubeConf *common.KubeConf) []string {
+	sysctls := clusterSysctls(kubeConf)
+
+	var lines []string
+	for _, key := range sortedKeys(sysctls) {
+		lines = append(lines, fmt.Sprintf("%s = %s", key, sysctls[key]))
+	}
+	return lines
+}
+
+// GenerateSysctlKeys returns the escaped keys of the sysctl drop-in file, which are commented out in /etc/sysctl.conf
+// since it is applied after the drop-in files. The keys removed from the defaults are left to the user.
+func GenerateSysctlKeys(kubeConf *common.KubeConf) []string {
+	var keys []string
+	for _, key := range sortedKeys(clusterSysctls(kubeConf)) {
+		keys = append(keys, regexp.QuoteMeta(key))
+	}
+	return keys
+}
+
+// GenerateLegacySysctls returns the escaped patterns of the parameters appended to /etc/sysctl.conf by the earlier
+// versions of kubekey, e.g. kernel.pid_max = 65535, whose keys are not in the drop-in file. Only the lines with
+// the exact values kubekey wrote are commented out.
+func GenerateLegacySysctls(kubeConf *common.KubeConf) []string {
+	sysctls := clusterSysctls(kubeConf)
+
+	var patterns []string
+	for _, key := range sortedKeys(legacySysctls) {
+		if _, ok := sysctls[key]; ok {
+			continue
+		}
+		patterns = append(patterns, fmt.Sprintf("%s[[:space:]]*=[[:space:]]*%s",
+			regexp.QuoteMeta(key), regexp.QuoteMeta(legacySysctls[key])))
+	}
+	return patterns
+}
+
+func clusterSysctls(kubeConf *common.KubeConf) map[string]string {
+	sysctls := merge(defaultSysctls, kubeConf.Cluster.System.Sysctls)
+	if _, ok := kubeConf.Cluster.System.Sysctls["net.ipv6.conf.all.forwarding"]; !ok && kubeConf.Cluster.Network.PodCIDR(true) != "" {
+		sysctls["net.ipv6.conf.all.forwarding"] = "1"
+	}
+	return sysctls
+}
+
+// GenerateKernelModules returns the default kernel modules which are loaded if available, and the ones of the cluster
+// config which must be loaded.
+func GenerateKernelModules(kubeConf *common.KubeConf) (optional []string, required []string) {
+	required = kubeConf.Cluster.System.KernelModules
+	for _, module := range defaultKernelModules {
+		if !contains(required, module) {
+			optional = append(optional, module)
+		}
+	}
+	return optional, required
+}
+
+// GenerateUlimits returns the lines of the limits drop-in file. The wildcard domain does not apply to root,
+// so the limits are set for both.
+func GenerateUlimits(kubeConf *common.KubeConf) []string {
+	ulimits := merge(defaultUlimits, kubeConf.Cluster.System.Ulimits)
+
+	var lines []string
+	for _, domain := range []string{"*", "root"} {
+		for _, item := range sortedKeys(ulimits) {
+			lines = append(lines, fmt.Sprintf("%-6s -  %-8s %s", domain, item, ulimits[item]))
+		}
+	}
+	return lines
+}
+
+// merge returns a copy of the defaults overridden by the custom values, an empty custom value removes the key.
+func merge(defaults, custom map[string]string) map[string]string {
+	res := make(map[string]string, len(defaults)+len(custom))
+	for k, v := range defaults {
+		res[k] = v
+	}
+	for k, v := range custom {
+		if v == "" {
+			delete(res, k)
+			continue
+		}
+		res[k] = v
+	}
+	return res
+}
+
+func sortedKeys(m map[string]string) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+func contains(list []string, str string) bool {
+	for _, s := range list {
+		if s == str {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,234 @@
+/*
+ Copyright 2021 The KubeSphere Authors.
+
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+     http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package templates
+
+import (
+	"reflect"
+	"testing"
+
+	kubekeyapiv1alpha2 "github.com/kubesphere/kubekey/apis/kubekey/v1alpha2"
+	"github.com/kubesphere/kubekey/pkg/common"
+)
+
+func systemConf(system kubekeyapiv1alpha2.System, podCIDR string) *common.KubeConf {
+	return &common.KubeConf{Cluster: &kubekeyapiv1alpha2.ClusterSpec{
+		System:  system,
+		Network: kubekeyapiv1alpha2.NetworkConfig{KubePodsCIDR: podCIDR},
+	}}
+}
+
+func TestMerge(t *testing.T) {
+	tests := []struct {
+		name     string
+		defaults map[string]string
+		custom   map[string]string
+		want     map[string]string
+	}{
+		{
+			name:     "no custom values",
+			defaults: map[string]string{"a": "1", "b": "2"},
+			want:     map[string]string{"a": "1", "b": "2"},
+		},
+		{
+			name:     "override and add",
+			defaults: map[string]string{"a": "1", "b": "2"},
+			custom:   map[string]string{"b": "3", "c": "4"},
+			want:     map[string]string{"a": "1", "b": "3", "c": "4"},
+		},
+		{
+			name:     "empty value removes the default",
+			defaults: map[string]string{"a": "1", "b": "2"},
+			custom:   map[string]string{"a": "", "d": ""},
+			want:     map[string]string{"b": "2"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			defaults := make(map[string]string)
+			for k, v := range tt.defaults {
+				defaults[k] = v
+			}
+			if got := merge(tt.defaults, tt.custom); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("merge() = %v, want %v", got, tt.want)
+			}
+			if !reflect.DeepEqual(tt.defaults, defaults) {
+				t.Errorf("merge() modified the defaults: %v", tt.defaults)
+			}
+		})
+	}
+}
+
+func TestGenerateSysctls(t *testing.T) {
+	tests := []struct {
+		name        string
+		sysctls     map[string]string
+		podCIDR     string
+		contains    []string
+		notContains []string
+		keys        []string
+		notKeys     []string
+		legacy      []string
+		notLegacy   []string
+	}{
+		{
+			name:     "defaults",
+			podCIDR:  "10.233.64.0/18",
+			contains: []string{"kernel.pid_max = 4194304", "net.ipv4.ip_forward = 1"},
+			notContains: []string{
+				"net.ipv6.conf.all.forwarding = 1",
+			},
+			keys:      []string{`kernel\.pid_max`, `net\.ipv4\.ip_forward`},
+			notLegacy: []string{`kernel\.pid_max[[:space:]]*=[[:space:]]*65535`},
+		},
+		{
+			name:        "custom values override the defaults",
+			sysctls:     map[string]string{"kernel.pid_max": "65535", "net.core.somaxconn": "32768"},
+			podCIDR:     "10.233.64.0/18",
+			contains:    []string{"kernel.pid_max = 65535", "net.core.somaxconn = 32768"},
+			notContains: []string{"kernel.pid_max = 4194304"},
+			keys:        []string{`net\.core\.somaxconn`},
+		},
+		{
+			name:        "removed default is left to the user",
+			sysctls:     map[string]string{"kernel.pid_max": "", "vm.swappiness": ""},
+			podCIDR:     "10.233.64.0/18",
+			notContains: []string{"kernel.pid_max = 4194304", "vm.swappiness = 1"},
+			notKeys:     []string{`kernel\.pid_max`, `vm\.swappiness`},
+			legacy: []string{
+				`kernel\.pid_max[[:space:]]*=[[:space:]]*65535`,
+				`vm\.swappiness[[:space:]]*=[[:space:]]*1`,
+			},
+		},
+		{
+			name:     "ipv6 forwarding for the ipv6 pod cidr",
+			podCIDR:  "10.233.64.0/18,fd85:ee78:d8a6:8607::1:0000/112",
+			contains: []string{"net.ipv6.conf.all.forwarding = 1"},
+			keys:     []string{`net\.ipv6\.conf\.all\.forwarding`},
+		},
+		{
+			name:        "ipv6 forwarding is removable",
+			sysctls:     map[string]string{"net.ipv6.conf.all.forwarding": ""},
+			podCIDR:     "fd85:ee78:d8a6:8607::1:0000/112",
+			notContains: []string{"net.ipv6.conf.all.forwarding = 1"},
+			notKeys:     []string{`net\.ipv6\.conf\.all\.forwarding`},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			kubeConf := systemConf(kubekeyapiv1alpha2.System{Sysctls: tt.sysctls}, tt.podCIDR)
+			lines := GenerateSysctls(kubeConf)
+			keys := GenerateSysctlKeys(kubeConf)
+			legacy := GenerateLegacySysctls(kubeConf)
+			for _, l := range tt.contains {
+				if !contains(lines, l) {
+					t.Errorf("GenerateSysctls() = %v, want to contain %q", lines, l)
+				}
+			}
+			for _, l := range tt.notContains {
+				if contains(lines, l) {
+					t.Errorf("GenerateSysctls() = %v, want not to contain %q", lines, l)
+				}
+			}
+			for _, k := range tt.keys {
+				if !contains(keys, k) {
+					t.Errorf("GenerateSysctlKeys() = %v, want to contain %q", keys, k)
+				}
+			}
+			for _, k := range tt.notKeys {
+				if contains(keys, k) {
+					t.Errorf("GenerateSysctlKeys() = %v, want not to contain %q", keys, k)
+				}
+			}
+			for _, p := range tt.legacy {
+				if !contains(legacy, p) {
+					t.Errorf("GenerateLegacySysctls() = %v, want to contain %q", legacy, p)
+				}
+			}
+			for _, p := range tt.notLegacy {
+				if contains(legacy, p) {
+					t.Errorf("GenerateLegacySysctls() = %v, want not to contain %q", legacy, p)
+				}
+			}
+		})
+	}
+}
+
+func TestGenerateKernelModules(t *testing.T) {
+	tests := []struct {
+		name         string
+		modules      []string
+		wantOptional []string
+		wantRequired []string
+	}{
+		{
+			name:         "defaults",
+			wantOptional: defaultKernelModules,
+		},
+		{
+			name:         "extra modules are required",
+			modules:      []string{"nf_nat", "br_netfilter"},
+			wantOptional: []string{"overlay", "ip_vs", "ip_vs_rr", "ip_vs_wrr", "ip_vs_sh", "nf_conntrack_ipv4", "nf_conntrack"},
+			wantRequired: []string{"nf_nat", "br_netfilter"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			optional, required := GenerateKernelModules(systemConf(kubekeyapiv1alpha2.System{KernelModules: tt.modules}, ""))
+			if !reflect.DeepEqual(optional, tt.wantOptional) {
+				t.Errorf("GenerateKernelModules() optional = %v, want %v", optional, tt.wantOptional)
+			}
+			if !reflect.DeepEqual(required, tt.wantRequired) {
+				t.Errorf("GenerateKernelModules() required = %v, want %v", required, tt.wantRequired)
+			}
+		})
+	}
+}
+
+func TestGenerateUlimits(t *testing.T) {
+	tests := []struct {
+		name    string
+		ulimits map[string]string
+		want    []string
+	}{
+		{
+			name: "defaults",
+			want: []string{
+				"*      -  nofile   65535",
+				"*      -  nproc    65535",
+				"root   -  nofile   65535",
+				"root   -  nproc    65535",
+			},
+		},
+		{
+			name:    "override, add and remove",
+			ulimits: map[string]string{"nofile": "1048576", "nproc": "", "memlock": "unlimited"},
+			want: []string{
+				"*      -  memlock  unlimited",
+				"*      -  nofile   1048576",
+				"root   -  memlock  unlimited",
+				"root   -  nofile   1048576",
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := GenerateUlimits(systemConf(kubekeyapiv1alpha2.System{Ulimits: tt.ulimits}, "")); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("GenerateUlimits() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
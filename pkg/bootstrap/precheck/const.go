@@ -27,6 +27,7 @@ const (
 	conntrack = "conntrack"
 	chrony    = "chronyd"
 	docker    = "docker"
+	isula     = "isula"
 	showmount = "showmount"
 	rbd       = "rbd"
 	glusterfs = "glusterfs"
@@ -49,6 +50,7 @@ var baseSoftware = []string{
 	conntrack,
 	chrony,
 	docker,
+	isula,
 	showmount,
 	rbd,
 	glusterfs,
@@ -31,6 +31,10 @@ func (n *NodePreCheckModule) IsSkip() bool {
 	return n.Skip
 }
 
+func (n *NodePreCheckModule) IsAlwaysRun() bool {
+	return true
+}
+
 func (n *NodePreCheckModule) Init() {
 	n.Name = "NodePreCheckModule"
 	n.Desc = "Do pre-check on cluster nodes"
@@ -60,6 +64,10 @@ type ClusterPreCheckModule struct {
 	common.KubeModule
 }
 
+func (c *ClusterPreCheckModule) IsAlwaysRun() bool {
+	return true
+}
+
 func (c *ClusterPreCheckModule) Init() {
 	c.Name = "ClusterPreCheckModule"
 	c.Desc = "Do pre-check on cluster"
@@ -57,6 +57,14 @@ func (n *NodePreCheck) Execute(runtime connector.Runtime) error {
 					results[software] = dockerVersion
 				}
 			}
+			if software == isula {
+				isulaVersion, err := runtime.GetRunner().SudoCmd("isula --version | grep -oE '[0-9]+\\.[0-9]+\\.[0-9]+' | head -n 1", false)
+				if err != nil || isulaVersion == "" {
+					results[software] = UnknownVersion
+				} else {
+					results[software] = isulaVersion
+				}
+			}
 		}
 	}
 
@@ -133,6 +141,7 @@ func (g *GetAllNodesK8sVersion) Execute(runtime connector.Runtime) error {
 	nodeK8sVersion = strings.Split(kubeletVersionInfo, " ")[1]
 
 	host := runtime.RemoteHost()
+	host.GetCache().Set(common.KubeletVersion, nodeK8sVersion)
 	if host.IsRole(common.Master) {
 		apiserverVersion, err := runtime.GetRunner().SudoCmd(
 			"cat /etc/kubernetes/manifests/kube-apiserver.yaml | grep 'image:' | rev | cut -d ':' -f1 | rev",
@@ -76,9 +76,9 @@ func (s *SyncCertsToAllNodes) Execute(runtime connector.Runtime) error {
 	case common.Conatinerd:
 		dstDir = common.RegistryCertDir
 	case common.Crio:
-		// TODO: Add the steps of cri-o's installation.
+		dstDir = fmt.Sprintf("/etc/containers/certs.d/%s", RegistryCertificateBaseName)
 	case common.Isula:
-		// TODO: Add the steps of iSula's installation.
+		dstDir = fmt.Sprintf("/etc/isulad/certs.d/%s", RegistryCertificateBaseName)
 	default:
 		logger.Log.Fatalf("Unsupported container runtime: %s", strings.TrimSpace(s.KubeConf.Cluster.Kubernetes.ContainerManager))
 	}
@@ -26,6 +26,14 @@ const (
 	File     = "file"
 	Operator = "operator"
 
+	// OperatorNamespace is the namespace where the cluster controller runs kk.
+	OperatorNamespace = "kubekey-system"
+	// ClusterConfigKey is the key of the cluster config in the ConfigMap written by the cluster controller.
+	ClusterConfigKey = "cluster.yaml"
+
+	OutputText = "text"
+	OutputJSON = "json"
+
 	Master        = "master"
 	Worker        = "worker"
 	ETCD          = "etcd"
@@ -74,15 +82,23 @@ const (
 	DesiredK8sVersion = "desiredK8sVersion"
 	PlanK8sVersion    = "planK8sVersion"
 	NodeK8sVersion    = "NodeK8sVersion"
+	KubeletVersion    = "kubeletVersion"
+
+	// NetworkPluginOutdated marks the network plugin to be redeployed in the upgrade hop.
+	NetworkPluginOutdated = "networkPluginOutdated"
 
 	// ETCDModule
-	ETCDCluster = "etcdCluster"
-	ETCDName    = "etcdName"
-	ETCDExist   = "etcdExist"
+	ETCDCluster  = "etcdCluster"
+	ETCDName     = "etcdName"
+	ETCDExist    = "etcdExist"
+	ETCDMemberID = "etcdMemberID"
+	ETCDDataBak  = "etcdDataBak"
+	ETCDVersion  = "etcdVersion"
 
 	// KubernetesModule
-	ClusterStatus = "clusterStatus"
-	ClusterExist  = "clusterExist"
+	ClusterStatus   = "clusterStatus"
+	ClusterExist    = "clusterExist"
+	MissingCertSANs = "missingCertSANs"
 
 	// CertsModule
 	Certificate   = "certificate"
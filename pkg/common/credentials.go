@@ -0,0 +1,155 @@
+/*
+ Copyright 2021 The KubeSphere Authors.
+
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+     http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package common
+
+import (
+	"context"
+	"fmt"
+	kubekeyapiv1alpha2 "github.com/kubesphere/kubekey/apis/kubekey/v1alpha2"
+	"github.com/kubesphere/kubekey/pkg/core/util"
+	"github.com/pkg/errors"
+	"io/ioutil"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+	"os"
+	"os/exec"
+	"strings"
+)
+
+// CredentialResolver resolves the credential references of the hosts and the bastions when the config is loaded,
+// so that the cluster config does not need to contain any plaintext password or private key.
+type CredentialResolver struct {
+	// Client is used to read the SecretKeyRef, it is only set when kk runs inside the cluster.
+	Client    kubernetes.Interface
+	Namespace string
+}
+
+func (r *CredentialResolver) Resolve(cluster *kubekeyapiv1alpha2.Cluster) error {
+	if err := r.resolveBastion(&cluster.Spec.Bastion); err != nil {
+		return errors.Wrap(err, "Failed to resolve the credentials of the bastion")
+	}
+	for i := range cluster.Spec.Hosts {
+		host := &cluster.Spec.Hosts[i]
+		if host.PasswordFrom != nil {
+			password, err := r.resolveValue(host.PasswordFrom)
+			if err != nil {
+				return errors.Wrapf(err, "Failed to resolve the password of the host %s", host.Name)
+			}
+			host.Password = password
+		}
+		if host.PrivateKeyFrom != nil {
+			privateKey, err := r.resolveValue(host.PrivateKeyFrom)
+			if err != nil {
+				return errors.Wrapf(err, "Failed to resolve the private key of the host %s", host.Name)
+			}
+			host.PrivateKey = privateKey
+		}
+		if err := r.resolveBastion(&host.Bastion); err != nil {
+			return errors.Wrapf(err, "Failed to resolve the credentials of the bastion of the host %s", host.Name)
+		}
+	}
+	return nil
+}
+
+func (r *CredentialResolver) resolveBastion(bastion *kubekeyapiv1alpha2.BastionCfg) error {
+	if bastion.PasswordFrom != nil {
+		password, err := r.resolveValue(bastion.PasswordFrom)
+		if err != nil {
+			return err
+		}
+		bastion.Password = password
+	}
+	if bastion.PrivateKeyFrom != nil {
+		privateKey, err := r.resolveValue(bastion.PrivateKeyFrom)
+		if err != nil {
+			return err
+		}
+		bastion.PrivateKey = privateKey
+	}
+	return nil
+}
+
+func (r *CredentialResolver) resolveValue(source *kubekeyapiv1alpha2.ValueSource) (string, error) {
+	set := 0
+	for _, ok := range []bool{source.Env != "", source.File != "", source.SopsFileRef != nil, source.SecretKeyRef != nil} {
+		if ok {
+			set++
+		}
+	}
+	if set != 1 {
+		return "", errors.New("exactly one of env, file, sopsFileRef and secretKeyRef must be set")
+	}
+
+	switch {
+	case source.Env != "":
+		value, ok := os.LookupEnv(source.Env)
+		if !ok {
+			return "", errors.Errorf("the environment variable %s is not set", source.Env)
+		}
+		return value, nil
+	case source.File != "":
+		content, err := ioutil.ReadFile(expandHome(source.File))
+		if err != nil {
+			return "", errors.Wrapf(err, "Failed to read the file %s", source.File)
+		}
+		return strings.TrimRight(string(content), "\r\n"), nil
+	case source.SopsFileRef != nil:
+		return decryptSopsFile(source.SopsFileRef)
+	default:
+		return r.resolveSecretKeyRef(source.SecretKeyRef)
+	}
+}
+
+func (r *CredentialResolver) resolveSecretKeyRef(ref *kubekeyapiv1alpha2.SecretKeySelector) (string, error) {
+	if r.Client == nil {
+		return "", errors.Errorf("the secretKeyRef %s is only supported when kk runs inside the cluster", ref.Name)
+	}
+	secret, err := r.Client.CoreV1().Secrets(r.Namespace).Get(context.TODO(), ref.Name, metav1.GetOptions{})
+	if err != nil {
+		return "", errors.Wrapf(err, "Failed to get the Secret %s/%s", r.Namespace, ref.Name)
+	}
+	value, ok := secret.Data[ref.Key]
+	if !ok {
+		return "", errors.Errorf("the key %s is not found in the Secret %s/%s", ref.Key, r.Namespace, ref.Name)
+	}
+	return string(value), nil
+}
+
+// decryptSopsFile extracts a top-level key of a sops encrypted file. The sops binary reads the decryption keys
+// from its own environment, e.g. SOPS_AGE_KEY_FILE for age or the credentials of the cloud KMS.
+func decryptSopsFile(ref *kubekeyapiv1alpha2.SopsFileSelector) (string, error) {
+	if _, err := exec.LookPath("sops"); err != nil {
+		return "", errors.New("the sops binary is required to decrypt the sopsFileRef, please install it first")
+	}
+	path := expandHome(ref.Path)
+	output, err := exec.Command("sops", "--decrypt", "--extract", fmt.Sprintf("[%q]", ref.Key), path).Output()
+	if err != nil {
+		if exitErr, ok := err.(*exec.ExitError); ok {
+			return "", errors.Errorf("Failed to decrypt the key %s of the file %s: %s", ref.Key, path, strings.TrimSpace(string(exitErr.Stderr)))
+		}
+		return "", errors.Wrapf(err, "Failed to decrypt the key %s of the file %s", ref.Key, path)
+	}
+	return strings.TrimRight(string(output), "\r\n"), nil
+}
+
+func expandHome(path string) string {
+	if strings.HasPrefix(strings.TrimSpace(path), "~/") {
+		homeDir, _ := util.Home()
+		return strings.Replace(path, "~/", fmt.Sprintf("%s/", homeDir), 1)
+	}
+	return path
+}
@@ -118,3 +118,16 @@ func (o *OnlyKubernetes) PreCheck(_ connector.Runtime) (bool, error) {
 	}
 	return false, nil
 }
+
+// OnlyNode matches the host which is specified by the node name argument, such as the node to be deleted.
+type OnlyNode struct {
+	KubePrepare
+	Not bool
+}
+
+func (o *OnlyNode) PreCheck(runtime connector.Runtime) (bool, error) {
+	if runtime.RemoteHost().GetName() == o.KubeConf.Arg.NodeName {
+		return !o.Not, nil
+	}
+	return o.Not, nil
+}
@@ -17,9 +17,15 @@
 package common
 
 import (
+	"crypto/sha256"
+	"encoding/json"
+	"fmt"
+
 	kubekeyapiv1alpha2 "github.com/kubesphere/kubekey/apis/kubekey/v1alpha2"
 	kubekeyclientset "github.com/kubesphere/kubekey/clients/clientset/versioned"
 	"github.com/kubesphere/kubekey/pkg/core/connector"
+	"github.com/kubesphere/kubekey/pkg/core/event"
+	"github.com/pkg/errors"
 )
 
 type KubeRuntime struct {
@@ -34,6 +40,7 @@ type KubeRuntime struct {
 type Argument struct {
 	NodeName           string
 	FilePath           string
+	ClusterName        string
 	KubernetesVersion  string
 	KsEnable           bool
 	KsVersion          string
@@ -44,7 +51,7 @@ type Argument struct {
 	AddImagesRepo      bool
 	DeployLocalStorage *bool
 	SourcesDir         string
-	DownloadCommand    func(path, url string) string
+	DownloadCommand    func(path, url string) string `json:"-"`
 	SkipConfirmCheck   bool
 	InCluster          bool
 	ContainerManager   string
@@ -53,6 +60,13 @@ type Argument struct {
 	Artifact           string
 	InstallPackages    bool
 	CertificatesDir    string
+	Resume             bool
+	DryRun             bool
+	MaxParallel        int
+	Output             string
+	OutputFile         string
+	Snapshot           string
+	SnapshotDir        string
 }
 
 func NewKubeRuntime(flag string, arg Argument) (*KubeRuntime, error) {
@@ -92,10 +106,68 @@ func NewKubeRuntime(flag string, arg Argument) (*KubeRuntime, error) {
 		Arg:         arg,
 	}
 	r.BaseRuntime = base
+	if arg.MaxParallel > 0 {
+		r.SetMaxParallel(arg.MaxParallel)
+	} else {
+		r.SetMaxParallel(defaultCluster.Execution.MaxParallel)
+	}
 
+	// the sinks are closed when the pipeline finishes.
+	if err := initEventSink(arg); err != nil {
+		return nil, err
+	}
 	return r, nil
 }
 
+// Fingerprint returns the hash of the cluster config and the arguments which change what the pipeline does,
+// a checkpoint is only resumed by a run with the same fingerprint.
+func (k *KubeRuntime) Fingerprint() (string, error) {
+	arg := k.Arg
+	// they only change how the pipeline runs or reports
+	arg.Debug = false
+	arg.IgnoreErr = false
+	arg.SkipConfirmCheck = false
+	arg.Resume = false
+	arg.DryRun = false
+	arg.MaxParallel = 0
+	arg.Output = ""
+	arg.OutputFile = ""
+
+	content, err := json.Marshal(struct {
+		Cluster *kubekeyapiv1alpha2.ClusterSpec
+		Arg     Argument
+	}{k.Cluster, arg})
+	if err != nil {
+		return "", errors.Wrap(err, "marshal the config and the arguments failed")
+	}
+	return fmt.Sprintf("%x", sha256.Sum256(content)), nil
+}
+
+func initEventSink(arg Argument) error {
+	var sinks event.MultiSink
+	switch arg.Output {
+	case "", OutputText:
+	case OutputJSON:
+		sinks = append(sinks, event.NewJSONStdoutSink())
+	default:
+		return errors.Errorf("unsupported output format %s, it should be %s or %s", arg.Output, OutputText, OutputJSON)
+	}
+
+	if arg.OutputFile != "" {
+		s, err := event.NewJSONFileSink(arg.OutputFile)
+		if err != nil {
+			_ = sinks.Close()
+			return errors.Wrapf(err, "open event output file %s failed", arg.OutputFile)
+		}
+		sinks = append(sinks, s)
+	}
+
+	if len(sinks) > 0 {
+		event.Default = sinks
+	}
+	return nil
+}
+
 // Copy is used to create a copy for Runtime.
 func (k *KubeRuntime) Copy() connector.Runtime {
 	runtime := *k
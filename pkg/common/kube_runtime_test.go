@@ -0,0 +1,50 @@
+/*
+ Copyright 2021 The KubeSphere Authors.
+
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+     http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package common
+
+import (
+	"testing"
+
+	kubekeyapiv1alpha2 "github.com/kubesphere/kubekey/apis/kubekey/v1alpha2"
+)
+
+func TestKubeRuntime_Fingerprint(t *testing.T) {
+	newRuntime := func(version string, arg Argument) *KubeRuntime {
+		cluster := &kubekeyapiv1alpha2.ClusterSpec{}
+		cluster.Kubernetes.Version = version
+		arg.DownloadCommand = func(path, url string) string { return "" }
+		return &KubeRuntime{Cluster: cluster, Arg: arg}
+	}
+	fingerprint := func(r *KubeRuntime) string {
+		f, err := r.Fingerprint()
+		if err != nil {
+			t.Fatal(err)
+		}
+		return f
+	}
+
+	base := fingerprint(newRuntime("v1.21.5", Argument{}))
+	if f := fingerprint(newRuntime("v1.21.5", Argument{Resume: true, Debug: true, MaxParallel: 5})); f != base {
+		t.Errorf("the arguments which only change how the pipeline runs should not change the fingerprint")
+	}
+	if f := fingerprint(newRuntime("v1.22.1", Argument{})); f == base {
+		t.Errorf("the changed config should change the fingerprint")
+	}
+	if f := fingerprint(newRuntime("v1.21.5", Argument{SkipPullImages: true})); f == base {
+		t.Errorf("the changed arguments should change the fingerprint")
+	}
+}
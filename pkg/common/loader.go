@@ -18,6 +18,7 @@ package common
 
 import (
 	"bufio"
+	"context"
 	"encoding/json"
 	"fmt"
 	kubekeyapiv1alpha2 "github.com/kubesphere/kubekey/apis/kubekey/v1alpha2"
@@ -25,7 +26,10 @@ import (
 	"github.com/kubesphere/kubekey/pkg/version/kubesphere"
 	"github.com/pkg/errors"
 	"gopkg.in/yaml.v2"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	k8syaml "k8s.io/apimachinery/pkg/util/yaml"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/rest"
 	"os"
 	"os/exec"
 	"os/user"
@@ -46,7 +50,7 @@ func NewLoader(flag string, arg Argument) Loader {
 	case File:
 		return NewFileLoader(arg)
 	case Operator:
-		return &ConfigMapLoader{}
+		return NewConfigMapLoader(arg)
 	case AllInOne:
 		return NewDefaultLoader(arg)
 	default:
@@ -244,6 +248,11 @@ func (f FileLoader) Load() (*kubekeyapiv1alpha2.Cluster, error) {
 		}
 	}
 
+	resolver := &CredentialResolver{}
+	if err := resolver.Resolve(&clusterCfg); err != nil {
+		return nil, err
+	}
+
 	if f.arg.ContainerManager != "" && f.arg.ContainerManager != Docker {
 		clusterCfg.Spec.Kubernetes.ContainerManager = f.arg.ContainerManager
 	}
@@ -252,11 +261,68 @@ func (f FileLoader) Load() (*kubekeyapiv1alpha2.Cluster, error) {
 	return &clusterCfg, nil
 }
 
+// ConfigMapLoader loads the cluster config from the ConfigMap which is written by the cluster controller,
+// it is used when kk runs as a job inside the cluster.
 type ConfigMapLoader struct {
+	arg       Argument
+	Name      string
+	Namespace string
+	Client    kubernetes.Interface
 }
 
-func (c ConfigMapLoader) Load() (*kubekeyapiv1alpha2.Cluster, error) {
-	return nil, nil
+func NewConfigMapLoader(arg Argument) *ConfigMapLoader {
+	return &ConfigMapLoader{
+		arg:       arg,
+		Name:      arg.ClusterName,
+		Namespace: OperatorNamespace,
+	}
+}
+
+func (c *ConfigMapLoader) Load() (*kubekeyapiv1alpha2.Cluster, error) {
+	if c.Name == "" {
+		return nil, errors.New("the cluster name is required to load the config from the ConfigMap")
+	}
+	if c.Client == nil {
+		config, err := rest.InClusterConfig()
+		if err != nil {
+			return nil, errors.Wrap(err, "Failed to get the in-cluster config")
+		}
+		client, err := kubernetes.NewForConfig(config)
+		if err != nil {
+			return nil, errors.Wrap(err, "Failed to create the kubernetes client")
+		}
+		c.Client = client
+	}
+
+	cm, err := c.Client.CoreV1().ConfigMaps(c.Namespace).Get(context.TODO(), c.Name, metav1.GetOptions{})
+	if err != nil {
+		return nil, errors.Wrapf(err, "Failed to get the ConfigMap %s/%s", c.Namespace, c.Name)
+	}
+	content, ok := cm.Data[ClusterConfigKey]
+	if !ok {
+		return nil, errors.Errorf("%s is not found in the ConfigMap %s/%s", ClusterConfigKey, c.Namespace, c.Name)
+	}
+
+	clusterCfg := kubekeyapiv1alpha2.Cluster{}
+	contentToJson, err := k8syaml.ToJSON([]byte(content))
+	if err != nil {
+		return nil, errors.Wrap(err, "Unable to convert configuration to json")
+	}
+	if err := json.Unmarshal(contentToJson, &clusterCfg); err != nil {
+		return nil, errors.Wrap(err, "Failed to unmarshal configuration")
+	}
+
+	resolver := &CredentialResolver{Client: c.Client, Namespace: c.Namespace}
+	if err := resolver.Resolve(&clusterCfg); err != nil {
+		return nil, err
+	}
+
+	if c.arg.ContainerManager != "" && c.arg.ContainerManager != Docker {
+		clusterCfg.Spec.Kubernetes.ContainerManager = c.arg.ContainerManager
+	}
+
+	clusterCfg.Name = c.Name
+	return &clusterCfg, nil
 }
 
 func defaultKSConfig(ks *kubekeyapiv1alpha2.KubeSphere, version string) error {
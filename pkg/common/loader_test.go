@@ -0,0 +1,149 @@
+/*
+ Copyright 2021 The KubeSphere Authors.
+
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+     http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package common
+
+import (
+	"io/ioutil"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+const clusterYaml = `apiVersion: kubekey.kubesphere.io/v1alpha2
+kind: Cluster
+metadata:
+  name: sample
+spec:
+  hosts:
+  - name: node1
+    address: 192.168.0.2
+    internalAddress: 192.168.0.2
+    user: root
+    passwordFrom:
+      secretKeyRef:
+        name: node-credentials
+        key: password
+  - name: node2
+    address: 192.168.0.3
+    internalAddress: 192.168.0.3
+    user: root
+    password: plaintext
+  roleGroups:
+    etcd:
+    - node1
+    master:
+    - node1
+    worker:
+    - node2
+  kubernetes:
+    version: v1.21.5
+`
+
+func TestConfigMapLoader(t *testing.T) {
+	cm := &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{Name: "sample", Namespace: OperatorNamespace},
+		Data:       map[string]string{ClusterConfigKey: clusterYaml},
+	}
+	secret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: "node-credentials", Namespace: OperatorNamespace},
+		Data:       map[string][]byte{"password": []byte("secret-password")},
+	}
+
+	loader := NewConfigMapLoader(Argument{ClusterName: "sample"})
+	loader.Client = fake.NewSimpleClientset(cm, secret)
+	cluster, err := loader.Load()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cluster.Name != "sample" || cluster.Spec.Kubernetes.Version != "v1.21.5" || len(cluster.Spec.Hosts) != 2 {
+		t.Fatalf("unexpected cluster %+v", cluster)
+	}
+	if cluster.Spec.Hosts[0].Password != "secret-password" {
+		t.Errorf("the password of node1 should be resolved from the secret, got %q", cluster.Spec.Hosts[0].Password)
+	}
+	if cluster.Spec.Hosts[1].Password != "plaintext" {
+		t.Errorf("the password of node2 should be kept, got %q", cluster.Spec.Hosts[1].Password)
+	}
+
+	// The referenced key is missing.
+	secret.Data = map[string][]byte{"privateKey": []byte("key")}
+	loader = NewConfigMapLoader(Argument{ClusterName: "sample"})
+	loader.Client = fake.NewSimpleClientset(cm, secret)
+	if _, err := loader.Load(); err == nil {
+		t.Errorf("the missing key of the secret should fail the loading")
+	}
+
+	// The ConfigMap is missing.
+	loader = NewConfigMapLoader(Argument{ClusterName: "other"})
+	loader.Client = fake.NewSimpleClientset(cm, secret)
+	if _, err := loader.Load(); err == nil {
+		t.Errorf("the missing ConfigMap should fail the loading")
+	}
+}
+
+func TestFileLoaderCredentials(t *testing.T) {
+	dir := t.TempDir()
+	keyFile := filepath.Join(dir, "id_rsa")
+	if err := ioutil.WriteFile(keyFile, []byte("private-key\n"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("KK_TEST_PASSWORD", "env-password")
+
+	content := `apiVersion: kubekey.kubesphere.io/v1alpha2
+kind: Cluster
+metadata:
+  name: sample
+spec:
+  hosts:
+  - {name: node1, address: 192.168.0.2, passwordFrom: {env: KK_TEST_PASSWORD}}
+  - {name: node2, address: 192.168.0.3, privateKeyFrom: {file: ` + keyFile + `}}
+  bastion:
+    address: 192.168.1.2
+    passwordFrom: {env: KK_TEST_PASSWORD}
+`
+	clusterFile := filepath.Join(dir, "config.yaml")
+	if err := ioutil.WriteFile(clusterFile, []byte(content), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	cluster, err := NewFileLoader(Argument{FilePath: clusterFile}).Load()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cluster.Spec.Hosts[0].Password != "env-password" {
+		t.Errorf("the password of node1 should be read from the environment variable, got %q", cluster.Spec.Hosts[0].Password)
+	}
+	if cluster.Spec.Hosts[1].PrivateKey != "private-key" {
+		t.Errorf("the private key of node2 should be read from the file, got %q", cluster.Spec.Hosts[1].PrivateKey)
+	}
+	if cluster.Spec.Bastion.Password != "env-password" {
+		t.Errorf("the password of the bastion should be read from the environment variable, got %q", cluster.Spec.Bastion.Password)
+	}
+
+	// The Secret can not be read without the operator.
+	content = strings.Replace(content, "  bastion:", "  - {name: node3, address: 192.168.0.4, passwordFrom: {secretKeyRef: {name: node-credentials, key: password}}}\n  bastion:", 1)
+	if err := ioutil.WriteFile(clusterFile, []byte(content), 0600); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := NewFileLoader(Argument{FilePath: clusterFile}).Load(); err == nil {
+		t.Errorf("the secretKeyRef should fail the loading outside of the cluster")
+	}
+}
@@ -16,6 +16,22 @@
 
 package container
 
+import "github.com/kubesphere/kubekey/pkg/common"
+
 const (
 	DefaultContainerdCRISocket = "/run/containerd/containerd.sock"
 )
+
+// ServiceName returns the name of the systemd service of the container manager.
+func ServiceName(containerManager string) string {
+	switch containerManager {
+	case common.Conatinerd:
+		return "containerd"
+	case common.Crio:
+		return "crio"
+	case common.Isula:
+		return "isulad"
+	default:
+		return "docker"
+	}
+}
@@ -0,0 +1,78 @@
+/*
+ Copyright 2021 The KubeSphere Authors.
+
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+     http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package container
+
+import (
+	"fmt"
+	"path/filepath"
+
+	"github.com/kubesphere/kubekey/pkg/common"
+	"github.com/kubesphere/kubekey/pkg/core/connector"
+	"github.com/kubesphere/kubekey/pkg/files"
+	"github.com/kubesphere/kubekey/pkg/utils"
+	"github.com/pkg/errors"
+)
+
+type SyncCrioBinaries struct {
+	common.KubeAction
+}
+
+func (s *SyncCrioBinaries) Execute(runtime connector.Runtime) error {
+	if err := utils.ResetTmpDir(runtime); err != nil {
+		return err
+	}
+
+	binariesMapObj, ok := s.PipelineCache.Get(common.KubeBinaries + "-" + runtime.RemoteHost().GetArch())
+	if !ok {
+		return errors.New("get KubeBinary by pipeline cache failed")
+	}
+	binariesMap := binariesMapObj.(map[string]*files.KubeBinary)
+
+	crio, ok := binariesMap[common.Crio]
+	if !ok {
+		return errors.New("get KubeBinary key crio by pipeline cache failed")
+	}
+
+	dst := filepath.Join(common.TmpDir, crio.FileName)
+	if err := runtime.GetRunner().Scp(crio.Path(), dst); err != nil {
+		return errors.Wrap(errors.WithStack(err), fmt.Sprintf("sync crio binaries failed"))
+	}
+
+	// The crictl shipped in the cri-o bundle is skipped, it is installed by SyncCrictlBinaries.
+	if _, err := runtime.GetRunner().SudoCmd(
+		fmt.Sprintf("cd %s && tar -zxf %s && "+
+			"mkdir -p /usr/bin /etc/crio /etc/containers /var/lib/crio && "+
+			"find cri-o/bin -type f ! -name crictl -exec install -m 0755 {} /usr/bin/ \\; && "+
+			"rm -rf cri-o", common.TmpDir, dst),
+		false); err != nil {
+		return errors.Wrap(errors.WithStack(err), fmt.Sprintf("install container runtime crio binaries failed"))
+	}
+	return nil
+}
+
+type EnableCrio struct {
+	common.KubeAction
+}
+
+func (e *EnableCrio) Execute(runtime connector.Runtime) error {
+	if _, err := runtime.GetRunner().SudoCmd(
+		"systemctl daemon-reload && systemctl enable crio && systemctl restart crio",
+		false); err != nil {
+		return errors.Wrap(errors.WithStack(err), fmt.Sprintf("enable and start crio failed"))
+	}
+	return nil
+}
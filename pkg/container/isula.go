@@ -0,0 +1,103 @@
+/*
+ Copyright 2021 The KubeSphere Authors.
+
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+     http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package container
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
+	"github.com/kubesphere/kubekey/pkg/common"
+	"github.com/kubesphere/kubekey/pkg/container/templates"
+	"github.com/kubesphere/kubekey/pkg/core/connector"
+	"github.com/pkg/errors"
+)
+
+// InstallIsulaPackages installs iSulad from the distribution repository. iSulad does not publish static release binaries,
+// in the offline scenario the packages are provided by the repository ISO (--with-packages).
+type InstallIsulaPackages struct {
+	common.KubeAction
+}
+
+func (i *InstallIsulaPackages) Execute(runtime connector.Runtime) error {
+	if _, err := runtime.GetRunner().SudoCmd(
+		"if command -v dnf > /dev/null 2>&1; then dnf install -y iSulad; "+
+			"elif command -v yum > /dev/null 2>&1; then yum install -y iSulad; "+
+			"else echo 'no supported package manager found' && exit 1; fi",
+		true); err != nil {
+		return errors.Wrap(errors.WithStack(err), fmt.Sprintf("install container runtime isula failed"))
+	}
+	return nil
+}
+
+type EnableIsula struct {
+	common.KubeAction
+}
+
+func (e *EnableIsula) Execute(runtime connector.Runtime) error {
+	if _, err := runtime.GetRunner().SudoCmd(
+		"systemctl daemon-reload && systemctl enable isulad && systemctl restart isulad",
+		false); err != nil {
+		return errors.Wrap(errors.WithStack(err), fmt.Sprintf("enable and start isula failed"))
+	}
+	return nil
+}
+
+type IsulaLoginRegistry struct {
+	common.KubeAction
+}
+
+func (i *IsulaLoginRegistry) Execute(runtime connector.Runtime) error {
+	auths := templates.Auths(i.KubeConf)
+
+	for repo, entry := range auths {
+		if err := isulaLogin(runtime, repo, entry.Username, entry.Password); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// isulaLogin passes the password on stdin from a file only readable by its owner,
+// which keeps it out of the process list and the logs.
+func isulaLogin(runtime connector.Runtime, repo, username, password string) error {
+	f, err := ioutil.TempFile("", "kubekey-isula-")
+	if err != nil {
+		return errors.WithStack(err)
+	}
+	defer os.Remove(f.Name())
+	if _, err := f.WriteString(password); err != nil {
+		f.Close()
+		return errors.WithStack(err)
+	}
+	if err := f.Close(); err != nil {
+		return errors.WithStack(err)
+	}
+
+	remote := filepath.Join(common.TmpDir, filepath.Base(f.Name()))
+	if err := runtime.GetRunner().Scp(f.Name(), remote); err != nil {
+		return errors.Wrapf(errors.WithStack(err), "scp the password of %s failed", repo)
+	}
+
+	cmd := fmt.Sprintf("isula login --username \"%s\" --password-stdin %s < %s && rm -f %s || { rm -f %s; exit 1; }",
+		username, repo, remote, remote, remote)
+	if _, err := runtime.GetRunner().SudoCmd(cmd, false); err != nil {
+		return errors.Wrapf(err, "login registry %s failed", repo)
+	}
+	return nil
+}
@@ -50,9 +50,9 @@ func (i *InstallContainerModule) Init() {
 	case common.Conatinerd:
 		i.Tasks = InstallContainerd(i)
 	case common.Crio:
-		// TODO: Add the steps of cri-o's installation.
+		i.Tasks = InstallCrio(i)
 	case common.Isula:
-		// TODO: Add the steps of iSula's installation.
+		i.Tasks = InstallIsula(i)
 	default:
 		logger.Log.Fatalf("Unsupported container runtime: %s", strings.TrimSpace(i.KubeConf.Cluster.Kubernetes.ContainerManager))
 	}
@@ -320,3 +320,265 @@ func InstallContainerd(m *InstallContainerModule) []task.Interface {
 		enableDocker,
 	}
 }
+
+func InstallIsula(m *InstallContainerModule) []task.Interface {
+	syncCrictlBinaries := &task.RemoteTask{
+		Name:  "SyncCrictlBinaries",
+		Desc:  "Sync crictl binaries",
+		Hosts: m.Runtime.GetHostsByRole(common.K8s),
+		Prepare: &prepare.PrepareCollection{
+			&kubernetes.NodeInCluster{Not: true},
+			&CrictlExist{Not: true},
+		},
+		Action:   new(SyncCrictlBinaries),
+		Parallel: true,
+		Retry:    2,
+	}
+
+	installIsula := &task.RemoteTask{
+		Name:  "InstallIsula",
+		Desc:  "Install isula",
+		Hosts: m.Runtime.GetHostsByRole(common.K8s),
+		Prepare: &prepare.PrepareCollection{
+			&kubernetes.NodeInCluster{Not: true},
+			&IsulaExist{Not: true},
+		},
+		Action:   new(InstallIsulaPackages),
+		Parallel: true,
+		Retry:    2,
+	}
+
+	generateIsulaConfig := &task.RemoteTask{
+		Name:  "GenerateIsulaConfig",
+		Desc:  "Generate isula config",
+		Hosts: m.Runtime.GetHostsByRole(common.K8s),
+		Prepare: &prepare.PrepareCollection{
+			&kubernetes.NodeInCluster{Not: true},
+			&IsulaExist{Not: true},
+		},
+		Action: &action.Template{
+			Template: templates.IsulaConfig,
+			Dst:      filepath.Join("/etc/isulad/", templates.IsulaConfig.Name()),
+			Data: util.Data{
+				"Mirrors":            templates.IsulaMirrors(m.KubeConf),
+				"InsecureRegistries": templates.InsecureRegistries(m.KubeConf),
+				"SandBoxImage":       images.GetImage(m.Runtime, m.KubeConf, "pause").ImageName(),
+			},
+		},
+		Parallel: true,
+	}
+
+	generateCrictlConfig := &task.RemoteTask{
+		Name:  "GenerateCrictlConfig",
+		Desc:  "Generate crictl config",
+		Hosts: m.Runtime.GetHostsByRole(common.K8s),
+		Prepare: &prepare.PrepareCollection{
+			&kubernetes.NodeInCluster{Not: true},
+			&IsulaExist{Not: true},
+		},
+		Action: &action.Template{
+			Template: templates.CrictlConfig,
+			Dst:      filepath.Join("/etc/", templates.CrictlConfig.Name()),
+			Data: util.Data{
+				"Endpoint": m.KubeConf.Cluster.Kubernetes.ContainerRuntimeEndpoint,
+			},
+		},
+		Parallel: true,
+	}
+
+	enableIsula := &task.RemoteTask{
+		Name:  "EnableIsula",
+		Desc:  "Enable isula",
+		Hosts: m.Runtime.GetHostsByRole(common.K8s),
+		Prepare: &prepare.PrepareCollection{
+			&kubernetes.NodeInCluster{Not: true},
+			&IsulaExist{Not: true},
+		},
+		Action:   new(EnableIsula),
+		Parallel: true,
+	}
+
+	isulaLoginRegistry := &task.RemoteTask{
+		Name:  "Login PrivateRegistry",
+		Desc:  "Add auths to container runtime",
+		Hosts: m.Runtime.GetHostsByRole(common.K8s),
+		Prepare: &prepare.PrepareCollection{
+			&kubernetes.NodeInCluster{Not: true},
+			&IsulaExist{},
+			&PrivateRegistryAuth{},
+		},
+		Action:   new(IsulaLoginRegistry),
+		Parallel: true,
+	}
+
+	return []task.Interface{
+		syncCrictlBinaries,
+		installIsula,
+		generateIsulaConfig,
+		generateCrictlConfig,
+		enableIsula,
+		isulaLoginRegistry,
+	}
+}
+
+func InstallCrio(m *InstallContainerModule) []task.Interface {
+	syncCrictlBinaries := &task.RemoteTask{
+		Name:  "SyncCrictlBinaries",
+		Desc:  "Sync crictl binaries",
+		Hosts: m.Runtime.GetHostsByRole(common.K8s),
+		Prepare: &prepare.PrepareCollection{
+			&kubernetes.NodeInCluster{Not: true},
+			&CrictlExist{Not: true},
+		},
+		Action:   new(SyncCrictlBinaries),
+		Parallel: true,
+		Retry:    2,
+	}
+
+	syncCrioBinaries := &task.RemoteTask{
+		Name:  "SyncCrioBinaries",
+		Desc:  "Sync crio binaries",
+		Hosts: m.Runtime.GetHostsByRole(common.K8s),
+		Prepare: &prepare.PrepareCollection{
+			&kubernetes.NodeInCluster{Not: true},
+			&CrioExist{Not: true},
+		},
+		Action:   new(SyncCrioBinaries),
+		Parallel: true,
+		Retry:    2,
+	}
+
+	generateCrioService := &task.RemoteTask{
+		Name:  "GenerateCrioService",
+		Desc:  "Generate crio service",
+		Hosts: m.Runtime.GetHostsByRole(common.K8s),
+		Prepare: &prepare.PrepareCollection{
+			&kubernetes.NodeInCluster{Not: true},
+			&CrioExist{Not: true},
+		},
+		Action: &action.Template{
+			Template: templates.CrioService,
+			Dst:      filepath.Join("/etc/systemd/system", templates.CrioService.Name()),
+		},
+		Parallel: true,
+	}
+
+	authFile := ""
+	if len(m.KubeConf.Cluster.Registry.Auths.Raw) != 0 {
+		authFile = templates.CrioAuthFile
+	}
+
+	generateCrioConfig := &task.RemoteTask{
+		Name:  "GenerateCrioConfig",
+		Desc:  "Generate crio config",
+		Hosts: m.Runtime.GetHostsByRole(common.K8s),
+		Prepare: &prepare.PrepareCollection{
+			&kubernetes.NodeInCluster{Not: true},
+			&CrioExist{Not: true},
+		},
+		Action: &action.Template{
+			Template: templates.CrioConfig,
+			Dst:      filepath.Join("/etc/crio/", templates.CrioConfig.Name()),
+			Data: util.Data{
+				"SandBoxImage": images.GetImage(m.Runtime, m.KubeConf, "pause").ImageName(),
+				"AuthFile":     authFile,
+			},
+		},
+		Parallel: true,
+	}
+
+	generateCrioRegistriesConfig := &task.RemoteTask{
+		Name:  "GenerateCrioRegistriesConfig",
+		Desc:  "Generate crio registries config",
+		Hosts: m.Runtime.GetHostsByRole(common.K8s),
+		Prepare: &prepare.PrepareCollection{
+			&kubernetes.NodeInCluster{Not: true},
+			&CrioExist{Not: true},
+		},
+		Action: &action.Template{
+			Template: templates.CrioRegistriesConfig,
+			Dst:      filepath.Join("/etc/containers/", templates.CrioRegistriesConfig.Name()),
+			Data: util.Data{
+				"Mirrors":            templates.CrioMirrors(m.KubeConf),
+				"InsecureRegistries": m.KubeConf.Cluster.Registry.InsecureRegistries,
+			},
+		},
+		Parallel: true,
+	}
+
+	generateCrioPolicy := &task.RemoteTask{
+		Name:  "GenerateCrioPolicy",
+		Desc:  "Generate crio signature policy",
+		Hosts: m.Runtime.GetHostsByRole(common.K8s),
+		Prepare: &prepare.PrepareCollection{
+			&kubernetes.NodeInCluster{Not: true},
+			&CrioExist{Not: true},
+		},
+		Action: &action.Template{
+			Template: templates.CrioPolicy,
+			Dst:      filepath.Join("/etc/containers/", templates.CrioPolicy.Name()),
+		},
+		Parallel: true,
+	}
+
+	generateCrioAuthConfig := &task.RemoteTask{
+		Name:  "GenerateCrioAuthConfig",
+		Desc:  "Add auths to container runtime",
+		Hosts: m.Runtime.GetHostsByRole(common.K8s),
+		Prepare: &prepare.PrepareCollection{
+			&kubernetes.NodeInCluster{Not: true},
+			&CrioExist{Not: true},
+			&PrivateRegistryAuth{},
+		},
+		Action: &action.Template{
+			Template: templates.CrioAuthConfig,
+			Dst:      templates.CrioAuthFile,
+			Data: util.Data{
+				"Auths": templates.CrioAuths(m.KubeConf),
+			},
+		},
+		Parallel: true,
+	}
+
+	generateCrictlConfig := &task.RemoteTask{
+		Name:  "GenerateCrictlConfig",
+		Desc:  "Generate crictl config",
+		Hosts: m.Runtime.GetHostsByRole(common.K8s),
+		Prepare: &prepare.PrepareCollection{
+			&kubernetes.NodeInCluster{Not: true},
+			&CrioExist{Not: true},
+		},
+		Action: &action.Template{
+			Template: templates.CrictlConfig,
+			Dst:      filepath.Join("/etc/", templates.CrictlConfig.Name()),
+			Data: util.Data{
+				"Endpoint": m.KubeConf.Cluster.Kubernetes.ContainerRuntimeEndpoint,
+			},
+		},
+		Parallel: true,
+	}
+
+	enableCrio := &task.RemoteTask{
+		Name:  "EnableCrio",
+		Desc:  "Enable crio",
+		Hosts: m.Runtime.GetHostsByRole(common.K8s),
+		Prepare: &prepare.PrepareCollection{
+			&kubernetes.NodeInCluster{Not: true},
+			&CrioExist{Not: true},
+		},
+		Action:   new(EnableCrio),
+		Parallel: true,
+	}
+
+	return []task.Interface{
+		syncCrictlBinaries,
+		syncCrioBinaries,
+		generateCrioService,
+		generateCrioConfig,
+		generateCrioRegistriesConfig,
+		generateCrioPolicy,
+		generateCrioAuthConfig,
+		generateCrictlConfig,
+		enableCrio,
+	}
+}
@@ -0,0 +1,180 @@
+/*
+ Copyright 2021 The KubeSphere Authors.
+
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+     http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package templates
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"text/template"
+
+	"github.com/kubesphere/kubekey/pkg/common"
+	"github.com/lithammer/dedent"
+	"github.com/pkg/errors"
+)
+
+const CrioAuthFile = "/etc/crio/auth.json"
+
+var CrioConfig = template.Must(template.New("crio.conf").Parse(
+	dedent.Dedent(`[crio]
+root = "/var/lib/containers/storage"
+runroot = "/var/run/containers/storage"
+log_dir = "/var/log/crio/pods"
+version_file = "/var/run/crio/version"
+
+[crio.api]
+listen = "/var/run/crio/crio.sock"
+stream_address = "127.0.0.1"
+stream_port = "0"
+grpc_max_send_msg_size = 16777216
+grpc_max_recv_msg_size = 16777216
+
+[crio.runtime]
+default_runtime = "runc"
+conmon = "/usr/bin/conmon"
+conmon_cgroup = "system.slice"
+cgroup_manager = "systemd"
+selinux = false
+seccomp_profile = ""
+apparmor_profile = "crio-default"
+pids_limit = 1024
+log_size_max = -1
+default_capabilities = [
+  "CHOWN",
+  "DAC_OVERRIDE",
+  "FSETID",
+  "FOWNER",
+  "SETGID",
+  "SETUID",
+  "SETPCAP",
+  "NET_BIND_SERVICE",
+  "KILL",
+]
+
+[crio.runtime.runtimes.runc]
+runtime_path = "/usr/bin/runc"
+runtime_type = "oci"
+runtime_root = "/run/runc"
+
+[crio.image]
+default_transport = "docker://"
+pause_image = "{{ .SandBoxImage }}"
+pause_command = "/pause"
+{{- if .AuthFile }}
+global_auth_file = "{{ .AuthFile }}"
+{{- end }}
+signature_policy = "/etc/containers/policy.json"
+
+[crio.network]
+network_dir = "/etc/cni/net.d/"
+plugin_dirs = [
+  "/opt/cni/bin/",
+]
+
+[crio.metrics]
+enable_metrics = false
+    `)))
+
+var CrioRegistriesConfig = template.Must(template.New("registries.conf").Parse(
+	dedent.Dedent(`unqualified-search-registries = ["docker.io"]
+
+[[registry]]
+prefix = "docker.io"
+location = "registry-1.docker.io"
+{{- range .Mirrors }}
+
+[[registry.mirror]]
+location = "{{ .Location }}"
+{{- if .Insecure }}
+insecure = true
+{{- end }}
+{{- end }}
+{{- range .InsecureRegistries }}
+
+[[registry]]
+prefix = "{{ . }}"
+location = "{{ . }}"
+insecure = true
+{{- end }}
+    `)))
+
+var CrioPolicy = template.Must(template.New("policy.json").Parse(
+	dedent.Dedent(`{
+  "default": [
+    {
+      "type": "insecureAcceptAnything"
+    }
+  ],
+  "transports": {
+    "docker-daemon": {
+      "": [
+        {
+          "type": "insecureAcceptAnything"
+        }
+      ]
+    }
+  }
+}
+    `)))
+
+var CrioAuthConfig = template.Must(template.New("auth.json").Funcs(template.FuncMap{"toJson": toJSON}).Parse(
+	dedent.Dedent(`{{ toJson .Auths }}
+    `)))
+
+type CrioRegistry struct {
+	Location string
+	Insecure bool
+}
+
+// CrioMirrors converts the registry mirrors to the registries.conf v2 format, which expects a location without scheme.
+func CrioMirrors(kubeConf *common.KubeConf) []CrioRegistry {
+	var mirrors []CrioRegistry
+	for _, mirror := range kubeConf.Cluster.Registry.RegistryMirrors {
+		m := CrioRegistry{Location: strings.TrimSuffix(mirror, "/")}
+		if strings.HasPrefix(m.Location, "http://") {
+			m.Insecure = true
+		}
+		m.Location = strings.TrimPrefix(strings.TrimPrefix(m.Location, "http://"), "https://")
+		mirrors = append(mirrors, m)
+	}
+	return mirrors
+}
+
+// CrioAuths converts the private registry auths to a containers-auth.json document.
+func CrioAuths(kubeConf *common.KubeConf) map[string]interface{} {
+	type authEntry struct {
+		Auth string `json:"auth"`
+	}
+	auths := make(map[string]authEntry)
+	for repo, entry := range Auths(kubeConf) {
+		auths[repo] = authEntry{
+			Auth: base64.StdEncoding.EncodeToString([]byte(fmt.Sprintf("%s:%s", entry.Username, entry.Password))),
+		}
+	}
+
+	return map[string]interface{}{"auths": auths}
+}
+
+// toJSON fails the template execution on error, which is returned by the task generating the file.
+func toJSON(v interface{}) (string, error) {
+	out, err := json.MarshalIndent(v, "", "  ")
+	if err != nil {
+		return "", errors.Wrap(err, "render cri-o registry auths failed")
+	}
+	return string(out), nil
+}
@@ -41,13 +41,22 @@ func (d *Dialer) Connect(host Host) (Connection, error) {
 	conn, ok := d.connections[host.GetName()]
 	if !ok {
 		opts := Cfg{
-			Username:   host.GetUser(),
-			Port:       host.GetPort(),
-			Address:    host.GetAddress(),
-			Password:   host.GetPassword(),
-			PrivateKey: host.GetPrivateKey(),
-			KeyFile:    host.GetPrivateKeyPath(),
-			Timeout:    30 * time.Second,
+			Username:    host.GetUser(),
+			Port:        host.GetPort(),
+			Address:     host.GetAddress(),
+			Password:    host.GetPassword(),
+			PrivateKey:  host.GetPrivateKey(),
+			KeyFile:     host.GetPrivateKeyPath(),
+			AgentSocket: host.GetAgentSocket(),
+			Timeout:     30 * time.Second,
+		}
+		if bastion := host.GetBastion(); bastion != nil {
+			opts.Bastion = bastion.Address
+			opts.BastionPort = bastion.Port
+			opts.BastionUser = bastion.User
+			opts.BastionPassword = bastion.Password
+			opts.BastionPrivateKey = bastion.PrivateKey
+			opts.BastionKeyFile = bastion.PrivateKeyPath
 		}
 		conn, err = NewConnection(d, opts)
 		if err != nil {
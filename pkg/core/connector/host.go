@@ -18,6 +18,16 @@ package connector
 
 import "github.com/kubesphere/kubekey/pkg/core/cache"
 
+// Bastion defines the jump host used to reach a host that is not directly accessible.
+type Bastion struct {
+	Address        string `yaml:"address,omitempty" json:"address,omitempty"`
+	Port           int    `yaml:"port,omitempty" json:"port,omitempty"`
+	User           string `yaml:"user,omitempty" json:"user,omitempty"`
+	Password       string `yaml:"password,omitempty" json:"password,omitempty"`
+	PrivateKey     string `yaml:"privateKey,omitempty" json:"privateKey,omitempty"`
+	PrivateKeyPath string `yaml:"privateKeyPath,omitempty" json:"privateKeyPath,omitempty"`
+}
+
 type BaseHost struct {
 	Name            string          `yaml:"name,omitempty" json:"name,omitempty"`
 	Address         string          `yaml:"address,omitempty" json:"address,omitempty"`
@@ -28,6 +38,8 @@ type BaseHost struct {
 	PrivateKey      string          `yaml:"privateKey,omitempty" json:"privateKey,omitempty"`
 	PrivateKeyPath  string          `yaml:"privateKeyPath,omitempty" json:"privateKeyPath,omitempty"`
 	Arch            string          `yaml:"arch,omitempty" json:"arch,omitempty"`
+	AgentSocket     string          `yaml:"agentSocket,omitempty" json:"agentSocket,omitempty"`
+	Bastion         *Bastion        `yaml:"bastion,omitempty" json:"bastion,omitempty"`
 	Roles           []string        `json:"-"`
 	RoleTable       map[string]bool `json:"-"`
 	Cache           *cache.Cache    `json:"-"`
@@ -113,6 +125,22 @@ func (b *BaseHost) SetArch(arch string) {
 	b.Arch = arch
 }
 
+func (b *BaseHost) GetAgentSocket() string {
+	return b.AgentSocket
+}
+
+func (b *BaseHost) SetAgentSocket(socket string) {
+	b.AgentSocket = socket
+}
+
+func (b *BaseHost) GetBastion() *Bastion {
+	return b.Bastion
+}
+
+func (b *BaseHost) SetBastion(bastion *Bastion) {
+	b.Bastion = bastion
+}
+
 func (b *BaseHost) GetRoles() []string {
 	return b.Roles
 }
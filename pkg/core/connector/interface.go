@@ -45,6 +45,8 @@ type ModuleRuntime interface {
 	GetHostWorkDir() string
 	GetWorkDir() string
 	GetIgnoreErr() bool
+	GetMaxParallel() int
+	SetMaxParallel(n int)
 	GetAllHosts() []Host
 	SetAllHosts([]Host)
 	GetHostsByRole(role string) []Host
@@ -58,6 +60,8 @@ type Runtime interface {
 	SetRunner(r *Runner)
 	GetConnector() Connector
 	SetConnector(c Connector)
+	GetPlan() *Plan
+	SetPlan(p *Plan)
 	RemoteHost() Host
 	Copy() Runtime
 	ModuleRuntime
@@ -82,6 +86,10 @@ type Host interface {
 	SetPrivateKeyPath(path string)
 	GetArch() string
 	SetArch(arch string)
+	GetAgentSocket() string
+	SetAgentSocket(socket string)
+	GetBastion() *Bastion
+	SetBastion(bastion *Bastion)
 	GetRoles() []string
 	SetRoles(roles []string)
 	IsRole(role string) bool
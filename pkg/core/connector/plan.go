@@ -0,0 +1,110 @@
+/*
+ Copyright 2021 The KubeSphere Authors.
+
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+     http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package connector
+
+import (
+	"bytes"
+	"fmt"
+	"sync"
+)
+
+const (
+	PlanCmd   = "cmd"
+	PlanSudo  = "sudo"
+	PlanScp   = "scp"
+	PlanFetch = "fetch"
+	PlanMkDir = "mkdir"
+	PlanChmod = "chmod"
+	PlanSkip  = "skip"
+	PlanLocal = "local"
+	PlanNote  = "note"
+)
+
+// Plan records the operations which a dry run would issue, grouped by module and host.
+type Plan struct {
+	mu      sync.Mutex
+	Modules []*ModulePlan
+}
+
+type ModulePlan struct {
+	Name  string
+	Hosts []*HostPlan
+}
+
+type HostPlan struct {
+	Name  string
+	Steps []PlanStep
+}
+
+type PlanStep struct {
+	Task   string
+	Kind   string
+	Detail string
+}
+
+func NewPlan() *Plan {
+	return &Plan{Modules: make([]*ModulePlan, 0)}
+}
+
+// BeginModule starts a new module section, the following steps are recorded into it.
+func (p *Plan) BeginModule(name string) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.Modules = append(p.Modules, &ModulePlan{Name: name})
+}
+
+func (p *Plan) Record(host, task, kind, detail string) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if len(p.Modules) == 0 {
+		p.Modules = append(p.Modules, &ModulePlan{})
+	}
+	m := p.Modules[len(p.Modules)-1]
+
+	var h *HostPlan
+	for i := range m.Hosts {
+		if m.Hosts[i].Name == host {
+			h = m.Hosts[i]
+			break
+		}
+	}
+	if h == nil {
+		h = &HostPlan{Name: host}
+		m.Hosts = append(m.Hosts, h)
+	}
+	h.Steps = append(h.Steps, PlanStep{Task: task, Kind: kind, Detail: detail})
+}
+
+func (p *Plan) String() string {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	buf := new(bytes.Buffer)
+	for _, m := range p.Modules {
+		fmt.Fprintf(buf, "Module[%s]\n", m.Name)
+		if len(m.Hosts) == 0 {
+			fmt.Fprintf(buf, "  (no operation)\n")
+		}
+		for _, h := range m.Hosts {
+			fmt.Fprintf(buf, "  Host[%s]\n", h.Name)
+			for _, s := range h.Steps {
+				fmt.Fprintf(buf, "    [%s] %-5s %s\n", s.Task, s.Kind, s.Detail)
+			}
+		}
+	}
+	return buf.String()
+}
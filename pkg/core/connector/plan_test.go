@@ -0,0 +1,63 @@
+/*
+ Copyright 2021 The KubeSphere Authors.
+
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+     http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package connector
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestRunner_DryRun(t *testing.T) {
+	plan := NewPlan()
+	plan.BeginModule("TestModule")
+
+	for _, name := range []string{"node1", "node2"} {
+		r := &Runner{
+			Host: &BaseHost{Name: name},
+			Plan: plan,
+			Task: "TestTask",
+		}
+		if _, err := r.SudoCmd("systemctl restart kubelet", false); err != nil {
+			t.Fatal(err)
+		}
+		if err := r.SudoScp("/tmp/kubelet.service", "/etc/systemd/system/kubelet.service"); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if len(plan.Modules) != 1 || len(plan.Modules[0].Hosts) != 2 {
+		t.Fatalf("unexpected plan: %s", plan.String())
+	}
+	for _, h := range plan.Modules[0].Hosts {
+		if len(h.Steps) != 2 {
+			t.Errorf("host %s has %d steps, want 2", h.Name, len(h.Steps))
+		}
+		if h.Steps[0].Kind != PlanSudo || h.Steps[0].Detail != "systemctl restart kubelet" {
+			t.Errorf("unexpected step %v", h.Steps[0])
+		}
+		if h.Steps[1].Kind != PlanScp {
+			t.Errorf("unexpected step %v", h.Steps[1])
+		}
+	}
+
+	out := plan.String()
+	for _, s := range []string{"Module[TestModule]", "Host[node1]", "Host[node2]", "/etc/systemd/system/kubelet.service"} {
+		if !strings.Contains(out, s) {
+			t.Errorf("plan output does not contain %q:\n%s", s, out)
+		}
+	}
+}
@@ -31,9 +31,20 @@ type Runner struct {
 	Debug bool
 	Host  Host
 	Index int
+	// Plan is set in dry-run mode, the operations are recorded into it instead of being executed.
+	Plan *Plan
+	Task string
+}
+
+func (r *Runner) record(kind, detail string) {
+	r.Plan.Record(r.Host.GetName(), r.Task, kind, detail)
 }
 
 func (r *Runner) Exec(cmd string, printOutput bool) (string, int, error) {
+	if r.Plan != nil {
+		r.record(PlanCmd, cmd)
+		return "", 0, nil
+	}
 	if r.Conn == nil {
 		return "", 1, errors.New("no ssh connection available")
 	}
@@ -80,14 +91,26 @@ func (r *Runner) Cmd(cmd string, printOutput bool) (string, error) {
 }
 
 func (r *Runner) SudoExec(cmd string, printOutput bool) (string, int, error) {
+	if r.Plan != nil {
+		r.record(PlanSudo, cmd)
+		return "", 0, nil
+	}
 	return r.Exec(SudoPrefix(cmd), printOutput)
 }
 
 func (r *Runner) SudoCmd(cmd string, printOutput bool) (string, error) {
+	if r.Plan != nil {
+		r.record(PlanSudo, cmd)
+		return "", nil
+	}
 	return r.Cmd(SudoPrefix(cmd), printOutput)
 }
 
 func (r *Runner) Fetch(local, remote string) error {
+	if r.Plan != nil {
+		r.record(PlanFetch, fmt.Sprintf("%s -> %s", remote, local))
+		return nil
+	}
 	if r.Conn == nil {
 		return errors.New("no ssh connection available")
 	}
@@ -101,6 +124,10 @@ func (r *Runner) Fetch(local, remote string) error {
 }
 
 func (r *Runner) Scp(local, remote string) error {
+	if r.Plan != nil {
+		r.record(PlanScp, fmt.Sprintf("%s -> %s", local, remote))
+		return nil
+	}
 	if r.Conn == nil {
 		return errors.New("no ssh connection available")
 	}
@@ -114,6 +141,10 @@ func (r *Runner) Scp(local, remote string) error {
 }
 
 func (r *Runner) SudoScp(local, remote string) error {
+	if r.Plan != nil {
+		r.record(PlanScp, fmt.Sprintf("%s -> %s", local, remote))
+		return nil
+	}
 	if r.Conn == nil {
 		return errors.New("no ssh connection available")
 	}
@@ -168,6 +199,10 @@ func (r *Runner) DirExist(remote string) (bool, error) {
 }
 
 func (r *Runner) MkDir(path string) error {
+	if r.Plan != nil {
+		r.record(PlanMkDir, path)
+		return nil
+	}
 	if r.Conn == nil {
 		return errors.New("no ssh connection available")
 	}
@@ -180,6 +215,10 @@ func (r *Runner) MkDir(path string) error {
 }
 
 func (r *Runner) Chmod(path string, mode os.FileMode) error {
+	if r.Plan != nil {
+		r.record(PlanChmod, fmt.Sprintf("%o %s", mode, path))
+		return nil
+	}
 	if r.Conn == nil {
 		return errors.New("no ssh connection available")
 	}
@@ -30,9 +30,11 @@ type BaseRuntime struct {
 	ObjName         string
 	connector       Connector
 	runner          *Runner
+	plan            *Plan
 	workDir         string
 	verbose         bool
 	ignoreErr       bool
+	maxParallel     int
 	allHosts        []Host
 	roleHosts       map[string][]Host
 	deprecatedHosts map[string]string
@@ -83,6 +85,14 @@ func (b *BaseRuntime) SetConnector(c Connector) {
 	b.connector = c
 }
 
+func (b *BaseRuntime) GetPlan() *Plan {
+	return b.plan
+}
+
+func (b *BaseRuntime) SetPlan(p *Plan) {
+	b.plan = p
+}
+
 func (b *BaseRuntime) GenerateWorkDir() error {
 	currentDir, err := filepath.Abs(filepath.Dir(os.Args[0]))
 	if err != nil {
@@ -121,6 +131,14 @@ func (b *BaseRuntime) GetIgnoreErr() bool {
 	return b.ignoreErr
 }
 
+func (b *BaseRuntime) GetMaxParallel() int {
+	return b.maxParallel
+}
+
+func (b *BaseRuntime) SetMaxParallel(n int) {
+	b.maxParallel = n
+}
+
 func (b *BaseRuntime) GetAllHosts() []Host {
 	return b.allHosts
 }
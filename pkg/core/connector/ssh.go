@@ -40,17 +40,20 @@ import (
 )
 
 type Cfg struct {
-	Username    string
-	Password    string
-	Address     string
-	Port        int
-	PrivateKey  string
-	KeyFile     string
-	AgentSocket string
-	Timeout     time.Duration
-	Bastion     string
-	BastionPort int
-	BastionUser string
+	Username          string
+	Password          string
+	Address           string
+	Port              int
+	PrivateKey        string
+	KeyFile           string
+	AgentSocket       string
+	Timeout           time.Duration
+	Bastion           string
+	BastionPort       int
+	BastionUser       string
+	BastionPassword   string
+	BastionPrivateKey string
+	BastionKeyFile    string
 }
 
 const socketEnvPrefix = "env:"
@@ -70,45 +73,9 @@ func NewConnection(dialer *Dialer, cfg Cfg) (Connection, error) {
 		return nil, errors.Wrap(err, "Failed to validate ssh connection parameters")
 	}
 
-	authMethods := make([]ssh.AuthMethod, 0)
-
-	if len(cfg.Password) > 0 {
-		authMethods = append(authMethods, ssh.Password(cfg.Password))
-	}
-
-	if len(cfg.PrivateKey) > 0 {
-		signer, parseErr := ssh.ParsePrivateKey([]byte(cfg.PrivateKey))
-		if parseErr != nil {
-			return nil, errors.Wrap(parseErr, "The given SSH key could not be parsed")
-		}
-		authMethods = append(authMethods, ssh.PublicKeys(signer))
-	}
-
-	if len(cfg.AgentSocket) > 0 {
-		addr := cfg.AgentSocket
-
-		if strings.HasPrefix(cfg.AgentSocket, socketEnvPrefix) {
-			envName := strings.TrimPrefix(cfg.AgentSocket, socketEnvPrefix)
-
-			if envAddr := os.Getenv(envName); len(envAddr) > 0 {
-				addr = envAddr
-			}
-		}
-
-		socket, dialErr := net.Dial("unix", addr)
-		if dialErr != nil {
-			return nil, errors.Wrapf(dialErr, "could not open socket %q", addr)
-		}
-
-		agentClient := agent.NewClient(socket)
-
-		signers, signersErr := agentClient.Signers()
-		if signersErr != nil {
-			_ = socket.Close()
-			return nil, errors.Wrap(signersErr, "error when creating signer for SSH agent")
-		}
-
-		authMethods = append(authMethods, ssh.PublicKeys(signers...))
+	authMethods, err := newAuthMethods(cfg.Password, cfg.PrivateKey, cfg.AgentSocket)
+	if err != nil {
+		return nil, err
 	}
 
 	sshConfig := &ssh.ClientConfig{
@@ -121,15 +88,29 @@ func NewConnection(dialer *Dialer, cfg Cfg) (Connection, error) {
 	targetHost := cfg.Address
 	targetPort := strconv.Itoa(cfg.Port)
 
+	bastionConfig := sshConfig
 	if cfg.Bastion != "" {
 		targetHost = cfg.Bastion
 		targetPort = strconv.Itoa(cfg.BastionPort)
-		sshConfig.User = cfg.BastionUser
+		bastionConfig = &ssh.ClientConfig{
+			User:            cfg.BastionUser,
+			Timeout:         cfg.Timeout,
+			Auth:            authMethods,
+			HostKeyCallback: ssh.InsecureIgnoreHostKey(),
+		}
+		// The bastion falls back to the credentials of the target host if it has none of its own.
+		if len(cfg.BastionPassword) > 0 || len(cfg.BastionPrivateKey) > 0 {
+			bastionAuthMethods, err := newAuthMethods(cfg.BastionPassword, cfg.BastionPrivateKey, cfg.AgentSocket)
+			if err != nil {
+				return nil, errors.Wrap(err, "Failed to create bastion auth methods")
+			}
+			bastionConfig.Auth = bastionAuthMethods
+		}
 	}
 
 	endpoint := net.JoinHostPort(targetHost, targetPort)
 
-	client, err := ssh.Dial("tcp", endpoint, sshConfig)
+	client, err := ssh.Dial("tcp", endpoint, bastionConfig)
 	if err != nil {
 		return nil, errors.Wrapf(err, "could not establish connection to %s", endpoint)
 	}
@@ -158,7 +139,6 @@ func NewConnection(dialer *Dialer, cfg Cfg) (Connection, error) {
 		return nil, errors.Wrapf(err, "could not establish connection to %s", endpointBehindBastion)
 	}
 
-	sshConfig.User = cfg.Username
 	ncc, chans, reqs, err := ssh.NewClientConn(conn, endpointBehindBastion, sshConfig)
 	if err != nil {
 		return nil, errors.Wrapf(err, "could not establish connection to %s", endpointBehindBastion)
@@ -173,6 +153,51 @@ func NewConnection(dialer *Dialer, cfg Cfg) (Connection, error) {
 	return sshConn, nil
 }
 
+func newAuthMethods(password, privateKey, agentSocket string) ([]ssh.AuthMethod, error) {
+	authMethods := make([]ssh.AuthMethod, 0)
+
+	if len(password) > 0 {
+		authMethods = append(authMethods, ssh.Password(password))
+	}
+
+	if len(privateKey) > 0 {
+		signer, parseErr := ssh.ParsePrivateKey([]byte(privateKey))
+		if parseErr != nil {
+			return nil, errors.Wrap(parseErr, "The given SSH key could not be parsed")
+		}
+		authMethods = append(authMethods, ssh.PublicKeys(signer))
+	}
+
+	if len(agentSocket) > 0 {
+		addr := agentSocket
+
+		if strings.HasPrefix(agentSocket, socketEnvPrefix) {
+			envName := strings.TrimPrefix(agentSocket, socketEnvPrefix)
+
+			if envAddr := os.Getenv(envName); len(envAddr) > 0 {
+				addr = envAddr
+			}
+		}
+
+		socket, dialErr := net.Dial("unix", addr)
+		if dialErr != nil {
+			return nil, errors.Wrapf(dialErr, "could not open socket %q", addr)
+		}
+
+		agentClient := agent.NewClient(socket)
+
+		signers, signersErr := agentClient.Signers()
+		if signersErr != nil {
+			_ = socket.Close()
+			return nil, errors.Wrap(signersErr, "error when creating signer for SSH agent")
+		}
+
+		authMethods = append(authMethods, ssh.PublicKeys(signers...))
+	}
+
+	return authMethods, nil
+}
+
 func validateOptions(cfg Cfg) (Cfg, error) {
 	if len(cfg.Username) == 0 {
 		return cfg, errors.New("No username specified for SSH connection")
@@ -204,6 +229,16 @@ func validateOptions(cfg Cfg) (Cfg, error) {
 		cfg.BastionPort = 22
 	}
 
+	if len(cfg.BastionPrivateKey) == 0 && len(cfg.BastionKeyFile) > 0 {
+		content, err := ioutil.ReadFile(cfg.BastionKeyFile)
+		if err != nil {
+			return cfg, errors.Wrapf(err, "Failed to read bastion keyfile %q", cfg.BastionKeyFile)
+		}
+
+		cfg.BastionPrivateKey = string(content)
+		cfg.BastionKeyFile = ""
+	}
+
 	if cfg.BastionUser == "" {
 		cfg.BastionUser = cfg.Username
 	}
@@ -0,0 +1,182 @@
+/*
+ Copyright 2021 The KubeSphere Authors.
+
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+     http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package event
+
+import (
+	"encoding/json"
+	"io"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+)
+
+const (
+	PipelineStart = "pipeline.start"
+	PipelineEnd   = "pipeline.end"
+	ModuleStart   = "module.start"
+	ModuleEnd     = "module.end"
+	TaskStart     = "task.start"
+	TaskEnd       = "task.end"
+	HostResult    = "host.result"
+	Retry         = "retry"
+)
+
+// Event is a machine-readable record of the pipeline progress.
+type Event struct {
+	Time     time.Time `json:"time"`
+	Type     string    `json:"type"`
+	Pipeline string    `json:"pipeline,omitempty"`
+	Module   string    `json:"module,omitempty"`
+	Task     string    `json:"task,omitempty"`
+	Host     string    `json:"host,omitempty"`
+	Status   string    `json:"status,omitempty"`
+	// Duration is in seconds.
+	Duration float64 `json:"duration,omitempty"`
+	Retry    int     `json:"retry,omitempty"`
+	Error    string  `json:"error,omitempty"`
+}
+
+type Sink interface {
+	Emit(e *Event)
+	Close() error
+}
+
+// Default is the sink used by Emit, the events are dropped when it is nil.
+var Default Sink
+
+// Close closes the default sink and restores the stdout redirected by it, the later events are dropped.
+// It can be called more than once, the commands defer it in case they fail before the pipeline closes the sink.
+func Close() error {
+	if Default == nil {
+		return nil
+	}
+	err := Default.Close()
+	Default = nil
+	return err
+}
+
+func Emit(e *Event) {
+	if Default == nil {
+		return
+	}
+	if e.Time.IsZero() {
+		e.Time = time.Now()
+	}
+	Default.Emit(e)
+}
+
+// Duration returns the seconds between start and end.
+func Duration(start, end time.Time) float64 {
+	if start.IsZero() || end.Before(start) {
+		return 0
+	}
+	return end.Sub(start).Seconds()
+}
+
+// ErrString returns the message of err or an empty string when err is nil.
+func ErrString(err error) string {
+	if err == nil {
+		return ""
+	}
+	return err.Error()
+}
+
+// JSONSink writes the events as JSON lines. The pipeline and module of an event are filled from
+// the latest pipeline and module start events when they are empty.
+type JSONSink struct {
+	mu       sync.Mutex
+	encoder  *json.Encoder
+	closer   io.Closer
+	restore  func()
+	pipeline string
+	module   string
+}
+
+func NewJSONSink(w io.Writer) *JSONSink {
+	return &JSONSink{encoder: json.NewEncoder(w)}
+}
+
+// NewJSONStdoutSink writes the events to the stdout. Until the sink is closed, the other outputs written to
+// os.Stdout are redirected to the stderr, which keeps the stdout for the events only.
+func NewJSONStdoutSink() *JSONSink {
+	stdout := os.Stdout
+	os.Stdout = os.Stderr
+	return &JSONSink{encoder: json.NewEncoder(stdout), restore: func() { os.Stdout = stdout }}
+}
+
+func NewJSONFileSink(path string) (*JSONSink, error) {
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return nil, err
+	}
+	f, err := os.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0644)
+	if err != nil {
+		return nil, err
+	}
+	return &JSONSink{encoder: json.NewEncoder(f), closer: f}, nil
+}
+
+func (s *JSONSink) Emit(e *Event) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	switch e.Type {
+	case PipelineStart:
+		s.pipeline = e.Pipeline
+		s.module = ""
+	case ModuleStart:
+		s.module = e.Module
+	}
+	if e.Pipeline == "" {
+		e.Pipeline = s.pipeline
+	}
+	if e.Module == "" && e.Type != PipelineStart && e.Type != PipelineEnd {
+		e.Module = s.module
+	}
+	_ = s.encoder.Encode(e)
+}
+
+func (s *JSONSink) Close() error {
+	if s.restore != nil {
+		s.restore()
+		s.restore = nil
+	}
+	if s.closer == nil {
+		return nil
+	}
+	return s.closer.Close()
+}
+
+// MultiSink emits the events to all of its sinks.
+type MultiSink []Sink
+
+func (m MultiSink) Emit(e *Event) {
+	for _, s := range m {
+		c := *e
+		s.Emit(&c)
+	}
+}
+
+func (m MultiSink) Close() error {
+	var err error
+	for _, s := range m {
+		if e := s.Close(); e != nil {
+			err = e
+		}
+	}
+	return err
+}
@@ -0,0 +1,79 @@
+/*
+ Copyright 2021 The KubeSphere Authors.
+
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+     http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package event
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"strings"
+	"testing"
+)
+
+func TestJSONSink_Emit(t *testing.T) {
+	buf := new(bytes.Buffer)
+	s := NewJSONSink(buf)
+
+	s.Emit(&Event{Type: PipelineStart, Pipeline: "CreateClusterPipeline"})
+	s.Emit(&Event{Type: ModuleStart, Module: "GreetingsModule"})
+	s.Emit(&Event{Type: HostResult, Task: "Greeting", Host: "node1", Status: "failed", Error: "connection refused"})
+	s.Emit(&Event{Type: PipelineEnd, Pipeline: "CreateClusterPipeline", Status: "failed"})
+
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	if len(lines) != 4 {
+		t.Fatalf("got %d lines, want 4:\n%s", len(lines), buf.String())
+	}
+
+	var e Event
+	if err := json.Unmarshal([]byte(lines[2]), &e); err != nil {
+		t.Fatal(err)
+	}
+	if e.Pipeline != "CreateClusterPipeline" || e.Module != "GreetingsModule" || e.Host != "node1" || e.Error != "connection refused" {
+		t.Errorf("unexpected event %+v", e)
+	}
+
+	e = Event{}
+	if err := json.Unmarshal([]byte(lines[3]), &e); err != nil {
+		t.Fatal(err)
+	}
+	if e.Module != "" {
+		t.Errorf("pipeline end event should not have a module, got %q", e.Module)
+	}
+}
+
+func TestJSONStdoutSink_Close(t *testing.T) {
+	stdout := os.Stdout
+	defer func() { os.Stdout = stdout }()
+
+	Default = NewJSONStdoutSink()
+	if os.Stdout != os.Stderr {
+		t.Fatal("the other outputs should be redirected to the stderr while the sink is open")
+	}
+	if err := Close(); err != nil {
+		t.Fatal(err)
+	}
+	if os.Stdout != stdout {
+		t.Error("the stdout should be restored when the sink is closed")
+	}
+	if Default != nil {
+		t.Error("the default sink should be reset when it is closed")
+	}
+	// the command closes it again after the pipeline
+	if err := Close(); err != nil || os.Stdout != stdout {
+		t.Errorf("closing the sink again should do nothing, got %v", err)
+	}
+}
@@ -23,6 +23,7 @@ import (
 )
 
 type Module interface {
+	GetName() string
 	IsSkip() bool
 	Default(runtime connector.Runtime, pipelineCache *cache.Cache, moduleCache *cache.Cache)
 	Init()
@@ -34,3 +35,9 @@ type Module interface {
 	AppendPostHook(h PostHookInterface)
 	CallPostHook(result *ending.ModuleResult) error
 }
+
+// AlwaysRun is implemented by the modules which collect the state of the hosts into the pipeline or host caches.
+// They are executed again when a pipeline resumes from a checkpoint, because those caches can not be restored.
+type AlwaysRun interface {
+	IsAlwaysRun() bool
+}
@@ -19,6 +19,7 @@ package module
 import (
 	"github.com/kubesphere/kubekey/pkg/core/connector"
 	"github.com/kubesphere/kubekey/pkg/core/ending"
+	"github.com/kubesphere/kubekey/pkg/core/event"
 	"github.com/kubesphere/kubekey/pkg/core/logger"
 	"github.com/kubesphere/kubekey/pkg/core/task"
 	"github.com/pkg/errors"
@@ -45,11 +46,21 @@ func (b *BaseTaskModule) Run(result *ending.ModuleResult) {
 		t.Init(b.Runtime.(connector.Runtime), b.ModuleCache, b.PipelineCache)
 
 		logger.Log.Infof("[%s] %s", b.Name, t.GetDesc())
+		event.Emit(&event.Event{Type: event.TaskStart, Module: b.Name, Task: t.GetName()})
 
 		res := t.Execute()
 		for j := range res.ActionResults {
 			ac := res.ActionResults[j]
 			logger.Log.Infof("%s: [%s]", ac.Status.String(), ac.Host.GetName())
+			event.Emit(&event.Event{
+				Type:     event.HostResult,
+				Module:   b.Name,
+				Task:     t.GetName(),
+				Host:     ac.Host.GetName(),
+				Status:   ac.Status.String(),
+				Duration: event.Duration(ac.StartTime, ac.EndTime),
+				Error:    event.ErrString(ac.Error),
+			})
 			result.AppendHostResult(ac)
 
 			if _, ok := t.(*task.RemoteTask); ok {
@@ -67,6 +78,19 @@ func (b *BaseTaskModule) Run(result *ending.ModuleResult) {
 			}
 		}
 
+		var taskErr error
+		if res.IsFailed() {
+			taskErr = res.CombineErr()
+		}
+		event.Emit(&event.Event{
+			Type:     event.TaskEnd,
+			Module:   b.Name,
+			Task:     t.GetName(),
+			Status:   res.Status.String(),
+			Duration: event.Duration(res.StartTime, res.EndTime),
+			Error:    event.ErrString(taskErr),
+		})
+
 		if res.IsFailed() {
 			t.ExecuteRollback()
 			result.ErrResult(errors.Wrapf(res.CombineErr(), "Module[%s] exec failed", b.Name))
@@ -0,0 +1,155 @@
+/*
+ Copyright 2021 The KubeSphere Authors.
+
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+     http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package pipeline
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
+	"github.com/kubesphere/kubekey/pkg/core/cache"
+	"github.com/kubesphere/kubekey/pkg/core/util"
+	"github.com/pkg/errors"
+)
+
+const checkpointDir = "checkpoints"
+
+// Checkpoint records the modules which a pipeline has completed, and the plain values of its pipeline cache,
+// so that a failed pipeline can be resumed without running the completed modules again. The fingerprint of the
+// config and the arguments is recorded as well, a checkpoint is not resumed by a different run.
+type Checkpoint struct {
+	Pipeline    string            `json:"pipeline"`
+	Fingerprint string            `json:"fingerprint"`
+	Modules     []string          `json:"modules"`
+	Strings     map[string]string `json:"strings,omitempty"`
+	Bools       map[string]bool   `json:"bools,omitempty"`
+	Ints        map[string]int    `json:"ints,omitempty"`
+
+	path      string
+	completed map[string]struct{}
+}
+
+func NewCheckpoint(workDir, objName, pipelineName, fingerprint string) *Checkpoint {
+	return &Checkpoint{
+		Pipeline:    pipelineName,
+		Fingerprint: fingerprint,
+		Modules:     make([]string, 0),
+		path:        filepath.Join(workDir, checkpointDir, fmt.Sprintf("%s-%s.json", objName, pipelineName)),
+		completed:   make(map[string]struct{}),
+	}
+}
+
+func (c *Checkpoint) Path() string {
+	return c.path
+}
+
+// Load reads the checkpoint left by the previous run. It is not an error if there is none, but it is if the
+// previous run had a different config or different arguments.
+func (c *Checkpoint) Load() error {
+	if !util.IsExist(c.path) {
+		return nil
+	}
+
+	content, err := ioutil.ReadFile(c.path)
+	if err != nil {
+		return errors.Wrapf(err, "read checkpoint %s failed", c.path)
+	}
+	fingerprint := c.Fingerprint
+	if err := json.Unmarshal(content, c); err != nil {
+		return errors.Wrapf(err, "parse checkpoint %s failed", c.path)
+	}
+	if c.Fingerprint != fingerprint {
+		return errors.Errorf("the config or the arguments have changed since checkpoint %s was written, "+
+			"run it again with them unchanged or without --resume", c.path)
+	}
+	for _, m := range c.Modules {
+		c.completed[m] = struct{}{}
+	}
+	return nil
+}
+
+// Restore puts the values saved in the checkpoint back into the pipeline cache.
+func (c *Checkpoint) Restore(pipelineCache *cache.Cache) {
+	for k, v := range c.Strings {
+		pipelineCache.Set(k, v)
+	}
+	for k, v := range c.Bools {
+		pipelineCache.Set(k, v)
+	}
+	for k, v := range c.Ints {
+		pipelineCache.Set(k, v)
+	}
+}
+
+func (c *Checkpoint) IsCompleted(key string) bool {
+	_, ok := c.completed[key]
+	return ok
+}
+
+// Complete marks the module as completed and persists the checkpoint together with the plain values of the
+// pipeline cache. The values of other types, e.g. clients or pointers, are not restorable and are dropped.
+func (c *Checkpoint) Complete(key string, pipelineCache *cache.Cache) error {
+	if !c.IsCompleted(key) {
+		c.completed[key] = struct{}{}
+		c.Modules = append(c.Modules, key)
+	}
+
+	c.Strings = make(map[string]string)
+	c.Bools = make(map[string]bool)
+	c.Ints = make(map[string]int)
+	pipelineCache.Range(func(key, value interface{}) bool {
+		k, ok := key.(string)
+		if !ok {
+			return true
+		}
+		switch v := value.(type) {
+		case string:
+			c.Strings[k] = v
+		case bool:
+			c.Bools[k] = v
+		case int:
+			c.Ints[k] = v
+		}
+		return true
+	})
+	return c.save()
+}
+
+func (c *Checkpoint) save() error {
+	if err := util.CreateDir(filepath.Dir(c.path)); err != nil {
+		return errors.Wrap(err, "create checkpoint dir failed")
+	}
+
+	content, err := json.MarshalIndent(c, "", "  ")
+	if err != nil {
+		return errors.Wrap(err, "marshal checkpoint failed")
+	}
+	if err := ioutil.WriteFile(c.path, content, 0644); err != nil {
+		return errors.Wrapf(err, "write checkpoint %s failed", c.path)
+	}
+	return nil
+}
+
+// Remove deletes the checkpoint once the pipeline has been executed successfully.
+func (c *Checkpoint) Remove() error {
+	if err := os.Remove(c.path); err != nil && !os.IsNotExist(err) {
+		return errors.Wrapf(err, "remove checkpoint %s failed", c.path)
+	}
+	return nil
+}
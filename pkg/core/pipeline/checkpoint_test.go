@@ -0,0 +1,103 @@
+/*
+ Copyright 2021 The KubeSphere Authors.
+
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+     http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package pipeline
+
+import (
+	"io/ioutil"
+	"os"
+	"testing"
+
+	"github.com/kubesphere/kubekey/pkg/core/cache"
+)
+
+func TestCheckpoint_CompleteAndLoad(t *testing.T) {
+	workDir, err := ioutil.TempDir("", "kubekey-checkpoint")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(workDir)
+
+	pipelineCache := cache.NewCache()
+	pipelineCache.Set("k8sVersion", "v1.21.5")
+	pipelineCache.Set("clusterExist", true)
+	pipelineCache.Set("count", 3)
+	pipelineCache.Set("binaries", map[string]string{"kubeadm": "kubeadm"})
+
+	c := NewCheckpoint(workDir, "sample", "CreateClusterPipeline", "fingerprint")
+	if err := c.Complete("0/NodePreCheckModule", pipelineCache); err != nil {
+		t.Fatal(err)
+	}
+	if err := c.Complete("1/NodeBinariesModule", pipelineCache); err != nil {
+		t.Fatal(err)
+	}
+
+	loaded := NewCheckpoint(workDir, "sample", "CreateClusterPipeline", "fingerprint")
+	if err := loaded.Load(); err != nil {
+		t.Fatal(err)
+	}
+	for _, key := range []string{"0/NodePreCheckModule", "1/NodeBinariesModule"} {
+		if !loaded.IsCompleted(key) {
+			t.Errorf("IsCompleted(%s) = false, want true", key)
+		}
+	}
+	if loaded.IsCompleted("2/ConfigureOSModule") {
+		t.Errorf("IsCompleted(2/ConfigureOSModule) = true, want false")
+	}
+
+	restored := cache.NewCache()
+	loaded.Restore(restored)
+	if v, _ := restored.GetMustString("k8sVersion"); v != "v1.21.5" {
+		t.Errorf("restored k8sVersion = %v, want v1.21.5", v)
+	}
+	if v, _ := restored.GetMustBool("clusterExist"); !v {
+		t.Errorf("restored clusterExist = %v, want true", v)
+	}
+	if v, _ := restored.GetMustInt("count"); v != 3 {
+		t.Errorf("restored count = %v, want 3", v)
+	}
+	if _, ok := restored.Get("binaries"); ok {
+		t.Errorf("the value which is not restorable should not be restored")
+	}
+
+	if err := loaded.Remove(); err != nil {
+		t.Fatal(err)
+	}
+	if err := NewCheckpoint(workDir, "sample", "CreateClusterPipeline", "fingerprint").Load(); err != nil {
+		t.Errorf("Load() without a checkpoint error = %v", err)
+	}
+}
+
+func TestCheckpoint_LoadFingerprintChanged(t *testing.T) {
+	workDir, err := ioutil.TempDir("", "kubekey-checkpoint")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(workDir)
+
+	c := NewCheckpoint(workDir, "sample", "CreateClusterPipeline", "fingerprint")
+	if err := c.Complete("0/NodePreCheckModule", cache.NewCache()); err != nil {
+		t.Fatal(err)
+	}
+
+	changed := NewCheckpoint(workDir, "sample", "CreateClusterPipeline", "changed")
+	if err := changed.Load(); err == nil {
+		t.Errorf("Load() should fail when the fingerprint has changed")
+	}
+	if changed.IsCompleted("0/NodePreCheckModule") {
+		t.Errorf("the modules of a checkpoint with another fingerprint should not be completed")
+	}
+}
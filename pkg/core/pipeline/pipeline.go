@@ -21,11 +21,15 @@ import (
 	"github.com/kubesphere/kubekey/pkg/core/cache"
 	"github.com/kubesphere/kubekey/pkg/core/connector"
 	"github.com/kubesphere/kubekey/pkg/core/ending"
+	"github.com/kubesphere/kubekey/pkg/core/event"
 	"github.com/kubesphere/kubekey/pkg/core/logger"
 	"github.com/kubesphere/kubekey/pkg/core/module"
+	"github.com/kubesphere/kubekey/pkg/core/util"
 	"github.com/pkg/errors"
 	"os"
+	"path/filepath"
 	"sync"
+	"time"
 )
 
 var logo = `
@@ -49,12 +53,36 @@ type Pipeline struct {
 	PipelineCache   *cache.Cache
 	ModuleCachePool sync.Pool
 	ModulePostHooks []module.PostHookInterface
+	// Fingerprint identifies the config and the arguments of the run. Only the pipelines with a fingerprint
+	// write checkpoints, and they can only be resumed with the same fingerprint.
+	Fingerprint string
+	Resume      bool
+	DryRun      bool
+	checkpoint  *Checkpoint
+	plan        *connector.Plan
 }
 
 func (p *Pipeline) Init() error {
 	fmt.Print(logo)
 	p.PipelineCache = cache.NewCache()
 	p.SpecHosts = len(p.Runtime.GetAllHosts())
+	if p.Fingerprint != "" {
+		p.checkpoint = NewCheckpoint(p.Runtime.GetWorkDir(), p.Runtime.GetObjName(), p.Name, p.Fingerprint)
+	}
+	if p.Resume {
+		if p.checkpoint == nil {
+			return errors.Errorf("Pipeline[%s] can not be resumed", p.Name)
+		}
+		if err := p.checkpoint.Load(); err != nil {
+			return err
+		}
+		p.checkpoint.Restore(p.PipelineCache)
+		logger.Log.Infof("Pipeline[%s] resume from checkpoint %s", p.Name, p.checkpoint.Path())
+	}
+	if p.DryRun {
+		p.plan = connector.NewPlan()
+		p.Runtime.SetPlan(p.plan)
+	}
 	//if err := p.Runtime.GenerateWorkDir(); err != nil {
 	//	return err
 	//}
@@ -64,17 +92,52 @@ func (p *Pipeline) Init() error {
 	return nil
 }
 
-func (p *Pipeline) Start() error {
+func (p *Pipeline) Start() (err error) {
+	start := time.Now()
+	event.Emit(&event.Event{Type: event.PipelineStart, Pipeline: p.Name})
+	defer func() {
+		status := ending.SUCCESS
+		if err != nil {
+			status = ending.FAILED
+		}
+		event.Emit(&event.Event{
+			Type:     event.PipelineEnd,
+			Pipeline: p.Name,
+			Status:   status.String(),
+			Duration: event.Duration(start, time.Now()),
+			Error:    event.ErrString(err),
+		})
+		if closeErr := event.Close(); closeErr != nil {
+			logger.Log.Warnf("close the event sink failed: %v", closeErr)
+		}
+	}()
+
 	if err := p.Init(); err != nil {
 		return errors.Wrapf(err, "Pipeline[%s] execute failed", p.Name)
 	}
 	for i := range p.Modules {
 		m := p.Modules[i]
 		if m.IsSkip() {
+			event.Emit(&event.Event{Type: event.ModuleEnd, Module: m.GetName(), Status: ending.SKIPPED.String()})
 			continue
 		}
 
 		p.InitModule(m)
+		key := fmt.Sprintf("%d/%s", i, m.GetName())
+		if p.checkpoint != nil && p.checkpoint.IsCompleted(key) && !isAlwaysRun(m) {
+			logger.Log.Infof("[%s] Skipped, it has been completed in the previous run", m.GetName())
+			event.Emit(&event.Event{Type: event.ModuleEnd, Module: m.GetName(), Status: ending.SKIPPED.String()})
+			continue
+		}
+
+		if p.DryRun {
+			p.plan.BeginModule(m.GetName())
+			if res := p.RunModule(m); res.IsFailed() {
+				return errors.Wrapf(res.CombineResult, "Pipeline[%s] dry run failed", p.Name)
+			}
+			continue
+		}
+
 		res := p.RunModule(m)
 		err := m.CallPostHook(res)
 		if res.IsFailed() {
@@ -83,11 +146,24 @@ func (p *Pipeline) Start() error {
 		if err != nil {
 			return errors.Wrapf(err, "Pipeline[%s] execute failed", p.Name)
 		}
+		if p.checkpoint != nil {
+			if err := p.checkpoint.Complete(key, p.PipelineCache); err != nil {
+				return errors.Wrapf(err, "Pipeline[%s] execute failed", p.Name)
+			}
+		}
 	}
 	p.releasePipelineCache()
+	if p.DryRun {
+		return p.printPlan()
+	}
 	if p.SpecHosts != len(p.Runtime.GetAllHosts()) {
 		return errors.Errorf("Pipeline[%s] execute failed: there are some error in your spec hosts", p.Name)
 	}
+	if p.checkpoint != nil {
+		if err := p.checkpoint.Remove(); err != nil {
+			logger.Log.Warnf("Pipeline[%s] %v", p.Name, err)
+		}
+	}
 	logger.Log.Infof("Pipeline[%s] execute successful", p.Name)
 	return nil
 }
@@ -107,6 +183,16 @@ func (p *Pipeline) RunModule(m module.Module) *ending.ModuleResult {
 	m.Slogan()
 
 	result := ending.NewModuleResult()
+	event.Emit(&event.Event{Type: event.ModuleStart, Module: m.GetName()})
+	defer func() {
+		event.Emit(&event.Event{
+			Type:     event.ModuleEnd,
+			Module:   m.GetName(),
+			Status:   result.Status.String(),
+			Duration: event.Duration(result.StartTime, result.EndTime),
+			Error:    event.ErrString(result.CombineResult),
+		})
+	}()
 	for {
 		switch m.Is() {
 		case module.TaskModuleType:
@@ -116,6 +202,10 @@ func (p *Pipeline) RunModule(m module.Module) *ending.ModuleResult {
 			}
 
 		case module.GoroutineModuleType:
+			if p.DryRun {
+				m.Run(result)
+				break
+			}
 			go func() {
 				m.Run(result)
 				if result.IsFailed() {
@@ -129,6 +219,11 @@ func (p *Pipeline) RunModule(m module.Module) *ending.ModuleResult {
 			}
 		}
 
+		// The loop conditions depend on the results of the real execution.
+		if p.DryRun {
+			break
+		}
+
 		stop, err := m.Until()
 		if err != nil {
 			result.LocalErrResult(err)
@@ -141,6 +236,25 @@ func (p *Pipeline) RunModule(m module.Module) *ending.ModuleResult {
 	return result
 }
 
+func (p *Pipeline) printPlan() error {
+	content := p.plan.String()
+	fmt.Printf("\nExecution plan of Pipeline[%s]:\n\n%s\n", p.Name, content)
+
+	path := filepath.Join(p.Runtime.GetWorkDir(), "plans", fmt.Sprintf("%s-%s.txt", p.Runtime.GetObjName(), p.Name))
+	if err := util.WriteFile(path, []byte(content)); err != nil {
+		return errors.Wrapf(err, "Pipeline[%s] save execution plan failed", p.Name)
+	}
+	logger.Log.Infof("Pipeline[%s] dry run successful, the execution plan is saved to %s", p.Name, path)
+	return nil
+}
+
+func isAlwaysRun(m module.Module) bool {
+	if a, ok := m.(module.AlwaysRun); ok {
+		return a.IsAlwaysRun()
+	}
+	return false
+}
+
 func (p *Pipeline) newModuleCache() *cache.Cache {
 	moduleCache, ok := p.ModuleCachePool.Get().(*cache.Cache)
 	if ok {
@@ -21,6 +21,8 @@ import (
 	"github.com/kubesphere/kubekey/pkg/core/connector"
 )
 
+// Prepare decides whether a task runs on a host. PreCheck is also executed against the hosts in dry-run mode to
+// decide which tasks are planned, so it must only read the state of the host and never change it.
 type Prepare interface {
 	PreCheck(runtime connector.Runtime) (bool, error)
 	Init(cache *cache.Cache, rootCache *cache.Cache)
@@ -27,6 +27,7 @@ import (
 	"github.com/kubesphere/kubekey/pkg/core/common"
 	"github.com/kubesphere/kubekey/pkg/core/connector"
 	"github.com/kubesphere/kubekey/pkg/core/ending"
+	"github.com/kubesphere/kubekey/pkg/core/event"
 	"github.com/kubesphere/kubekey/pkg/core/logger"
 	"github.com/kubesphere/kubekey/pkg/core/prepare"
 	"github.com/pkg/errors"
@@ -50,6 +51,10 @@ type LocalTask struct {
 	TaskResult    *ending.TaskResult
 }
 
+func (l *LocalTask) GetName() string {
+	return l.Name
+}
+
 func (l *LocalTask) GetDesc() string {
 	return l.Desc
 }
@@ -98,6 +103,13 @@ func (l *LocalTask) Execute() *ending.TaskResult {
 		Name: common.LocalHost,
 	}
 
+	if plan := l.Runtime.GetPlan(); plan != nil {
+		plan.Record(host.GetName(), l.Name, connector.PlanLocal, "local tasks are not executed in dry-run mode")
+		l.TaskResult.AppendSkip(host)
+		l.TaskResult.NormalResult()
+		return l.TaskResult
+	}
+
 	selfRuntime := l.Runtime.Copy()
 	l.RunWithTimeout(selfRuntime, host)
 
@@ -173,6 +185,7 @@ func (l *LocalTask) WhenWithRetry(runtime connector.Runtime, host connector.Host
 				continue
 			}
 			logger.Log.Infof("retry: [%s]", host.GetName())
+			event.Emit(&event.Event{Type: event.Retry, Task: l.Name, Host: host.GetName(), Retry: i + 1, Error: e.Error()})
 			time.Sleep(l.Delay)
 			continue
 		} else {
@@ -209,6 +222,7 @@ func (l *LocalTask) ExecuteWithRetry(runtime connector.Runtime, host connector.H
 				continue
 			}
 			logger.Log.Infof("retry: [%s]", host.GetName())
+			event.Emit(&event.Event{Type: event.Retry, Task: l.Name, Host: host.GetName(), Retry: i + 1, Error: e.Error()})
 			time.Sleep(l.Delay)
 			continue
 		} else {
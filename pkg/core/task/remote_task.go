@@ -27,6 +27,7 @@ import (
 	"github.com/kubesphere/kubekey/pkg/core/cache"
 	"github.com/kubesphere/kubekey/pkg/core/connector"
 	"github.com/kubesphere/kubekey/pkg/core/ending"
+	"github.com/kubesphere/kubekey/pkg/core/event"
 	"github.com/kubesphere/kubekey/pkg/core/logger"
 	"github.com/kubesphere/kubekey/pkg/core/prepare"
 	"github.com/kubesphere/kubekey/pkg/core/util"
@@ -34,17 +35,22 @@ import (
 )
 
 type RemoteTask struct {
-	Name        string
-	Desc        string
-	Hosts       []connector.Host
-	Prepare     prepare.Prepare
-	Action      action.Action
-	Rollback    rollback.Rollback
-	Parallel    bool
-	Retry       int
-	Delay       time.Duration
-	Timeout     time.Duration
+	Name     string
+	Desc     string
+	Hosts    []connector.Host
+	Prepare  prepare.Prepare
+	Action   action.Action
+	Rollback rollback.Rollback
+	Parallel bool
+	Retry    int
+	Delay    time.Duration
+	Timeout  time.Duration
+	// Concurrency is the ratio of the hosts which the task runs on at the same time,
+	// it is limited by the max parallel of the runtime.
 	Concurrency float64
+	// Rolling runs the task on the hosts batch by batch, the size of a batch is calculated by Concurrency.
+	// The next batch starts only when the previous one succeeded.
+	Rolling bool
 
 	PipelineCache *cache.Cache
 	ModuleCache   *cache.Cache
@@ -54,6 +60,10 @@ type RemoteTask struct {
 	TaskResult    *ending.TaskResult
 }
 
+func (t *RemoteTask) GetName() string {
+	return t.Name
+}
+
 func (t *RemoteTask) GetDesc() string {
 	return t.Desc
 }
@@ -70,27 +80,52 @@ func (t *RemoteTask) Execute() *ending.TaskResult {
 		return t.TaskResult
 	}
 
-	routinePool := make(chan struct{}, DefaultCon)
+	poolSize := t.poolSize()
+	routinePool := make(chan struct{}, poolSize)
 	defer close(routinePool)
 
 	ctx, cancel := context.WithTimeout(context.Background(), t.Timeout)
 	defer cancel()
-	wg := &sync.WaitGroup{}
-	for i := range t.Hosts {
-		if t.Hosts[i] == nil || t.Runtime.HostIsDeprecated(t.Hosts[i]) {
-			continue
+
+	batchSize := len(t.Hosts)
+	if t.Rolling {
+		batchSize = poolSize
+	}
+	for start := 0; start < len(t.Hosts); start += batchSize {
+		end := start + batchSize
+		if end > len(t.Hosts) {
+			end = len(t.Hosts)
 		}
-		selfRuntime := t.Runtime.Copy()
-		selfHost := t.Hosts[i].Copy()
 
-		wg.Add(1)
-		if t.Parallel {
-			go t.RunWithTimeout(ctx, selfRuntime, selfHost, i, wg, routinePool)
-		} else {
-			t.RunWithTimeout(ctx, selfRuntime, selfHost, i, wg, routinePool)
+		wg := &sync.WaitGroup{}
+		for i := start; i < end; i++ {
+			if t.Hosts[i] == nil || t.Runtime.HostIsDeprecated(t.Hosts[i]) {
+				continue
+			}
+			selfRuntime := t.Runtime.Copy()
+			selfHost := t.Hosts[i].Copy()
+
+			wg.Add(1)
+			if t.Parallel {
+				go t.RunWithTimeout(ctx, selfRuntime, selfHost, i, wg, routinePool)
+			} else {
+				t.RunWithTimeout(ctx, selfRuntime, selfHost, i, wg, routinePool)
+			}
+		}
+		wg.Wait()
+
+		if t.Rolling && t.TaskResult.IsFailed() {
+			if end < len(t.Hosts) {
+				logger.Log.Errorf("[%s] stop rolling, %d hosts are left untouched", t.Name, len(t.Hosts)-end)
+			}
+			break
 		}
 	}
-	wg.Wait()
+
+	if t.TaskResult.IsFailed() && t.IgnoreError {
+		logger.Log.Warningf("[%s] ignore the error: %v", t.Name, t.TaskResult.CombineErr())
+		t.TaskResult.Status = ending.NULL
+	}
 
 	if t.TaskResult.IsFailed() {
 		t.TaskResult.ErrResult()
@@ -134,9 +169,20 @@ func (t *RemoteTask) Run(runtime connector.Runtime, host connector.Host, index i
 		return
 	}
 
+	// The prepare is evaluated against the host in dry-run mode as well, its read-only probes are executed,
+	// so that the plan only contains the tasks which would run.
 	t.Prepare.Init(t.ModuleCache, t.PipelineCache)
 	t.Prepare.AutoAssert(runtime)
 	if ok, err := t.WhenWithRetry(runtime); !ok {
+		if plan := runtime.GetPlan(); plan != nil {
+			if err != nil {
+				plan.Record(host.GetName(), t.Name, connector.PlanNote, fmt.Sprintf("evaluate prepare failed: %v", err))
+			} else {
+				plan.Record(host.GetName(), t.Name, connector.PlanSkip, "the prepare conditions are not met")
+			}
+			t.TaskResult.AppendSkip(host)
+			return
+		}
 		if err != nil {
 			res = err
 			return
@@ -148,6 +194,11 @@ func (t *RemoteTask) Run(runtime connector.Runtime, host connector.Host, index i
 
 	t.Action.Init(t.ModuleCache, t.PipelineCache)
 	t.Action.AutoAssert(runtime)
+	if runtime.GetPlan() != nil {
+		t.ExecuteDryRun(runtime)
+		t.TaskResult.AppendSuccess(host)
+		return
+	}
 	if err := t.ExecuteWithRetry(runtime); err != nil {
 		res = err
 		return
@@ -197,6 +248,7 @@ func (t *RemoteTask) WhenWithRetry(runtime connector.Runtime) (bool, error) {
 				continue
 			}
 			logger.Log.Infof("retry: [%s]", runtime.GetRunner().Host.GetName())
+			event.Emit(&event.Event{Type: event.Retry, Task: t.Name, Host: runtime.GetRunner().Host.GetName(), Retry: i + 1, Error: e.Error()})
 			time.Sleep(t.Delay)
 			continue
 		} else {
@@ -221,6 +273,7 @@ func (t *RemoteTask) ExecuteWithRetry(runtime connector.Runtime) error {
 				continue
 			}
 			logger.Log.Infof("retry: [%s]", runtime.GetRunner().Host.GetName())
+			event.Emit(&event.Event{Type: event.Retry, Task: t.Name, Host: runtime.GetRunner().Host.GetName(), Retry: i + 1, Error: e.Error()})
 			time.Sleep(t.Delay)
 			continue
 		} else {
@@ -231,6 +284,18 @@ func (t *RemoteTask) ExecuteWithRetry(runtime connector.Runtime) error {
 	return err
 }
 
+// ExecuteDryRun executes the action once with a runner which records the operations into the plan
+// instead of issuing them. The recorded commands return empty output, so an action which depends on
+// the output may stop early, the reason is noted in the plan.
+func (t *RemoteTask) ExecuteDryRun(runtime connector.Runtime) {
+	r := runtime.GetRunner()
+	r.Plan = runtime.GetPlan()
+	r.Task = t.Name
+	if err := t.Action.Execute(runtime); err != nil {
+		r.Plan.Record(r.Host.GetName(), t.Name, connector.PlanNote, fmt.Sprintf("stop planning the task: %v", err))
+	}
+}
+
 func (t *RemoteTask) ExecuteRollback() {
 	if t.Rollback == nil {
 		return
@@ -241,7 +306,7 @@ func (t *RemoteTask) ExecuteRollback() {
 
 	ctx, cancel := context.WithTimeout(context.Background(), t.Timeout)
 	defer cancel()
-	routinePool := make(chan struct{}, DefaultCon)
+	routinePool := make(chan struct{}, t.poolSize())
 	defer close(routinePool)
 
 	rwg := &sync.WaitGroup{}
@@ -354,3 +419,15 @@ func (t *RemoteTask) calculateConcurrency() int {
 	}
 	return res
 }
+
+func (t *RemoteTask) poolSize() int {
+	size := t.calculateConcurrency()
+	max := t.Runtime.GetMaxParallel()
+	if max <= 0 {
+		max = DefaultCon
+	}
+	if size > max {
+		size = max
+	}
+	return size
+}
@@ -17,8 +17,12 @@
 package task
 
 import (
-	"github.com/kubesphere/kubekey/pkg/core/connector"
+	"errors"
 	"testing"
+
+	"github.com/kubesphere/kubekey/pkg/core/action"
+	"github.com/kubesphere/kubekey/pkg/core/cache"
+	"github.com/kubesphere/kubekey/pkg/core/connector"
 )
 
 func TestTask_calculateConcurrency(t1 *testing.T) {
@@ -138,3 +142,85 @@ func TestTask_calculateConcurrency(t1 *testing.T) {
 		})
 	}
 }
+
+func TestRemoteTask_poolSize(t1 *testing.T) {
+	tests := []struct {
+		name        string
+		hosts       int
+		concurrency float64
+		maxParallel int
+		want        int
+	}{
+		{name: "default max parallel", hosts: 20, concurrency: 1, maxParallel: 0, want: DefaultCon},
+		{name: "limited by max parallel", hosts: 20, concurrency: 1, maxParallel: 5, want: 5},
+		{name: "limited by concurrency", hosts: 20, concurrency: 0.2, maxParallel: 10, want: 4},
+		{name: "max parallel larger than default", hosts: 50, concurrency: 1, maxParallel: 30, want: 30},
+	}
+	for _, tt := range tests {
+		t1.Run(tt.name, func(t1 *testing.T) {
+			var hosts []connector.Host
+			for i := 0; i < tt.hosts; i++ {
+				hosts = append(hosts, &connector.BaseHost{})
+			}
+			runtime := &connector.BaseRuntime{}
+			runtime.SetMaxParallel(tt.maxParallel)
+
+			t := &RemoteTask{
+				Concurrency: tt.concurrency,
+				Hosts:       hosts,
+				Runtime:     runtime,
+			}
+			if got := t.poolSize(); got != tt.want {
+				t1.Errorf("poolSize() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+type fakeConnector struct{}
+
+func (f *fakeConnector) Connect(connector.Host) (connector.Connection, error) {
+	return nil, nil
+}
+
+type failedAction struct {
+	action.BaseAction
+}
+
+func (f *failedAction) Execute(connector.Runtime) error {
+	return errors.New("failed")
+}
+
+func TestRemoteTask_IgnoreError(t1 *testing.T) {
+	tests := []struct {
+		name        string
+		ignoreError bool
+		wantFailed  bool
+	}{
+		{name: "failed task", ignoreError: false, wantFailed: true},
+		{name: "ignored failed task", ignoreError: true, wantFailed: false},
+	}
+	for _, tt := range tests {
+		t1.Run(tt.name, func(t1 *testing.T) {
+			runtime := connector.NewBaseRuntime("test", &fakeConnector{}, false, false)
+			t := &RemoteTask{
+				Name:        "FailedTask",
+				Hosts:       []connector.Host{&connector.BaseHost{Name: "node1"}, &connector.BaseHost{Name: "node2"}},
+				Action:      new(failedAction),
+				Parallel:    true,
+				Retry:       1,
+				IgnoreError: tt.ignoreError,
+			}
+			t.Init(&runtime, cache.NewCache(), cache.NewCache())
+
+			res := t.Execute()
+			if got := res.IsFailed(); got != tt.wantFailed {
+				t1.Errorf("IsFailed() = %v, want %v", got, tt.wantFailed)
+			}
+			// the errors are kept for the report even if they are ignored
+			if got := len(res.ActionResults); got != 2 {
+				t1.Errorf("len(ActionResults) = %v, want 2", got)
+			}
+		})
+	}
+}
@@ -137,7 +137,13 @@ func WriteFile(fileName string, content []byte) error {
 }
 
 func Tar(src, dst, trimPrefix string) error {
-	fw, err := os.Create(dst)
+	return TarWithPerm(src, dst, trimPrefix, 0666)
+}
+
+// TarWithPerm is like Tar, but the archive is created with the permission, so it is never readable by others
+// when the permission does not allow it.
+func TarWithPerm(src, dst, trimPrefix string, perm os.FileMode) error {
+	fw, err := os.OpenFile(dst, os.O_CREATE|os.O_TRUNC|os.O_WRONLY, perm)
 	if err != nil {
 		return err
 	}
@@ -20,6 +20,7 @@ import (
 	"encoding/binary"
 	"github.com/kubesphere/kubekey/pkg/core/logger"
 	"github.com/pkg/errors"
+	"math/big"
 	"net"
 	"strconv"
 	"strings"
@@ -164,3 +165,55 @@ func LocalIP() string {
 	}
 	return localIp
 }
+
+// SplitAddresses splits a comma-separated list of addresses or CIDRs, such as the dual-stack "10.233.64.0/18,fd85:ee78:d8a6:8607::1:0000/112".
+func SplitAddresses(str string) []string {
+	var res []string
+	for _, s := range strings.Split(str, ",") {
+		if s = strings.TrimSpace(s); s != "" {
+			res = append(res, s)
+		}
+	}
+	return res
+}
+
+// IsIPv6 returns true if the address or the CIDR is IPv6.
+func IsIPv6(str string) bool {
+	ip := net.ParseIP(str)
+	if ip == nil {
+		ip, _, _ = net.ParseCIDR(str)
+	}
+	return ip != nil && ip.To4() == nil
+}
+
+// NthIP returns the n-th address of the CIDR, e.g. 10.233.0.1 is the 1st address of 10.233.0.0/18.
+// Unlike ParseIp, it does not enumerate the addresses, so it works with the large IPv6 CIDRs.
+func NthIP(cidr string, n int64) (string, error) {
+	_, ipnet, err := net.ParseCIDR(strings.TrimSpace(cidr))
+	if err != nil {
+		return "", errors.Wrapf(err, "invalid CIDR %s", cidr)
+	}
+	ones, bits := ipnet.Mask.Size()
+	size := new(big.Int).Lsh(big.NewInt(1), uint(bits-ones))
+	if big.NewInt(n).Cmp(size) >= 0 {
+		return "", errors.Errorf("the CIDR %s does not have %d addresses", cidr, n+1)
+	}
+
+	ip := ipnet.IP.To4()
+	if ip == nil {
+		ip = ipnet.IP.To16()
+	}
+	num := new(big.Int).Add(new(big.Int).SetBytes(ip), big.NewInt(n))
+	b := num.Bytes()
+	res := make(net.IP, len(ip))
+	copy(res[len(res)-len(b):], b)
+	return res.String(), nil
+}
+
+// URLHost returns the address in the form used by URLs, the IPv6 address is enclosed in square brackets.
+func URLHost(address string) string {
+	if IsIPv6(address) {
+		return "[" + address + "]"
+	}
+	return address
+}
@@ -53,3 +53,53 @@ func TestRound(t *testing.T) {
 		})
 	}
 }
+
+func TestNthIP(t *testing.T) {
+	type args struct {
+		cidr string
+		n    int64
+	}
+	tests := []struct {
+		name    string
+		args    args
+		want    string
+		wantErr bool
+	}{
+		{
+			name: "ipv4",
+			args: args{
+				cidr: "10.233.0.0/18",
+				n:    3,
+			},
+			want: "10.233.0.3",
+		},
+		{
+			name: "ipv6",
+			args: args{
+				cidr: "fd00:10:96::/108",
+				n:    10,
+			},
+			want: "fd00:10:96::a",
+		},
+		{
+			name: "out of range",
+			args: args{
+				cidr: "10.233.0.0/31",
+				n:    2,
+			},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := NthIP(tt.args.cidr, tt.args.n)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("NthIP() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if got != tt.want {
+				t.Errorf("NthIP() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -157,9 +157,10 @@ func (g *GenerateCerts) Execute(runtime connector.Runtime) error {
 
 	for _, host := range g.KubeConf.Cluster.Hosts {
 		dnsList = append(dnsList, host.Name)
-		internalAddress := netutils.ParseIPSloppy(host.InternalAddress)
-		if internalAddress != nil {
-			ipList = append(ipList, internalAddress)
+		for _, address := range host.InternalAddresses() {
+			if internalAddress := netutils.ParseIPSloppy(address); internalAddress != nil {
+				ipList = append(ipList, internalAddress)
+			}
 		}
 	}
 
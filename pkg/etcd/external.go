@@ -0,0 +1,60 @@
+/*
+ Copyright 2021 The KubeSphere Authors.
+
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+     http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package etcd
+
+import (
+	"net"
+	"net/url"
+	"path/filepath"
+
+	kubekeyapiv1alpha2 "github.com/kubesphere/kubekey/apis/kubekey/v1alpha2"
+	"github.com/kubesphere/kubekey/pkg/common"
+)
+
+// The TLS files of the external etcd are uploaded to the masters with the following names.
+const (
+	ExternalCaFile   = "external-etcd-ca.pem"
+	ExternalCertFile = "external-etcd-client.pem"
+	ExternalKeyFile  = "external-etcd-client-key.pem"
+)
+
+// ExternalEtcd returns the external etcd configuration which points to the TLS files uploaded to the masters.
+func ExternalEtcd(cluster *kubekeyapiv1alpha2.ClusterSpec) kubekeyapiv1alpha2.ExternalEtcd {
+	return kubekeyapiv1alpha2.ExternalEtcd{
+		Endpoints: cluster.Etcd.External.Endpoints,
+		CaFile:    filepath.Join(common.ETCDCertDir, ExternalCaFile),
+		CertFile:  filepath.Join(common.ETCDCertDir, ExternalCertFile),
+		KeyFile:   filepath.Join(common.ETCDCertDir, ExternalKeyFile),
+	}
+}
+
+// ExternalEndpointHosts returns the hosts of the external etcd endpoints, such as 192.168.0.2 of https://192.168.0.2:2379.
+func ExternalEndpointHosts(cluster *kubekeyapiv1alpha2.ClusterSpec) []string {
+	var hosts []string
+	for _, endpoint := range cluster.Etcd.External.Endpoints {
+		u, err := url.Parse(endpoint)
+		if err != nil || u.Host == "" {
+			continue
+		}
+		host := u.Host
+		if h, _, err := net.SplitHostPort(u.Host); err == nil {
+			host = h
+		}
+		hosts = append(hosts, host)
+	}
+	return hosts
+}
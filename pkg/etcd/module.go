@@ -17,14 +17,29 @@
 package etcd
 
 import (
+	"fmt"
+	"time"
+
 	"github.com/kubesphere/kubekey/pkg/common"
 	"github.com/kubesphere/kubekey/pkg/core/action"
+	"github.com/kubesphere/kubekey/pkg/core/connector"
+	"github.com/kubesphere/kubekey/pkg/core/ending"
+	"github.com/kubesphere/kubekey/pkg/core/logger"
 	"github.com/kubesphere/kubekey/pkg/core/task"
 	"github.com/kubesphere/kubekey/pkg/etcd/templates"
 )
 
 type PreCheckModule struct {
 	common.KubeModule
+	Skip bool
+}
+
+func (p *PreCheckModule) IsSkip() bool {
+	return p.Skip
+}
+
+func (p *PreCheckModule) IsAlwaysRun() bool {
+	return true
 }
 
 func (p *PreCheckModule) Init() {
@@ -46,6 +61,11 @@ func (p *PreCheckModule) Init() {
 
 type CertsModule struct {
 	common.KubeModule
+	Skip bool
+}
+
+func (c *CertsModule) IsSkip() bool {
+	return c.Skip
 }
 
 func (c *CertsModule) Init() {
@@ -95,8 +115,44 @@ func (c *CertsModule) Init() {
 	}
 }
 
+type ExternalCertsModule struct {
+	common.KubeModule
+	Skip bool
+}
+
+func (e *ExternalCertsModule) IsSkip() bool {
+	return e.Skip
+}
+
+func (e *ExternalCertsModule) Init() {
+	e.Name = "ExternalETCDCertsModule"
+	e.Desc = "Synchronize external etcd certs"
+
+	syncCerts := &task.RemoteTask{
+		Name:     "SyncExternalCertsToMaster",
+		Desc:     "Synchronize external etcd certs to master",
+		Hosts:    e.Runtime.GetHostsByRole(common.Master),
+		Action:   new(SyncExternalCerts),
+		Parallel: true,
+		Retry:    1,
+	}
+
+	e.Tasks = []task.Interface{
+		syncCerts,
+	}
+}
+
 type InstallETCDBinaryModule struct {
 	common.KubeModule
+	Skip bool
+}
+
+func (i *InstallETCDBinaryModule) IsSkip() bool {
+	return i.Skip
+}
+
+func (i *InstallETCDBinaryModule) IsAlwaysRun() bool {
+	return true
 }
 
 func (i *InstallETCDBinaryModule) Init() {
@@ -143,6 +199,11 @@ func (i *InstallETCDBinaryModule) Init() {
 
 type ConfigureModule struct {
 	common.KubeModule
+	Skip bool
+}
+
+func (e *ConfigureModule) IsSkip() bool {
+	return e.Skip
 }
 
 func (e *ConfigureModule) Init() {
@@ -316,6 +377,11 @@ func handleExistCluster(c *ConfigureModule) []task.Interface {
 
 type BackupModule struct {
 	common.KubeModule
+	Skip bool
+}
+
+func (b *BackupModule) IsSkip() bool {
+	return b.Skip
 }
 
 func (b *BackupModule) Init() {
@@ -334,3 +400,393 @@ func (b *BackupModule) Init() {
 		backupETCD,
 	}
 }
+
+type SnapshotModule struct {
+	common.KubeModule
+	LocalDir string
+}
+
+func (s *SnapshotModule) Init() {
+	s.Name = "ETCDSnapshotModule"
+	s.Desc = "Take a snapshot of ETCD cluster"
+
+	fileName := fmt.Sprintf("%s-etcd-snapshot-%s.db", s.Runtime.GetObjName(), time.Now().Format("20060102150405"))
+
+	accessAddress := &task.RemoteTask{
+		Name:     "GenerateAccessAddress",
+		Desc:     "Generate access address",
+		Hosts:    s.Runtime.GetHostsByRole(common.ETCD),
+		Prepare:  new(FirstETCDNode),
+		Action:   new(GenerateAccessAddress),
+		Parallel: true,
+		Retry:    1,
+	}
+
+	healthCheck := &task.RemoteTask{
+		Name:     "ETCDHealthCheck",
+		Desc:     "Health check on etcd",
+		Hosts:    s.Runtime.GetHostsByRole(common.ETCD),
+		Prepare:  new(FirstETCDNode),
+		Action:   new(HealthCheck),
+		Parallel: true,
+		Retry:    3,
+	}
+
+	snapshot := &task.RemoteTask{
+		Name:     "SnapshotETCD",
+		Desc:     "Save etcd snapshot",
+		Hosts:    s.Runtime.GetHostsByRole(common.ETCD),
+		Prepare:  new(FirstETCDNode),
+		Action:   &SnapshotETCD{FileName: fileName},
+		Parallel: false,
+		Retry:    1,
+	}
+
+	fetch := &task.RemoteTask{
+		Name:     "FetchSnapshot",
+		Desc:     "Fetch etcd snapshot to local",
+		Hosts:    s.Runtime.GetHostsByRole(common.ETCD),
+		Prepare:  new(FirstETCDNode),
+		Action:   &FetchSnapshot{LocalDir: s.LocalDir},
+		Parallel: false,
+		Retry:    2,
+	}
+
+	s.Tasks = []task.Interface{
+		accessAddress,
+		healthCheck,
+		snapshot,
+		fetch,
+	}
+}
+
+type RestoreModule struct {
+	common.KubeModule
+	Snapshot string
+}
+
+func (r *RestoreModule) Init() {
+	r.Name = "ETCDRestoreModule"
+	r.Desc = "Restore ETCD cluster from a snapshot"
+
+	// Let kubelet remove the static pods of the control plane before it is stopped.
+	stopControlPlane := &task.RemoteTask{
+		Name:     "StopControlPlane",
+		Desc:     "Stop control plane on masters",
+		Hosts:    r.Runtime.GetHostsByRole(common.Master),
+		Action:   new(StopControlPlane),
+		Parallel: true,
+		Retry:    1,
+	}
+
+	waitControlPlaneStopped := &task.RemoteTask{
+		Name:     "WaitControlPlaneStopped",
+		Desc:     "Wait for the control plane to be stopped",
+		Hosts:    r.Runtime.GetHostsByRole(common.Master),
+		Action:   new(WaitControlPlaneStopped),
+		Parallel: true,
+		Retry:    24,
+		Delay:    5 * time.Second,
+	}
+
+	stopKubelet := &task.RemoteTask{
+		Name:     "StopKubelet",
+		Desc:     "Stop kubelet on masters",
+		Hosts:    r.Runtime.GetHostsByRole(common.Master),
+		Action:   new(StopKubelet),
+		Parallel: true,
+		Retry:    1,
+	}
+
+	stopETCD := &task.RemoteTask{
+		Name:     "StopETCD",
+		Desc:     "Stop kubelet and etcd on etcd nodes",
+		Hosts:    r.Runtime.GetHostsByRole(common.ETCD),
+		Action:   new(StopETCD),
+		Parallel: true,
+		Retry:    1,
+	}
+
+	syncSnapshot := &task.RemoteTask{
+		Name:     "SyncSnapshot",
+		Desc:     "Synchronize etcd snapshot to etcd nodes",
+		Hosts:    r.Runtime.GetHostsByRole(common.ETCD),
+		Action:   &SyncSnapshot{Snapshot: r.Snapshot},
+		Parallel: true,
+		Retry:    2,
+	}
+
+	restoreData := &task.RemoteTask{
+		Name:     "RestoreETCDData",
+		Desc:     "Restore etcd member data from snapshot",
+		Hosts:    r.Runtime.GetHostsByRole(common.ETCD),
+		Action:   new(RestoreETCDData),
+		Parallel: true,
+		Retry:    1,
+	}
+
+	// All members have to be started together to make up the quorum.
+	start := &task.RemoteTask{
+		Name:     "StartETCD",
+		Desc:     "Start etcd",
+		Hosts:    r.Runtime.GetHostsByRole(common.ETCD),
+		Action:   new(RestartETCD),
+		Parallel: true,
+	}
+
+	accessAddress := &task.RemoteTask{
+		Name:     "GenerateAccessAddress",
+		Desc:     "Generate access address",
+		Hosts:    r.Runtime.GetHostsByRole(common.ETCD),
+		Prepare:  new(FirstETCDNode),
+		Action:   new(GenerateAccessAddress),
+		Parallel: true,
+		Retry:    1,
+	}
+
+	healthCheck := &task.RemoteTask{
+		Name:     "AllETCDNodeHealthCheck",
+		Desc:     "Health check on all etcd",
+		Hosts:    r.Runtime.GetHostsByRole(common.ETCD),
+		Action:   new(HealthCheck),
+		Parallel: true,
+		Retry:    20,
+	}
+
+	startControlPlane := &task.RemoteTask{
+		Name:     "StartControlPlane",
+		Desc:     "Restore control plane manifests on masters",
+		Hosts:    r.Runtime.GetHostsByRole(common.Master),
+		Action:   new(StartControlPlane),
+		Parallel: true,
+		Retry:    1,
+	}
+
+	startKubelet := &task.RemoteTask{
+		Name:     "StartKubelet",
+		Desc:     "Start kubelet",
+		Hosts:    kubeletHosts(r.Runtime),
+		Action:   new(StartKubelet),
+		Parallel: true,
+		Retry:    3,
+	}
+
+	controlPlaneHealthCheck := &task.RemoteTask{
+		Name:     "ControlPlaneHealthCheck",
+		Desc:     "Health check on kube-apiserver",
+		Hosts:    r.Runtime.GetHostsByRole(common.Master),
+		Prepare:  new(common.OnlyFirstMaster),
+		Action:   new(ControlPlaneHealthCheck),
+		Parallel: false,
+		Retry:    30,
+		Delay:    10 * time.Second,
+	}
+
+	r.Tasks = []task.Interface{
+		stopControlPlane,
+		waitControlPlaneStopped,
+		stopKubelet,
+		stopETCD,
+		syncSnapshot,
+		restoreData,
+		start,
+		accessAddress,
+		healthCheck,
+		startControlPlane,
+		startKubelet,
+		controlPlaneHealthCheck,
+	}
+}
+
+func (r *RestoreModule) Run(result *ending.ModuleResult) {
+	r.KubeModule.Run(result)
+	if !result.IsFailed() {
+		return
+	}
+
+	// The etcd data is put back and etcd is started before the control plane, which is stopped
+	// until the restore finishes.
+	rollbackData := &task.RemoteTask{
+		Name:     "RollbackETCDData",
+		Desc:     "Move back etcd data dir on etcd nodes",
+		Hosts:    r.Runtime.GetHostsByRole(common.ETCD),
+		Action:   new(RollbackETCDData),
+		Parallel: true,
+		Retry:    1,
+	}
+
+	startETCD := &task.RemoteTask{
+		Name:     "StartETCD",
+		Desc:     "Start etcd",
+		Hosts:    r.Runtime.GetHostsByRole(common.ETCD),
+		Action:   new(RestartETCD),
+		Parallel: true,
+		Retry:    1,
+	}
+
+	rollbackControlPlane := &task.RemoteTask{
+		Name:     "RollbackControlPlane",
+		Desc:     "Move back control plane manifests on masters",
+		Hosts:    r.Runtime.GetHostsByRole(common.Master),
+		Action:   new(RollbackControlPlane),
+		Parallel: true,
+		Retry:    1,
+	}
+
+	startKubelet := &task.RemoteTask{
+		Name:     "StartKubelet",
+		Desc:     "Start kubelet",
+		Hosts:    kubeletHosts(r.Runtime),
+		Action:   new(StartKubelet),
+		Parallel: true,
+		Retry:    1,
+	}
+
+	for _, t := range []task.Interface{rollbackData, startETCD, rollbackControlPlane, startKubelet} {
+		t.Init(r.Runtime.(connector.Runtime), r.ModuleCache, r.PipelineCache)
+		logger.Log.Infof("[%s] %s", r.Name, t.GetDesc())
+		if res := t.Execute(); res.IsFailed() {
+			logger.Log.Errorf("[%s] rollback failed, the cluster has to be recovered manually: %v", r.Name, res.CombineErr())
+			return
+		}
+	}
+}
+
+// kubeletHosts returns the masters and the etcd nodes running kubelet, which are stopped during restore.
+func kubeletHosts(runtime connector.ModuleRuntime) []connector.Host {
+	hosts := make([]connector.Host, 0)
+	set := make(map[string]struct{})
+	for _, h := range append(runtime.GetHostsByRole(common.Master), runtime.GetHostsByRole(common.ETCD)...) {
+		if !h.IsRole(common.K8s) {
+			continue
+		}
+		if _, ok := set[h.GetName()]; ok {
+			continue
+		}
+		set[h.GetName()] = struct{}{}
+		hosts = append(hosts, h)
+	}
+	return hosts
+}
+
+type RemoveMemberPreCheckModule struct {
+	common.KubeModule
+	Skip bool
+}
+
+func (r *RemoveMemberPreCheckModule) IsSkip() bool {
+	return r.Skip
+}
+
+func (r *RemoveMemberPreCheckModule) Init() {
+	r.Name = "ETCDRemoveMemberPreCheckModule"
+	r.Desc = "Check the etcd quorum before removing the member"
+
+	checkQuorum := &task.RemoteTask{
+		Name:   "CheckETCDQuorum",
+		Desc:   "Check the etcd quorum after removing the member",
+		Hosts:  remainingMember(r.KubeConf, r.Runtime),
+		Action: new(CheckQuorum),
+	}
+
+	r.Tasks = []task.Interface{
+		checkQuorum,
+	}
+}
+
+type RemoveMemberModule struct {
+	common.KubeModule
+	Skip bool
+}
+
+func (r *RemoveMemberModule) IsSkip() bool {
+	return r.Skip
+}
+
+func (r *RemoveMemberModule) Init() {
+	r.Name = "ETCDRemoveMemberModule"
+	r.Desc = "Remove the member from etcd cluster"
+
+	removeMember := &task.RemoteTask{
+		Name:   "RemoveETCDMember",
+		Desc:   "Remove etcd member",
+		Hosts:  remainingMember(r.KubeConf, r.Runtime),
+		Action: new(RemoveMember),
+		Retry:  3,
+	}
+
+	r.Tasks = []task.Interface{
+		removeMember,
+	}
+}
+
+type RefreshConfigModule struct {
+	common.KubeModule
+	Skip bool
+}
+
+func (r *RefreshConfigModule) IsSkip() bool {
+	return r.Skip
+}
+
+func (r *RefreshConfigModule) Init() {
+	r.Name = "ETCDRefreshConfigModule"
+	r.Desc = "Refresh etcd.env config on the remaining etcd"
+
+	getStatus := &task.RemoteTask{
+		Name:     "GetETCDStatus",
+		Desc:     "Get etcd status",
+		Hosts:    r.Runtime.GetHostsByRole(common.ETCD),
+		Action:   new(GetStatus),
+		Parallel: false,
+	}
+
+	refreshConfig := &task.RemoteTask{
+		Name:     "RefreshETCDConfig",
+		Desc:     "Refresh etcd.env config on all etcd",
+		Hosts:    r.Runtime.GetHostsByRole(common.ETCD),
+		Action:   &RefreshConfig{ToExisting: true},
+		Parallel: false,
+	}
+
+	accessAddress := &task.RemoteTask{
+		Name:     "GenerateAccessAddress",
+		Desc:     "Generate access address",
+		Hosts:    r.Runtime.GetHostsByRole(common.ETCD),
+		Prepare:  new(FirstETCDNode),
+		Action:   new(GenerateAccessAddress),
+		Parallel: true,
+		Retry:    1,
+	}
+
+	allETCDNodeHealthCheck := &task.RemoteTask{
+		Name:     "AllETCDNodeHealthCheck",
+		Desc:     "Health check on all etcd",
+		Hosts:    r.Runtime.GetHostsByRole(common.ETCD),
+		Action:   new(HealthCheck),
+		Parallel: true,
+		Retry:    20,
+	}
+
+	r.Tasks = []task.Interface{
+		getStatus,
+		refreshConfig,
+		accessAddress,
+		allETCDNodeHealthCheck,
+	}
+}
+
+// remainingMember returns the first etcd member which is not going to be deleted,
+// the etcd members are the masters if etcd is deployed by kubeadm.
+func remainingMember(kubeConf *common.KubeConf, runtime connector.ModuleRuntime) []connector.Host {
+	role := common.ETCD
+	if kubeConf.Cluster.Etcd.IsKubeadm() {
+		role = common.Master
+	}
+	for _, host := range runtime.GetHostsByRole(role) {
+		if host.GetName() != kubeConf.Arg.NodeName {
+			return []connector.Host{host}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,150 @@
+/*
+ Copyright 2021 The KubeSphere Authors.
+
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+     http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package etcd
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/kubesphere/kubekey/pkg/common"
+	"github.com/kubesphere/kubekey/pkg/core/cache"
+	"github.com/kubesphere/kubekey/pkg/core/connector"
+	"github.com/kubesphere/kubekey/pkg/core/ending"
+)
+
+type execRecord struct {
+	host string
+	cmd  string
+}
+
+// fakeConnection runs nothing, it records the commands and fails the ones containing failOn on failHost.
+type fakeConnection struct {
+	mu       *sync.Mutex
+	records  *[]execRecord
+	failHost string
+	failOn   string
+}
+
+func (f *fakeConnection) Exec(cmd string, host connector.Host) (string, int, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	*f.records = append(*f.records, execRecord{host: host.GetName(), cmd: cmd})
+	if host.GetName() == f.failHost && strings.Contains(cmd, f.failOn) {
+		return "", 1, fmt.Errorf("exec %s failed", f.failOn)
+	}
+	return "", 0, nil
+}
+
+func (f *fakeConnection) PExec(string, io.Reader, io.Writer, io.Writer, connector.Host) (int, error) {
+	return 0, nil
+}
+func (f *fakeConnection) Fetch(string, string, connector.Host) error  { return nil }
+func (f *fakeConnection) Scp(string, string, connector.Host) error    { return nil }
+func (f *fakeConnection) RemoteFileExist(string, connector.Host) bool { return true }
+func (f *fakeConnection) RemoteDirExist(string, connector.Host) (bool, error) {
+	return true, nil
+}
+func (f *fakeConnection) MkDirAll(string, string, connector.Host) error { return nil }
+func (f *fakeConnection) Chmod(string, os.FileMode) error               { return nil }
+func (f *fakeConnection) Close()                                        {}
+
+type fakeConnector struct {
+	conn *fakeConnection
+}
+
+func (f *fakeConnector) Connect(connector.Host) (connector.Connection, error) {
+	return f.conn, nil
+}
+
+// lastIndex returns the position of the last command of the host containing sub, or -1.
+func lastIndex(records []execRecord, host, sub string) int {
+	index := -1
+	for i, r := range records {
+		if r.host == host && strings.Contains(r.cmd, sub) {
+			index = i
+		}
+	}
+	return index
+}
+
+func TestRestoreModule_Rollback(t *testing.T) {
+	var records []execRecord
+	conn := &fakeConnection{mu: &sync.Mutex{}, records: &records, failHost: "node2", failOn: "snapshot restore"}
+	runtime := &common.KubeRuntime{BaseRuntime: connector.NewBaseRuntime("test", &fakeConnector{conn: conn}, false, false)}
+
+	hosts := map[string][]string{
+		"node1": {common.Master, common.ETCD, common.K8s},
+		"node2": {common.ETCD},
+		"node3": {common.ETCD},
+	}
+	for _, name := range []string{"node1", "node2", "node3"} {
+		host := connector.NewHost()
+		host.SetName(name)
+		host.SetInternalAddress("172.16.0.1")
+		for _, role := range hosts[name] {
+			host.SetRole(role)
+		}
+		host.GetCache().Set(common.ETCDExist, true)
+		host.GetCache().Set(common.ETCDName, "etcd-"+name)
+		runtime.AppendHost(host)
+		runtime.AppendRoleMap(host)
+	}
+
+	m := &RestoreModule{Snapshot: "snapshot.db"}
+	m.Default(runtime, cache.NewCache(), cache.NewCache())
+	m.AutoAssert()
+	m.Init()
+	result := ending.NewModuleResult()
+	m.Run(result)
+
+	if !result.IsFailed() {
+		t.Fatalf("the restore should fail on node2")
+	}
+
+	// The data dir of every etcd node is moved back before etcd is started, and the control plane is started after etcd.
+	lastETCDStart := -1
+	for name := range hosts {
+		moveBack := lastIndex(records, name, "rm -rf /var/lib/etcd && mv /var/lib/etcd-")
+		if moveBack < 0 {
+			t.Errorf("the etcd data dir of %s is not moved back", name)
+		}
+		start := lastIndex(records, name, "systemctl restart etcd")
+		if start < moveBack {
+			t.Errorf("etcd on %s is not started after its data dir is moved back", name)
+		}
+		if start > lastETCDStart {
+			lastETCDStart = start
+		}
+	}
+	for _, host := range runtime.GetHostsByRole(common.ETCD) {
+		if _, ok := host.GetCache().GetMustString(common.ETCDDataBak); ok {
+			t.Errorf("the etcd data backup of %s should be cleared after the rollback", host.GetName())
+		}
+	}
+
+	manifests := lastIndex(records, "node1", "mv -f "+ManifestBakDir)
+	if manifests < lastETCDStart {
+		t.Errorf("the control plane manifests are moved back before etcd is started on all etcd nodes")
+	}
+	if kubelet := lastIndex(records, "node1", "systemctl start kubelet"); kubelet < manifests {
+		t.Errorf("kubelet is not started after the control plane manifests are moved back")
+	}
+}
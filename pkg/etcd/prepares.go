@@ -22,6 +22,7 @@ import (
 	"github.com/kubesphere/kubekey/pkg/common"
 	"github.com/kubesphere/kubekey/pkg/core/connector"
 	"github.com/pkg/errors"
+	versionutil "k8s.io/apimachinery/pkg/util/version"
 )
 
 type FirstETCDNode struct {
@@ -58,3 +59,25 @@ func (n *NodeETCDExist) PreCheck(runtime connector.Runtime) (bool, error) {
 	}
 	return false, errors.New("get etcd node status by host label failed")
 }
+
+// OutdatedVersion checks whether the etcd on the node is older than the version the Kubernetes version being
+// upgraded to requires.
+type OutdatedVersion struct {
+	common.KubePrepare
+}
+
+func (o *OutdatedVersion) PreCheck(runtime connector.Runtime) (bool, error) {
+	required := RequiredVersion(o.KubeConf.Cluster.Kubernetes.Version)
+	if required == "" {
+		return false, nil
+	}
+	version, ok := runtime.RemoteHost().GetCache().GetMustString(common.ETCDVersion)
+	if !ok {
+		return false, errors.New("get etcd version by host cache failed")
+	}
+	current, err := versionutil.ParseSemantic(version)
+	if err != nil {
+		return false, errors.Wrapf(err, "parse etcd version %s failed", version)
+	}
+	return current.LessThan(versionutil.MustParseSemantic(required)), nil
+}
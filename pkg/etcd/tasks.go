@@ -17,15 +17,19 @@
 package etcd
 
 import (
+	"encoding/json"
 	"fmt"
 	"github.com/kubesphere/kubekey/pkg/files"
 	"path/filepath"
 	"strings"
+	"time"
 
 	kubekeyapiv1alpha2 "github.com/kubesphere/kubekey/apis/kubekey/v1alpha2"
 	"github.com/kubesphere/kubekey/pkg/common"
 	"github.com/kubesphere/kubekey/pkg/core/action"
 	"github.com/kubesphere/kubekey/pkg/core/connector"
+	"github.com/kubesphere/kubekey/pkg/core/logger"
+	"github.com/kubesphere/kubekey/pkg/core/task"
 	"github.com/kubesphere/kubekey/pkg/core/util"
 	"github.com/kubesphere/kubekey/pkg/etcd/templates"
 	"github.com/kubesphere/kubekey/pkg/utils"
@@ -83,12 +87,12 @@ func (g *GetStatus) Execute(runtime connector.Runtime) error {
 
 		if v, ok := g.PipelineCache.Get(common.ETCDCluster); ok {
 			c := v.(*EtcdCluster)
-			c.peerAddresses = append(c.peerAddresses, fmt.Sprintf("%s=https://%s:2380", etcdName, host.GetInternalAddress()))
+			c.peerAddresses = append(c.peerAddresses, fmt.Sprintf("%s=https://%s:2380", etcdName, util.URLHost(host.GetInternalAddress())))
 			c.clusterExist = true
 			// type: *EtcdCluster
 			g.PipelineCache.Set(common.ETCDCluster, c)
 		} else {
-			cluster.peerAddresses = append(cluster.peerAddresses, fmt.Sprintf("%s=https://%s:2380", etcdName, host.GetInternalAddress()))
+			cluster.peerAddresses = append(cluster.peerAddresses, fmt.Sprintf("%s=https://%s:2380", etcdName, util.URLHost(host.GetInternalAddress())))
 			cluster.clusterExist = true
 			g.PipelineCache.Set(common.ETCDCluster, cluster)
 		}
@@ -168,7 +172,7 @@ type GenerateAccessAddress struct {
 func (g *GenerateAccessAddress) Execute(runtime connector.Runtime) error {
 	var addrList []string
 	for _, host := range runtime.GetHostsByRole(common.ETCD) {
-		addrList = append(addrList, fmt.Sprintf("https://%s:2379", host.GetInternalAddress()))
+		addrList = append(addrList, fmt.Sprintf("https://%s:2379", util.URLHost(host.GetInternalAddress())))
 	}
 
 	accessAddresses := strings.Join(addrList, ",")
@@ -226,7 +230,7 @@ func (g *GenerateConfig) Execute(runtime connector.Runtime) error {
 	if v, ok := g.PipelineCache.Get(common.ETCDCluster); ok {
 		cluster := v.(*EtcdCluster)
 
-		cluster.peerAddresses = append(cluster.peerAddresses, fmt.Sprintf("%s=https://%s:2380", etcdName, host.GetInternalAddress()))
+		cluster.peerAddresses = append(cluster.peerAddresses, fmt.Sprintf("%s=https://%s:2380", etcdName, util.URLHost(host.GetInternalAddress())))
 		g.PipelineCache.Set(common.ETCDCluster, cluster)
 
 		if !cluster.clusterExist {
@@ -294,7 +298,7 @@ func refreshConfig(runtime connector.Runtime, endpoints []string, state, etcdNam
 		Data: util.Data{
 			"Tag":             kubekeyapiv1alpha2.DefaultEtcdVersion,
 			"Name":            etcdName,
-			"Ip":              host.GetInternalAddress(),
+			"Ip":              util.URLHost(host.GetInternalAddress()),
 			"Hostname":        host.GetName(),
 			"State":           state,
 			"peerAddresses":   strings.Join(endpoints, ","),
@@ -329,7 +333,7 @@ func (j *JoinMember) Execute(runtime connector.Runtime) error {
 			"export ETCDCTL_CA_FILE='/etc/ssl/etcd/ssl/ca.pem';"+
 			"%s/etcdctl --endpoints=%s member add %s %s",
 			host.GetName(), host.GetName(), common.BinDir, cluster.accessAddresses, etcdName,
-			fmt.Sprintf("https://%s:2380", host.GetInternalAddress()))
+			fmt.Sprintf("https://%s:2380", util.URLHost(host.GetInternalAddress())))
 
 		if _, err := runtime.GetRunner().SudoCmd(joinMemberCmd, true); err != nil {
 			return errors.Wrap(errors.WithStack(err), "add etcd member failed")
@@ -357,7 +361,7 @@ func (c *CheckMember) Execute(runtime connector.Runtime) error {
 		if err != nil {
 			return errors.Wrap(errors.WithStack(err), "list etcd member failed")
 		}
-		if !strings.Contains(memberList, fmt.Sprintf("https://%s:2379", host.GetInternalAddress())) {
+		if !strings.Contains(memberList, fmt.Sprintf("https://%s:2379", util.URLHost(host.GetInternalAddress()))) {
 			return errors.Wrap(errors.WithStack(err), "add etcd member failed")
 		}
 	} else {
@@ -377,6 +381,70 @@ func (r *RestartETCD) Execute(runtime connector.Runtime) error {
 	return nil
 }
 
+type GetVersion struct {
+	common.KubeAction
+}
+
+func (g *GetVersion) Execute(runtime connector.Runtime) error {
+	output, err := runtime.GetRunner().SudoCmd(
+		fmt.Sprintf("%s/etcd --version | grep 'etcd Version' | cut -d' ' -f3", common.BinDir), false)
+	if err != nil {
+		return errors.Wrap(errors.WithStack(err), "get etcd version failed")
+	}
+	runtime.RemoteHost().GetCache().Set(common.ETCDVersion, "v"+strings.TrimPrefix(strings.TrimSpace(output), "v"))
+	return nil
+}
+
+type UpgradeMember struct {
+	common.KubeAction
+}
+
+func (u *UpgradeMember) Execute(runtime connector.Runtime) error {
+	host := runtime.RemoteHost()
+
+	installETCDBinary := &task.RemoteTask{
+		Name:     "InstallETCDBinary",
+		Desc:     "Install etcd using binary",
+		Hosts:    []connector.Host{host},
+		Action:   new(InstallETCDBinary),
+		Parallel: false,
+		Retry:    1,
+	}
+
+	restart := &task.RemoteTask{
+		Name:     "RestartETCD",
+		Desc:     "Restart etcd",
+		Hosts:    []connector.Host{host},
+		Action:   new(RestartETCD),
+		Parallel: false,
+	}
+
+	healthCheck := &task.RemoteTask{
+		Name:     "UpgradedETCDHealthCheck",
+		Desc:     "Health check on upgraded etcd",
+		Hosts:    []connector.Host{host},
+		Action:   new(HealthCheck),
+		Parallel: false,
+		Retry:    20,
+	}
+
+	tasks := []task.Interface{
+		installETCDBinary,
+		restart,
+		healthCheck,
+	}
+
+	for i := range tasks {
+		t := tasks[i]
+		t.Init(runtime, u.ModuleCache, u.PipelineCache)
+		if res := t.Execute(); res.IsFailed() {
+			return errors.Wrap(res.CombineErr(), fmt.Sprintf("upgrade etcd failed: %s", host.GetName()))
+		}
+	}
+	host.GetCache().Set(common.ETCDVersion, RequiredVersion(u.KubeConf.Cluster.Kubernetes.Version))
+	return nil
+}
+
 type BackupETCD struct {
 	common.KubeAction
 }
@@ -387,7 +455,7 @@ func (b *BackupETCD) Execute(runtime connector.Runtime) error {
 		Dst:      filepath.Join(b.KubeConf.Cluster.Kubernetes.EtcdBackupScriptDir, "etcd-backup.sh"),
 		Data: util.Data{
 			"Hostname":            runtime.RemoteHost().GetName(),
-			"Etcdendpoint":        fmt.Sprintf("https://%s:2379", runtime.RemoteHost().GetInternalAddress()),
+			"Etcdendpoint":        fmt.Sprintf("https://%s:2379", util.URLHost(runtime.RemoteHost().GetInternalAddress())),
 			"Backupdir":           b.KubeConf.Cluster.Kubernetes.EtcdBackupDir,
 			"KeepbackupNumber":    b.KubeConf.Cluster.Kubernetes.KeepBackupNumber,
 			"EtcdBackupPeriod":    b.KubeConf.Cluster.Kubernetes.EtcdBackupPeriod,
@@ -410,3 +478,436 @@ func (b *BackupETCD) Execute(runtime connector.Runtime) error {
 	}
 	return nil
 }
+
+const (
+	SnapshotFile   = "snapshotFile"
+	RestoreTmpFile = "etcd-snapshot.db"
+	ManifestBakDir = "/etc/kubernetes/manifests-etcd-restore"
+)
+
+// etcdctlV3 returns the etcdctl v3 command with the admin certs of the host.
+func etcdctlV3(host connector.Host, endpoints string) string {
+	return fmt.Sprintf("export ETCDCTL_API=3;"+
+		"%s/etcdctl --endpoints=%s "+
+		"--cacert=/etc/ssl/etcd/ssl/ca.pem "+
+		"--cert=/etc/ssl/etcd/ssl/admin-%s.pem "+
+		"--key=/etc/ssl/etcd/ssl/admin-%s-key.pem",
+		common.BinDir, endpoints, host.GetName(), host.GetName())
+}
+
+type SnapshotETCD struct {
+	common.KubeAction
+	FileName string
+}
+
+func (s *SnapshotETCD) Execute(runtime connector.Runtime) error {
+	host := runtime.RemoteHost()
+	backupDir := s.KubeConf.Cluster.Kubernetes.EtcdBackupDir
+	remoteFile := filepath.Join(backupDir, s.FileName)
+
+	if _, err := runtime.GetRunner().SudoCmd(fmt.Sprintf("mkdir -p %s", backupDir), false); err != nil {
+		return errors.Wrap(errors.WithStack(err), fmt.Sprintf("create etcd backup dir %s failed", backupDir))
+	}
+
+	endpoint := fmt.Sprintf("https://%s:2379", util.URLHost(host.GetInternalAddress()))
+	if _, err := runtime.GetRunner().SudoCmd(fmt.Sprintf("%s snapshot save %s", etcdctlV3(host, endpoint), remoteFile), true); err != nil {
+		return errors.Wrap(errors.WithStack(err), "save etcd snapshot failed")
+	}
+	if _, err := runtime.GetRunner().SudoCmd(fmt.Sprintf("export ETCDCTL_API=3;%s/etcdctl snapshot status %s -w table", common.BinDir, remoteFile), true); err != nil {
+		return errors.Wrap(errors.WithStack(err), fmt.Sprintf("check etcd snapshot %s failed", remoteFile))
+	}
+
+	s.ModuleCache.Set(SnapshotFile, remoteFile)
+	return nil
+}
+
+type FetchSnapshot struct {
+	common.KubeAction
+	LocalDir string
+}
+
+func (f *FetchSnapshot) Execute(runtime connector.Runtime) error {
+	remoteFile, ok := f.ModuleCache.GetMustString(SnapshotFile)
+	if !ok {
+		return errors.New("get etcd snapshot file by module cache failed")
+	}
+
+	if err := utils.ResetTmpDir(runtime); err != nil {
+		return err
+	}
+
+	tmpFile := filepath.Join(common.TmpDir, filepath.Base(remoteFile))
+	if _, err := runtime.GetRunner().SudoCmd(fmt.Sprintf("cp -f %s %s && chmod 0644 %s", remoteFile, tmpFile, tmpFile), false); err != nil {
+		return errors.Wrap(errors.WithStack(err), "copy etcd snapshot to tmp dir failed")
+	}
+
+	if err := util.CreateDir(f.LocalDir); err != nil {
+		return errors.Wrap(errors.WithStack(err), fmt.Sprintf("create local dir %s failed", f.LocalDir))
+	}
+	localFile := filepath.Join(f.LocalDir, filepath.Base(remoteFile))
+	if err := runtime.GetRunner().Fetch(localFile, tmpFile); err != nil {
+		return errors.Wrap(errors.WithStack(err), "fetch etcd snapshot failed")
+	}
+	logger.Log.Infof("etcd snapshot is saved to %s", localFile)
+	return nil
+}
+
+type StopControlPlane struct {
+	common.KubeAction
+}
+
+func (s *StopControlPlane) Execute(runtime connector.Runtime) error {
+	host := runtime.RemoteHost()
+	// Let kubelet remove the static pods of the control plane before it is stopped.
+	if _, err := runtime.GetRunner().SudoCmd(fmt.Sprintf(
+		"mkdir -p %s && mv -f %s/kube-apiserver.yaml %s/kube-controller-manager.yaml %s/kube-scheduler.yaml %s/",
+		ManifestBakDir, common.KubeManifestDir, common.KubeManifestDir, common.KubeManifestDir, ManifestBakDir), false); err != nil {
+		return errors.Wrap(errors.WithStack(err), fmt.Sprintf("move control plane manifests failed: %s", host.GetName()))
+	}
+	return nil
+}
+
+// WaitControlPlaneStopped fails until kubelet has removed the static pods of the control plane, the task is retried to poll them.
+type WaitControlPlaneStopped struct {
+	common.KubeAction
+}
+
+func (w *WaitControlPlaneStopped) Execute(runtime connector.Runtime) error {
+	host := runtime.RemoteHost()
+	// the process name of kube-controller-manager is truncated to 15 characters.
+	out, err := runtime.GetRunner().SudoCmd("pgrep -x 'kube-apiserver|kube-controller|kube-scheduler' || true", false)
+	if err != nil {
+		return errors.Wrap(errors.WithStack(err), fmt.Sprintf("check control plane processes failed: %s", host.GetName()))
+	}
+	if strings.TrimSpace(out) != "" {
+		return fmt.Errorf("the control plane is still running on %s", host.GetName())
+	}
+	return nil
+}
+
+type StopKubelet struct {
+	common.KubeAction
+}
+
+func (s *StopKubelet) Execute(runtime connector.Runtime) error {
+	host := runtime.RemoteHost()
+	if _, err := runtime.GetRunner().SudoCmd("systemctl stop kubelet", false); err != nil {
+		return errors.Wrap(errors.WithStack(err), fmt.Sprintf("stop kubelet failed: %s", host.GetName()))
+	}
+	return nil
+}
+
+type StopETCD struct {
+	common.KubeAction
+}
+
+func (s *StopETCD) Execute(runtime connector.Runtime) error {
+	host := runtime.RemoteHost()
+	if host.IsRole(common.K8s) {
+		if _, err := runtime.GetRunner().SudoCmd("systemctl stop kubelet", false); err != nil {
+			return errors.Wrap(errors.WithStack(err), fmt.Sprintf("stop kubelet failed: %s", host.GetName()))
+		}
+	}
+	if _, err := runtime.GetRunner().SudoCmd("systemctl stop etcd", false); err != nil {
+		return errors.Wrap(errors.WithStack(err), fmt.Sprintf("stop etcd failed: %s", host.GetName()))
+	}
+	return nil
+}
+
+type SyncSnapshot struct {
+	common.KubeAction
+	Snapshot string
+}
+
+func (s *SyncSnapshot) Execute(runtime connector.Runtime) error {
+	if err := utils.ResetTmpDir(runtime); err != nil {
+		return err
+	}
+
+	if err := runtime.GetRunner().Scp(s.Snapshot, filepath.Join(common.TmpDir, RestoreTmpFile)); err != nil {
+		return errors.Wrap(errors.WithStack(err), fmt.Sprintf("sync etcd snapshot %s failed", s.Snapshot))
+	}
+	return nil
+}
+
+type RestoreETCDData struct {
+	common.KubeAction
+}
+
+func (r *RestoreETCDData) Execute(runtime connector.Runtime) error {
+	host := runtime.RemoteHost()
+	if exist, ok := host.GetCache().GetMustBool(common.ETCDExist); !ok || !exist {
+		return fmt.Errorf("etcd is not installed on %s, it can not be restored", host.GetName())
+	}
+
+	var initialCluster []string
+	for _, h := range runtime.GetHostsByRole(common.ETCD) {
+		name, ok := h.GetCache().GetMustString(common.ETCDName)
+		if !ok {
+			return fmt.Errorf("get etcd name of %s by host cache failed", h.GetName())
+		}
+		initialCluster = append(initialCluster, fmt.Sprintf("%s=https://%s:2380", name, util.URLHost(h.GetInternalAddress())))
+	}
+	etcdName, _ := host.GetCache().GetMustString(common.ETCDName)
+
+	// The current data is kept, it can be moved back if the restored cluster is not expected.
+	bakDir := fmt.Sprintf("/var/lib/etcd-%s.bak", time.Now().Format("20060102150405"))
+	if _, err := runtime.GetRunner().SudoCmd(fmt.Sprintf("if [ -d /var/lib/etcd ]; then mv /var/lib/etcd %s; fi", bakDir), false); err != nil {
+		return errors.Wrap(errors.WithStack(err), fmt.Sprintf("backup etcd data dir failed: %s", host.GetName()))
+	}
+	host.GetCache().Set(common.ETCDDataBak, bakDir)
+
+	restoreCmd := fmt.Sprintf("export ETCDCTL_API=3;"+
+		"%s/etcdctl snapshot restore %s "+
+		"--name=%s "+
+		"--initial-cluster=%s "+
+		"--initial-cluster-token=k8s_etcd "+
+		"--initial-advertise-peer-urls=https://%s:2380 "+
+		"--data-dir=/var/lib/etcd",
+		common.BinDir, filepath.Join(common.TmpDir, RestoreTmpFile), etcdName, strings.Join(initialCluster, ","), util.URLHost(host.GetInternalAddress()))
+	if _, err := runtime.GetRunner().SudoCmd(restoreCmd, true); err != nil {
+		return errors.Wrap(errors.WithStack(err), fmt.Sprintf("restore etcd snapshot failed: %s", host.GetName()))
+	}
+	return nil
+}
+
+type StartControlPlane struct {
+	common.KubeAction
+}
+
+func (s *StartControlPlane) Execute(runtime connector.Runtime) error {
+	host := runtime.RemoteHost()
+	if _, err := runtime.GetRunner().SudoCmd(fmt.Sprintf("mv -f %s/*.yaml %s/ && rm -rf %s",
+		ManifestBakDir, common.KubeManifestDir, ManifestBakDir), false); err != nil {
+		return errors.Wrap(errors.WithStack(err), fmt.Sprintf("move back control plane manifests failed: %s", host.GetName()))
+	}
+	return nil
+}
+
+// RollbackETCDData moves the data dir kept by RestoreETCDData back over the restored one.
+type RollbackETCDData struct {
+	common.KubeAction
+}
+
+func (r *RollbackETCDData) Execute(runtime connector.Runtime) error {
+	host := runtime.RemoteHost()
+	bakDir, ok := host.GetCache().GetMustString(common.ETCDDataBak)
+	if !ok {
+		return nil
+	}
+	if _, err := runtime.GetRunner().SudoCmd(fmt.Sprintf("systemctl stop etcd; if [ -d %s ]; then rm -rf /var/lib/etcd && mv %s /var/lib/etcd; fi",
+		bakDir, bakDir), false); err != nil {
+		return errors.Wrap(errors.WithStack(err), fmt.Sprintf("move back etcd data dir %s failed: %s", bakDir, host.GetName()))
+	}
+	host.GetCache().Delete(common.ETCDDataBak)
+	return nil
+}
+
+// RollbackControlPlane moves the control plane manifests back, the masters are left stopped otherwise
+// when the restore fails.
+type RollbackControlPlane struct {
+	common.KubeAction
+}
+
+func (r *RollbackControlPlane) Execute(runtime connector.Runtime) error {
+	host := runtime.RemoteHost()
+	if _, err := runtime.GetRunner().SudoCmd(fmt.Sprintf("if [ -d %s ]; then mv -f %s/*.yaml %s/ && rm -rf %s; fi",
+		ManifestBakDir, ManifestBakDir, common.KubeManifestDir, ManifestBakDir), false); err != nil {
+		return errors.Wrap(errors.WithStack(err), fmt.Sprintf("move back control plane manifests failed: %s", host.GetName()))
+	}
+	return nil
+}
+
+type StartKubelet struct {
+	common.KubeAction
+}
+
+func (s *StartKubelet) Execute(runtime connector.Runtime) error {
+	host := runtime.RemoteHost()
+	if _, err := runtime.GetRunner().SudoCmd("systemctl start kubelet", false); err != nil {
+		return errors.Wrap(errors.WithStack(err), fmt.Sprintf("start kubelet failed: %s", host.GetName()))
+	}
+	return nil
+}
+
+type ControlPlaneHealthCheck struct {
+	common.KubeAction
+}
+
+func (c *ControlPlaneHealthCheck) Execute(runtime connector.Runtime) error {
+	if _, err := runtime.GetRunner().SudoCmd(
+		fmt.Sprintf("%s/kubectl --kubeconfig %s get --raw=/healthz", common.BinDir, filepath.Join(common.KubeConfigDir, "admin.conf")),
+		false); err != nil {
+		return errors.Wrap(errors.WithStack(err), "kube-apiserver is not healthy")
+	}
+	return nil
+}
+
+type SyncExternalCerts struct {
+	common.KubeAction
+}
+
+func (s *SyncExternalCerts) Execute(runtime connector.Runtime) error {
+	external := s.KubeConf.Cluster.Etcd.External
+	files := map[string]string{
+		external.CaFile:   ExternalCaFile,
+		external.CertFile: ExternalCertFile,
+		external.KeyFile:  ExternalKeyFile,
+	}
+
+	if _, err := runtime.GetRunner().SudoCmd(fmt.Sprintf("mkdir -p %s", common.ETCDCertDir), false); err != nil {
+		return errors.Wrap(errors.WithStack(err), "create etcd certs dir failed")
+	}
+	for src, name := range files {
+		dst := filepath.Join(common.ETCDCertDir, name)
+		if err := runtime.GetRunner().SudoScp(src, dst); err != nil {
+			return errors.Wrap(errors.WithStack(err), fmt.Sprintf("sync external etcd cert %s failed", src))
+		}
+	}
+	return nil
+}
+
+type etcdMemberList struct {
+	Members []struct {
+		ID       uint64   `json:"ID"`
+		Name     string   `json:"name"`
+		PeerURLs []string `json:"peerURLs"`
+	} `json:"members"`
+}
+
+type etcdEndpointHealth struct {
+	Endpoint string `json:"endpoint"`
+	Health   bool   `json:"health"`
+}
+
+// MemberEtcdctl returns the etcdctl command which manages the members on the remote host.
+// The etcdctl in the static pod is used if etcd is deployed by kubeadm.
+func MemberEtcdctl(kubeConf *common.KubeConf, host connector.Host) string {
+	if kubeConf.Cluster.Etcd.IsKubeadm() {
+		return fmt.Sprintf("%s/kubectl -n kube-system exec etcd-%s -- etcdctl "+
+			"--endpoints=https://127.0.0.1:2379 "+
+			"--cacert=/etc/kubernetes/pki/etcd/ca.crt "+
+			"--cert=/etc/kubernetes/pki/etcd/healthcheck-client.crt "+
+			"--key=/etc/kubernetes/pki/etcd/healthcheck-client.key",
+			common.BinDir, host.GetName())
+	}
+	return etcdctlV3(host, fmt.Sprintf("https://%s:2379", util.URLHost(host.GetInternalAddress())))
+}
+
+type CheckQuorum struct {
+	common.KubeAction
+}
+
+func (c *CheckQuorum) Execute(runtime connector.Runtime) error {
+	var node connector.Host
+	for _, host := range runtime.GetAllHosts() {
+		if host.GetName() == c.KubeConf.Arg.NodeName {
+			node = host
+		}
+	}
+	if node == nil {
+		return errors.Errorf("node %s is not found in the config", c.KubeConf.Arg.NodeName)
+	}
+
+	etcdctl := MemberEtcdctl(c.KubeConf, runtime.RemoteHost())
+	output, err := runtime.GetRunner().SudoCmd(fmt.Sprintf("%s member list -w json", etcdctl), false)
+	if err != nil {
+		return errors.Wrap(errors.WithStack(err), "list etcd member failed")
+	}
+	memberList := &etcdMemberList{}
+	if err := json.Unmarshal([]byte(output), memberList); err != nil {
+		return errors.Wrap(errors.WithStack(err), "parse etcd member list failed")
+	}
+
+	peerURL := fmt.Sprintf("https://%s:2380", util.URLHost(node.GetInternalAddress()))
+	found := false
+	for _, member := range memberList.Members {
+		for _, url := range member.PeerURLs {
+			if url == peerURL {
+				// type: uint64
+				c.PipelineCache.Set(common.ETCDMemberID, member.ID)
+				found = true
+			}
+		}
+	}
+	if !found {
+		logger.Log.Warningf("node %s is not a member of the etcd cluster, skip removing the member", node.GetName())
+		return nil
+	}
+
+	remaining := len(memberList.Members) - 1
+	if remaining < 1 {
+		return errors.Errorf("node %s is the last member of the etcd cluster", node.GetName())
+	}
+
+	// The command exits with a non-zero code when any endpoint is unhealthy, the result is still printed.
+	output, err = runtime.GetRunner().SudoCmd(
+		fmt.Sprintf("%s endpoint health --cluster -w json 2>/dev/null || true", etcdctl), false)
+	if err != nil {
+		return errors.Wrap(errors.WithStack(err), "check etcd endpoint health failed")
+	}
+	var endpoints []etcdEndpointHealth
+	if err := json.Unmarshal([]byte(output), &endpoints); err != nil {
+		return errors.Wrap(errors.WithStack(err), "parse etcd endpoint health failed")
+	}
+
+	healthy := 0
+	for _, e := range endpoints {
+		if e.Health && !strings.Contains(e.Endpoint, fmt.Sprintf("//%s:", util.URLHost(node.GetInternalAddress()))) {
+			healthy++
+		}
+	}
+	if quorum := remaining/2 + 1; healthy < quorum {
+		return errors.Errorf("removing node %s will lose the etcd quorum: %d of the remaining %d members are healthy, %d are required",
+			node.GetName(), healthy, remaining, quorum)
+	}
+	return nil
+}
+
+type RemoveMember struct {
+	common.KubeAction
+}
+
+func (r *RemoveMember) Execute(runtime connector.Runtime) error {
+	id, ok := r.PipelineCache.Get(common.ETCDMemberID)
+	if !ok {
+		logger.Log.Infof("node %s is not a member of the etcd cluster, skip", r.KubeConf.Arg.NodeName)
+		return nil
+	}
+
+	if _, err := runtime.GetRunner().SudoCmd(
+		fmt.Sprintf("%s member remove %x", MemberEtcdctl(r.KubeConf, runtime.RemoteHost()), id.(uint64)), true); err != nil {
+		return errors.Wrap(errors.WithStack(err), "remove etcd member failed")
+	}
+	return nil
+}
+
+type ClusterHealthCheck struct {
+	common.KubeAction
+}
+
+func (c *ClusterHealthCheck) Execute(runtime connector.Runtime) error {
+	output, err := runtime.GetRunner().SudoCmd(
+		fmt.Sprintf("%s endpoint health --cluster -w json 2>/dev/null || true", MemberEtcdctl(c.KubeConf, runtime.RemoteHost())), false)
+	if err != nil {
+		return errors.Wrap(errors.WithStack(err), "check etcd endpoint health failed")
+	}
+	var endpoints []etcdEndpointHealth
+	if err := json.Unmarshal([]byte(output), &endpoints); err != nil {
+		return errors.Wrap(errors.WithStack(err), "parse etcd endpoint health failed")
+	}
+	if len(endpoints) == 0 {
+		return errors.New("no etcd endpoint is found")
+	}
+
+	var unhealthy []string
+	for _, e := range endpoints {
+		if !e.Health {
+			unhealthy = append(unhealthy, e.Endpoint)
+		}
+	}
+	if len(unhealthy) != 0 {
+		return errors.Errorf("etcd endpoints %s are unhealthy, fix them before changing the members", strings.Join(unhealthy, ", "))
+	}
+	return nil
+}
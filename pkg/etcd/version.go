@@ -0,0 +1,37 @@
+/*
+ Copyright 2021 The KubeSphere Authors.
+
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+     http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package etcd
+
+import (
+	kubekeyapiv1alpha2 "github.com/kubesphere/kubekey/apis/kubekey/v1alpha2"
+	versionutil "k8s.io/apimachinery/pkg/util/version"
+)
+
+// minETCDUpgradeK8sVersion is the first Kubernetes version whose kubeadm expects etcd v3.4, the clusters created
+// by the older KubeKey releases run etcd v3.3 until they are upgraded to it.
+const minETCDUpgradeK8sVersion = "v1.17.0"
+
+// RequiredVersion returns the etcd version which the clusters of the Kubernetes version are installed with,
+// the etcd deployed by KubeKey is upgraded to it along with Kubernetes. An empty string is returned if the
+// Kubernetes version does not require a newer etcd.
+func RequiredVersion(k8sVersion string) string {
+	v, err := versionutil.ParseSemantic(k8sVersion)
+	if err != nil || v.LessThan(versionutil.MustParseSemantic(minETCDUpgradeK8sVersion)) {
+		return ""
+	}
+	return kubekeyapiv1alpha2.DefaultEtcdVersion
+}
@@ -0,0 +1,37 @@
+/*
+ Copyright 2021 The KubeSphere Authors.
+
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+     http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package etcd
+
+import (
+	"testing"
+
+	kubekeyapiv1alpha2 "github.com/kubesphere/kubekey/apis/kubekey/v1alpha2"
+)
+
+func TestRequiredVersion(t *testing.T) {
+	tests := map[string]string{
+		"v1.16.15": "",
+		"v1.17.0":  kubekeyapiv1alpha2.DefaultEtcdVersion,
+		"v1.22.1":  kubekeyapiv1alpha2.DefaultEtcdVersion,
+		"invalid":  "",
+	}
+	for k8sVersion, want := range tests {
+		if got := RequiredVersion(k8sVersion); got != want {
+			t.Errorf("RequiredVersion(%s) = %q, want %q", k8sVersion, got, want)
+		}
+	}
+}
@@ -0,0 +1,266 @@
+/*
+ Copyright 2021 The KubeSphere Authors.
+
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+     http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package files
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/base64"
+	"fmt"
+	"github.com/kubesphere/kubekey/pkg/core/logger"
+	"github.com/pkg/errors"
+	"gopkg.in/yaml.v2"
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"path"
+	"path/filepath"
+	"regexp"
+	"strings"
+)
+
+const (
+	// k8sSigningIdentity and k8sSigningIssuer identify the keyless signatures published with the kubernetes release binaries.
+	k8sSigningIdentity = "krel-trust@k8s-releng-prod.iam.gserviceaccount.com"
+	k8sSigningIssuer   = "https://accounts.google.com"
+)
+
+var sha256Pattern = regexp.MustCompile(`^[a-fA-F0-9]{64}$`)
+
+// ChecksumSource provides the expected SHA256 of a binary.
+type ChecksumSource interface {
+	// Sha256 returns the expected checksum of the binary, or an empty string if the source does not know it.
+	Sha256(b *KubeBinary) (string, error)
+	// Verify checks the signature of the downloaded binary if the source can do it.
+	Verify(b *KubeBinary) error
+	String() string
+}
+
+// DefaultChecksumSources returns the built-in table followed by the upstream checksum files.
+func DefaultChecksumSources() []ChecksumSource {
+	return []ChecksumSource{BuiltinChecksums{}, NewUpstreamChecksums()}
+}
+
+// NewChecksumSources returns the checksum sources in the order they are consulted:
+// the user-supplied checksums file, the built-in table and, if enabled, the upstream checksum files.
+func NewChecksumSources(file string, upstream bool) ([]ChecksumSource, error) {
+	var sources []ChecksumSource
+	if file != "" {
+		f, err := NewFileChecksums(file)
+		if err != nil {
+			return nil, err
+		}
+		sources = append(sources, f)
+	}
+	sources = append(sources, BuiltinChecksums{})
+	if upstream {
+		sources = append(sources, NewUpstreamChecksums())
+	}
+	return sources, nil
+}
+
+// BuiltinChecksums looks up the checksums compiled into kk.
+type BuiltinChecksums struct{}
+
+func (BuiltinChecksums) Sha256(b *KubeBinary) (string, error) {
+	return FileSha256[b.ID][b.Arch][b.Version], nil
+}
+
+func (BuiltinChecksums) Verify(_ *KubeBinary) error {
+	return nil
+}
+
+func (BuiltinChecksums) String() string {
+	return "built-in checksums"
+}
+
+// FileChecksums looks up the checksums in a user-supplied file, which uses the same layout as the built-in table:
+//
+//	kubeadm:
+//	  amd64:
+//	    v1.23.1: <sha256>
+type FileChecksums struct {
+	Path      string
+	checksums map[string]map[string]map[string]string
+}
+
+func NewFileChecksums(path string) (*FileChecksums, error) {
+	content, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, errors.Wrapf(errors.WithStack(err), "read checksums file %s failed", path)
+	}
+	// yaml.v2 keeps the checksums which look like numbers as they are written
+	checksums := make(map[string]map[string]map[string]string)
+	if err := yaml.Unmarshal(content, &checksums); err != nil {
+		return nil, errors.Wrapf(errors.WithStack(err), "parse checksums file %s failed", path)
+	}
+	return &FileChecksums{Path: path, checksums: checksums}, nil
+}
+
+func (f *FileChecksums) Sha256(b *KubeBinary) (string, error) {
+	return strings.TrimSpace(f.checksums[b.ID][b.Arch][b.Version]), nil
+}
+
+func (f *FileChecksums) Verify(_ *KubeBinary) error {
+	return nil
+}
+
+func (f *FileChecksums) String() string {
+	return fmt.Sprintf("checksums file %s", f.Path)
+}
+
+// UpstreamChecksums fetches the checksum files published with the upstream releases.
+type UpstreamChecksums struct {
+	checksums map[string]string
+}
+
+func NewUpstreamChecksums() *UpstreamChecksums {
+	return &UpstreamChecksums{checksums: make(map[string]string)}
+}
+
+func (u *UpstreamChecksums) Sha256(b *KubeBinary) (string, error) {
+	url, name := upstreamChecksumUrl(b)
+	if url == "" {
+		return "", nil
+	}
+	key := url + "#" + name
+	if sum, ok := u.checksums[key]; ok {
+		return sum, nil
+	}
+
+	content, err := b.fetch(url, path.Base(url))
+	if err != nil {
+		// the release may not publish a checksum file, or we are offline
+		logger.Log.Debugf("fetch %s failed: %v", url, err)
+		return "", nil
+	}
+	sum := parseChecksumFile(content, name)
+	u.checksums[key] = sum
+	return sum, nil
+}
+
+// Verify checks the keyless signature of the kubernetes release binaries with cosign.
+// It is skipped if cosign is not installed or the release is not signed.
+func (u *UpstreamChecksums) Verify(b *KubeBinary) error {
+	if b.Type != KUBE || b.ID == k3s {
+		return nil
+	}
+	cosign, err := exec.LookPath("cosign")
+	if err != nil {
+		logger.Log.Warningf("cosign is not installed, skip verifying the signature of %s %s", b.ID, b.Version)
+		return nil
+	}
+
+	base := fmt.Sprintf("https://dl.k8s.io/release/%s/bin/linux/%s/%s", b.Version, b.Arch, b.ID)
+	sig, err := b.fetch(base+".sig", b.FileName+".sig")
+	if err != nil {
+		logger.Log.Debugf("%s %s is not signed: %v", b.ID, b.Version, err)
+		return nil
+	}
+	cert, err := b.fetch(base+".cert", b.FileName+".cert")
+	if err != nil {
+		logger.Log.Debugf("%s %s has no signing certificate: %v", b.ID, b.Version, err)
+		return nil
+	}
+	// the download command may save the error page of a missing file, the releases before v1.26 are not signed.
+	if !isBase64(sig) || !isBase64(cert) {
+		logger.Log.Debugf("%s %s is not signed", b.ID, b.Version)
+		return nil
+	}
+
+	sigPath := filepath.Join(b.BaseDir, b.FileName+".sig")
+	certPath := filepath.Join(b.BaseDir, b.FileName+".cert")
+	if err := ioutil.WriteFile(sigPath, sig, 0644); err != nil {
+		return errors.Wrapf(errors.WithStack(err), "write %s failed", sigPath)
+	}
+	defer os.Remove(sigPath)
+	if err := ioutil.WriteFile(certPath, cert, 0644); err != nil {
+		return errors.Wrapf(errors.WithStack(err), "write %s failed", certPath)
+	}
+	defer os.Remove(certPath)
+
+	cmd := exec.Command(cosign, "verify-blob", b.Path(),
+		"--signature", sigPath,
+		"--certificate", certPath,
+		"--certificate-identity", k8sSigningIdentity,
+		"--certificate-oidc-issuer", k8sSigningIssuer)
+	if output, err := cmd.CombinedOutput(); err != nil {
+		return errors.Wrapf(errors.WithStack(err), "verify signature of %s failed: %s", b.Path(), strings.TrimSpace(string(output)))
+	}
+	return nil
+}
+
+func (u *UpstreamChecksums) String() string {
+	return "upstream checksums"
+}
+
+// upstreamChecksumUrl returns where the upstream release publishes the checksum of the binary,
+// and the file name the checksum is listed under. The binaries without a published SHA256 return an empty url.
+func upstreamChecksumUrl(b *KubeBinary) (string, string) {
+	switch b.ID {
+	case kubeadm, kubelet, kubectl:
+		return fmt.Sprintf("https://dl.k8s.io/release/%s/bin/linux/%s/%s.sha256", b.Version, b.Arch, b.ID), b.FileName
+	case etcd:
+		return fmt.Sprintf("https://github.com/etcd-io/etcd/releases/download/%s/SHA256SUMS", b.Version), b.FileName
+	case kubecni:
+		return fmt.Sprintf("https://github.com/containernetworking/plugins/releases/download/%s/%s.sha256", b.Version, b.FileName), b.FileName
+	case crictl:
+		return fmt.Sprintf("https://github.com/kubernetes-sigs/cri-tools/releases/download/%s/%s.sha256", b.Version, b.FileName), b.FileName
+	case crio:
+		return fmt.Sprintf("https://storage.googleapis.com/cri-o/artifacts/%s.sha256sum", b.FileName), b.FileName
+	case k3s:
+		name := k3s
+		if b.Arch == arm64 {
+			name = fmt.Sprintf("k3s-%s", b.Arch)
+		}
+		return fmt.Sprintf("https://github.com/k3s-io/k3s/releases/download/%s+k3s1/sha256sum-%s.txt", b.Version, b.Arch), name
+	case compose:
+		return fmt.Sprintf("https://github.com/docker/compose/releases/download/%s/%s.sha256", b.Version, b.FileName), b.FileName
+	default:
+		// helm is extracted from the tarball and the others do not publish a SHA256 file.
+		return "", ""
+	}
+}
+
+func isBase64(content []byte) bool {
+	content = bytes.TrimSpace(content)
+	if len(content) == 0 {
+		return false
+	}
+	_, err := base64.StdEncoding.DecodeString(string(content))
+	return err == nil
+}
+
+// parseChecksumFile finds the checksum of fileName in either a bare checksum file or a sha256sum style listing.
+func parseChecksumFile(content []byte, fileName string) string {
+	scanner := bufio.NewScanner(bytes.NewReader(content))
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		switch len(fields) {
+		case 1:
+			if sha256Pattern.MatchString(fields[0]) {
+				return strings.ToLower(fields[0])
+			}
+		case 2:
+			name := strings.TrimPrefix(fields[1], "*")
+			if sha256Pattern.MatchString(fields[0]) && path.Base(name) == fileName {
+				return strings.ToLower(fields[0])
+			}
+		}
+	}
+	return ""
+}
@@ -0,0 +1,89 @@
+/*
+ Copyright 2021 The KubeSphere Authors.
+
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+     http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package files
+
+import (
+	"io/ioutil"
+	"path/filepath"
+	"testing"
+)
+
+func TestParseChecksumFile(t *testing.T) {
+	sum := "2ac029e47bab752dacdb7b30032f230f49e2f457cbc32e8f555c2210bb5ff107"
+	tests := []struct {
+		name     string
+		content  string
+		fileName string
+		want     string
+	}{
+		{"bare", sum + "\n", "kubeadm", sum},
+		{"listing", "0000000000000000000000000000000000000000000000000000000000000000  etcd-v3.4.13-linux-arm64.tar.gz\n" +
+			sum + "  etcd-v3.4.13-linux-amd64.tar.gz\n", "etcd-v3.4.13-linux-amd64.tar.gz", sum},
+		{"binary mode", sum + " *docker-compose-linux-x86_64", "docker-compose-linux-x86_64", sum},
+		{"not listed", sum + "  k3s-arm64", "k3s", ""},
+		{"error page", "<?xml version='1.0'?><Error>NoSuchKey</Error>", "kubelet", ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := parseChecksumFile([]byte(tt.content), tt.fileName); got != tt.want {
+				t.Errorf("parseChecksumFile() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestChecksumSources(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "checksums.yaml")
+	content := `kubeadm:
+  amd64:
+    v1.23.1: 4a9c7a41b2e4c9a2fc1a6e9c5b0e2d6ac3a1bd1ee0b5d5f4e1d6e55bfa9c5a0b
+    v1.23.0: 1111111111111111111111111111111111111111111111111111111111111111
+`
+	if err := ioutil.WriteFile(file, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+	sources, err := NewChecksumSources(file, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	b := NewKubeBinary("kubeadm", "amd64", "v1.23.1", t.TempDir(), nil)
+	b.ChecksumSources = sources
+	if got := b.GetSha256(); got != "4a9c7a41b2e4c9a2fc1a6e9c5b0e2d6ac3a1bd1ee0b5d5f4e1d6e55bfa9c5a0b" {
+		t.Errorf("expected the checksum from the file, got %q", got)
+	}
+
+	// the checksums file takes precedence over the built-in table
+	b.Version = "v1.23.0"
+	if got := b.GetSha256(); got != "1111111111111111111111111111111111111111111111111111111111111111" {
+		t.Errorf("expected the checksum from the file, got %q", got)
+	}
+
+	b.Version = "v1.22.1"
+	if got := b.GetSha256(); got != FileSha256[kubeadm][amd64]["v1.22.1"] {
+		t.Errorf("expected the built-in checksum, got %q", got)
+	}
+
+	b.Version = "v1.99.0"
+	if got := b.GetSha256(); got != "" {
+		t.Errorf("expected no checksum, got %q", got)
+	}
+
+	if _, err := NewChecksumSources(filepath.Join(t.TempDir(), "missing.yaml"), false); err == nil {
+		t.Error("expected an error for a missing checksums file")
+	}
+}
@@ -45,12 +45,14 @@ const (
 	registry = "registry"
 	harbor   = "harbor"
 	compose  = "compose"
+	crio     = "crio"
 )
 
 // KubeBinary Type field const
 const (
 	CNI      = "cni"
 	CRICTL   = "crictl"
+	CRIO     = "crio"
 	DOCKER   = "docker"
 	ETCD     = "etcd"
 	HELM     = "helm"
@@ -67,7 +69,9 @@ type KubeBinary struct {
 	Url      string
 	BaseDir  string
 	Zone     string
-	getCmd   func(path, url string) string
+	// ChecksumSources are consulted in order for the expected SHA256 of the binary.
+	ChecksumSources []ChecksumSource
+	getCmd          func(path, url string) string
 }
 
 func NewKubeBinary(name, arch, version, prePath string, getCmd func(path, url string) string) *KubeBinary {
@@ -77,6 +81,7 @@ func NewKubeBinary(name, arch, version, prePath string, getCmd func(path, url st
 	component.Version = version
 	component.Zone = os.Getenv("KKZONE")
 	component.getCmd = getCmd
+	component.ChecksumSources = DefaultChecksumSources()
 
 	switch name {
 	case etcd:
@@ -137,6 +142,13 @@ func NewKubeBinary(name, arch, version, prePath string, getCmd func(path, url st
 		if component.Zone == "cn" {
 			component.Url = fmt.Sprintf("https://kubernetes-release.pek3b.qingstor.com/cri-tools/releases/download/%s/crictl-%s-linux-%s.tar.gz", version, version, arch)
 		}
+	case crio:
+		component.Type = CRIO
+		component.FileName = fmt.Sprintf("cri-o.%s.%s.tar.gz", arch, version)
+		component.Url = fmt.Sprintf("https://storage.googleapis.com/cri-o/artifacts/cri-o.%s.%s.tar.gz", arch, version)
+		if component.Zone == "cn" {
+			component.Url = fmt.Sprintf("https://kubernetes-release.pek3b.qingstor.com/cri-o/artifacts/cri-o.%s.%s.tar.gz", arch, version)
+		}
 	case k3s:
 		component.Type = KUBE
 		component.FileName = k3s
@@ -205,10 +217,46 @@ func (b *KubeBinary) GetCmd() string {
 }
 
 func (b *KubeBinary) GetSha256() string {
-	s := FileSha256[b.ID][b.Arch][b.Version]
+	s, _, _ := b.lookupSha256()
 	return s
 }
 
+// ExpectedSha256 returns the first checksum found in the checksum sources, or an empty string if none of them
+// knows the binary.
+func (b *KubeBinary) ExpectedSha256() (string, error) {
+	s, _, err := b.lookupSha256()
+	return s, err
+}
+
+// lookupSha256 returns the first checksum found in the checksum sources and the source it came from.
+func (b *KubeBinary) lookupSha256() (string, ChecksumSource, error) {
+	for _, source := range b.ChecksumSources {
+		s, err := source.Sha256(b)
+		if err != nil {
+			return "", nil, errors.Wrapf(err, "get SHA256 of %s %s from %s failed", b.ID, b.Version, source)
+		}
+		if s = strings.TrimSpace(s); s != "" {
+			return s, source, nil
+		}
+	}
+	return "", nil, nil
+}
+
+// fetch downloads a small release file, such as a checksum file, with the download command and returns its content.
+func (b *KubeBinary) fetch(url, name string) ([]byte, error) {
+	dir, err := ioutil.TempDir("", "kubekey-")
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+	defer os.RemoveAll(dir)
+
+	p := filepath.Join(dir, name)
+	if output, err := exec.Command("/bin/sh", "-c", b.getCmd(p, url)).CombinedOutput(); err != nil {
+		return nil, errors.Wrapf(errors.WithStack(err), "download %s failed: %s", url, strings.TrimSpace(string(output)))
+	}
+	return ioutil.ReadFile(p)
+}
+
 func (b *KubeBinary) Download() error {
 	for i := 5; i > 0; i-- {
 		cmd := exec.Command("/bin/sh", "-c", b.GetCmd())
@@ -259,13 +307,17 @@ func (b *KubeBinary) SHA256Check() error {
 		return errors.Wrap(err, fmt.Sprintf("Failed to check SHA256 of %s", b.Path()))
 	}
 
-	if strings.TrimSpace(b.GetSha256()) == "" {
-		return errors.New(fmt.Sprintf("No SHA256 found for %s. %s is not supported.", b.ID, b.Version))
+	sum, source, err := b.lookupSha256()
+	if err != nil {
+		return err
 	}
-	if output != b.GetSha256() {
-		return errors.New(fmt.Sprintf("SHA256 no match. %s not equal %s", b.GetSha256(), output))
+	if sum == "" {
+		return errors.New(fmt.Sprintf("No SHA256 found for %s %s %s. Add it to the checksums file of the cluster config to verify it.", b.ID, b.Arch, b.Version))
 	}
-	return nil
+	if output != strings.ToLower(sum) {
+		return errors.New(fmt.Sprintf("SHA256 no match. %s from %s not equal %s", sum, source, output))
+	}
+	return source.Verify(b)
 }
 
 func sha256sum(path string) (string, error) {
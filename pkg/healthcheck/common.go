@@ -0,0 +1,44 @@
+/*
+ Copyright 2021 The KubeSphere Authors.
+
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+     http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package healthcheck
+
+import "time"
+
+const (
+	ReportKey = "clusterCheckReport"
+
+	// ClusterNode is the node name of the results which belong to the whole cluster.
+	ClusterNode = "cluster"
+
+	CheckSSH                   = "ssh"
+	CheckNodeStatus            = "node-status"
+	CheckETCDHealth            = "etcd/health"
+	CheckETCDDBSize            = "etcd/db-size"
+	CheckETCDLeader            = "etcd/leader"
+	CheckCerts                 = "certificates"
+	CheckAPIServerReachability = "apiserver"
+	CheckTimeSkewName          = "time-skew"
+	CheckDisk                  = "disk"
+
+	// ETCDQuotaBackendBytes is the default backend quota of etcd, an alarm is raised when the db size exceeds it.
+	ETCDQuotaBackendBytes = 2 * 1024 * 1024 * 1024
+	DiskWarningPercent    = 80
+	DiskErrorPercent      = 90
+	CertExpirationWarning = 30 * 24 * time.Hour
+	TimeSkewWarning       = time.Second
+	TimeSkewError         = 5 * time.Second
+)
@@ -0,0 +1,212 @@
+/*
+ Copyright 2021 The KubeSphere Authors.
+
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+     http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package healthcheck
+
+import (
+	"github.com/kubesphere/kubekey/pkg/bootstrap/precheck"
+	"github.com/kubesphere/kubekey/pkg/certs"
+	"github.com/kubesphere/kubekey/pkg/common"
+	"github.com/kubesphere/kubekey/pkg/core/connector"
+	"github.com/kubesphere/kubekey/pkg/core/task"
+	"io"
+)
+
+type ConnectivityModule struct {
+	common.KubeModule
+}
+
+func (c *ConnectivityModule) Init() {
+	c.Name = "ConnectivityModule"
+	c.Desc = "Check the connectivity of the nodes"
+
+	c.PipelineCache.Set(ReportKey, NewReport())
+
+	// The error is ignored, so the unreachable nodes are reported instead of stopping the whole check.
+	connection := &task.RemoteTask{
+		Name:        "CheckConnection",
+		Desc:        "Check the ssh connection of the nodes",
+		Hosts:       c.Runtime.GetAllHosts(),
+		Action:      new(CheckConnection),
+		Parallel:    true,
+		Retry:       1,
+		IgnoreError: true,
+	}
+
+	unreachable := &task.LocalTask{
+		Name:   "ReportUnreachableNodes",
+		Desc:   "Report the unreachable nodes",
+		Action: new(ReportUnreachable),
+	}
+
+	c.Tasks = []task.Interface{
+		connection,
+		unreachable,
+	}
+}
+
+type ClusterCheckModule struct {
+	common.KubeModule
+}
+
+func (c *ClusterCheckModule) Init() {
+	c.Name = "ClusterCheckModule"
+	c.Desc = "Check the health of the cluster"
+
+	report, err := getReport(c.PipelineCache)
+	if err != nil {
+		c.Tasks = []task.Interface{
+			&task.LocalTask{
+				Name:   "GetReport",
+				Desc:   "Get the cluster check report",
+				Action: &ReportNotFound{Err: err},
+			},
+		}
+		return
+	}
+	reachable := func(hosts []connector.Host) []connector.Host {
+		res := make([]connector.Host, 0, len(hosts))
+		for _, host := range hosts {
+			if report.IsReachable(host.GetName()) {
+				res = append(res, host)
+			}
+		}
+		return res
+	}
+	allHosts := reachable(c.Runtime.GetAllHosts())
+	k8sHosts := reachable(c.Runtime.GetHostsByRole(common.K8s))
+	masters := reachable(c.Runtime.GetHostsByRole(common.Master))
+
+	if len(allHosts) != 0 {
+		c.Tasks = append(c.Tasks,
+			&task.RemoteTask{
+				Name:     "CheckServices",
+				Desc:     "Check the state of the kubelet, container runtime and etcd services",
+				Hosts:    allHosts,
+				Action:   new(CheckServices),
+				Parallel: true,
+			},
+			&task.RemoteTask{
+				Name:     "CheckTimeSkew",
+				Desc:     "Check the time skew of the nodes",
+				Hosts:    allHosts,
+				Action:   new(CheckTimeSkew),
+				Parallel: true,
+			},
+		)
+	}
+
+	if len(k8sHosts) != 0 {
+		c.Tasks = append(c.Tasks, &task.RemoteTask{
+			Name:     "CheckAPIServer",
+			Desc:     "Check the apiserver is reachable through the control plane endpoint",
+			Hosts:    k8sHosts,
+			Action:   new(CheckAPIServer),
+			Parallel: true,
+		})
+	}
+
+	if len(masters) == 0 {
+		report.Add(ClusterNode, CheckNodeStatus, StatusError, "none of the control plane nodes is reachable")
+	} else {
+		c.Tasks = append(c.Tasks,
+			&task.RemoteTask{
+				Name:        "GetKubernetesNodesStatus",
+				Desc:        "Get kubernetes nodes status",
+				Hosts:       masters[:1],
+				Action:      new(precheck.GetKubernetesNodesStatus),
+				Retry:       1,
+				IgnoreError: true,
+			},
+			&task.RemoteTask{
+				Name:   "CheckNodesStatus",
+				Desc:   "Check the status of the kubernetes nodes",
+				Hosts:  masters[:1],
+				Action: new(CheckNodesStatus),
+			},
+			&task.RemoteTask{
+				Name:   "CheckDiskPressure",
+				Desc:   "Check the disk pressure condition of the kubernetes nodes",
+				Hosts:  masters[:1],
+				Action: new(CheckDiskPressure),
+			},
+		)
+	}
+
+	// The certificates of k3s are not managed by kubeadm, they are not checked.
+	if len(masters) != 0 && c.KubeConf.Cluster.Kubernetes.Type != common.K3s {
+		c.Tasks = append(c.Tasks,
+			&task.RemoteTask{
+				Name:        "ListClusterCerts",
+				Desc:        "List the cluster certs",
+				Hosts:       masters,
+				Action:      new(certs.ListClusterCerts),
+				Parallel:    true,
+				Retry:       1,
+				IgnoreError: true,
+			},
+			&task.RemoteTask{
+				Name:     "CheckCertsExpiration",
+				Desc:     "Check the expiration of the cluster certs",
+				Hosts:    masters,
+				Action:   new(CheckCertsExpiration),
+				Parallel: true,
+			},
+		)
+	}
+
+	// The external etcd is not managed by kubekey, it is not checked.
+	var etcdHosts []connector.Host
+	switch {
+	case c.KubeConf.Cluster.Etcd.IsKubeKey():
+		etcdHosts = reachable(c.Runtime.GetHostsByRole(common.ETCD))
+	case c.KubeConf.Cluster.Etcd.IsKubeadm():
+		etcdHosts = masters
+	}
+	if c.KubeConf.Cluster.Etcd.IsKubeKey() || c.KubeConf.Cluster.Etcd.IsKubeadm() {
+		if len(etcdHosts) == 0 {
+			report.Add(ClusterNode, CheckETCDHealth, StatusError, "none of the etcd nodes is reachable")
+		} else {
+			c.Tasks = append(c.Tasks, &task.RemoteTask{
+				Name:   "CheckETCD",
+				Desc:   "Check the health and db size of the etcd members",
+				Hosts:  etcdHosts[:1],
+				Action: new(CheckETCD),
+			})
+		}
+	}
+}
+
+type ReportModule struct {
+	common.KubeModule
+	Output string
+	Writer io.Writer
+}
+
+func (r *ReportModule) Init() {
+	r.Name = "ReportModule"
+	r.Desc = "Print the cluster check report"
+
+	printReport := &task.LocalTask{
+		Name:   "PrintReport",
+		Desc:   "Print the cluster check report",
+		Action: &PrintReport{Output: r.Output, Writer: r.Writer},
+	}
+
+	r.Tasks = []task.Interface{
+		printReport,
+	}
+}
@@ -0,0 +1,134 @@
+/*
+ Copyright 2021 The KubeSphere Authors.
+
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+     http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package healthcheck
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+	"sync"
+	"text/tabwriter"
+)
+
+const (
+	StatusOK      = "ok"
+	StatusWarning = "warning"
+	StatusError   = "error"
+)
+
+// Result is the result of a check on a node, the node is "cluster" for the checks of the whole cluster.
+type Result struct {
+	Node    string `json:"node"`
+	Check   string `json:"check"`
+	Status  string `json:"status"`
+	Message string `json:"message"`
+}
+
+type Summary struct {
+	OK      int `json:"ok"`
+	Warning int `json:"warning"`
+	Error   int `json:"error"`
+}
+
+// Report collects the results of the checks, it is shared by the tasks which run on the hosts in parallel.
+type Report struct {
+	mu        sync.Mutex
+	Results   []Result `json:"results"`
+	Summary   Summary  `json:"summary"`
+	reachable map[string]struct{}
+}
+
+func NewReport() *Report {
+	return &Report{
+		Results:   make([]Result, 0),
+		reachable: make(map[string]struct{}),
+	}
+}
+
+func (r *Report) Add(node, check, status, format string, args ...interface{}) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.Results = append(r.Results, Result{Node: node, Check: check, Status: status, Message: fmt.Sprintf(format, args...)})
+	switch status {
+	case StatusOK:
+		r.Summary.OK++
+	case StatusWarning:
+		r.Summary.Warning++
+	default:
+		r.Summary.Error++
+	}
+}
+
+func (r *Report) SetReachable(node string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.reachable[node] = struct{}{}
+}
+
+func (r *Report) IsReachable(node string) bool {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	_, ok := r.reachable[node]
+	return ok
+}
+
+func (r *Report) Failed() bool {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.Summary.Error > 0
+}
+
+func (r *Report) sort() {
+	sort.SliceStable(r.Results, func(i, j int) bool {
+		if r.Results[i].Node != r.Results[j].Node {
+			return r.Results[i].Node < r.Results[j].Node
+		}
+		return r.Results[i].Check < r.Results[j].Check
+	})
+}
+
+// PrintText prints the results as a table which is sorted by the node and the check name.
+func (r *Report) PrintText(w io.Writer) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.sort()
+
+	tw := tabwriter.NewWriter(w, 10, 4, 3, ' ', 0)
+	_, _ = fmt.Fprintln(tw, "NODE\tCHECK\tSTATUS\tMESSAGE")
+	for _, res := range r.Results {
+		_, _ = fmt.Fprintf(tw, "%s\t%s\t%s\t%s\n", res.Node, res.Check, res.Status, res.Message)
+	}
+	if err := tw.Flush(); err != nil {
+		return err
+	}
+	_, err := fmt.Fprintf(w, "\n%d ok, %d warning, %d error\n", r.Summary.OK, r.Summary.Warning, r.Summary.Error)
+	return err
+}
+
+func (r *Report) PrintJSON(w io.Writer) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.sort()
+
+	b, err := json.MarshalIndent(r, "", "  ")
+	if err != nil {
+		return err
+	}
+	_, err = fmt.Fprintln(w, string(b))
+	return err
+}
@@ -0,0 +1,60 @@
+/*
+ Copyright 2021 The KubeSphere Authors.
+
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+     http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package healthcheck
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+func TestReport(t *testing.T) {
+	r := NewReport()
+	r.Add("node2", CheckDisk, StatusWarning, "%d%% of %s is used", 85, "/")
+	r.Add("node1", CheckSSH, StatusOK, "reachable")
+	if r.Failed() {
+		t.Fatalf("the report without errors should not fail")
+	}
+	r.Add(ClusterNode, CheckETCDHealth, StatusError, "%s is unhealthy", "https://192.168.0.2:2379")
+	if !r.Failed() {
+		t.Fatalf("the report with errors should fail")
+	}
+
+	buf := &bytes.Buffer{}
+	if err := r.PrintJSON(buf); err != nil {
+		t.Fatal(err)
+	}
+	var out Report
+	if err := json.Unmarshal(buf.Bytes(), &out); err != nil {
+		t.Fatal(err)
+	}
+	if out.Summary != (Summary{OK: 1, Warning: 1, Error: 1}) {
+		t.Fatalf("unexpected summary %+v", out.Summary)
+	}
+	if out.Results[0].Node != ClusterNode || out.Results[2].Message != "85% of / is used" {
+		t.Fatalf("unexpected results %+v", out.Results)
+	}
+
+	buf.Reset()
+	if err := r.PrintText(buf); err != nil {
+		t.Fatal(err)
+	}
+	if !strings.HasSuffix(buf.String(), "1 ok, 1 warning, 1 error\n") {
+		t.Fatalf("unexpected text report:\n%s", buf.String())
+	}
+}
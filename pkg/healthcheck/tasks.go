@@ -0,0 +1,468 @@
+/*
+ Copyright 2021 The KubeSphere Authors.
+
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+     http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package healthcheck
+
+import (
+	"encoding/json"
+	"fmt"
+	"github.com/kubesphere/kubekey/pkg/certs"
+	"github.com/kubesphere/kubekey/pkg/common"
+	"github.com/kubesphere/kubekey/pkg/container"
+	"github.com/kubesphere/kubekey/pkg/core/cache"
+	"github.com/kubesphere/kubekey/pkg/core/connector"
+	"github.com/kubesphere/kubekey/pkg/etcd"
+	"github.com/pkg/errors"
+	"io"
+	corev1 "k8s.io/api/core/v1"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+)
+
+func getReport(pipelineCache *cache.Cache) (*Report, error) {
+	v, ok := pipelineCache.Get(ReportKey)
+	if !ok {
+		return nil, errors.New("get the cluster check report by pipeline cache failed")
+	}
+	return v.(*Report), nil
+}
+
+type CheckConnection struct {
+	common.KubeAction
+}
+
+func (c *CheckConnection) Execute(runtime connector.Runtime) error {
+	report, err := getReport(c.PipelineCache)
+	if err != nil {
+		return err
+	}
+	host := runtime.RemoteHost()
+	if _, err := runtime.GetRunner().SudoCmd("true", false); err != nil {
+		return errors.Wrap(errors.WithStack(err), "exec command by sudo failed")
+	}
+	report.SetReachable(host.GetName())
+	report.Add(host.GetName(), CheckSSH, StatusOK, "reachable")
+	return nil
+}
+
+type ReportUnreachable struct {
+	common.KubeAction
+}
+
+func (r *ReportUnreachable) Execute(runtime connector.Runtime) error {
+	report, err := getReport(r.PipelineCache)
+	if err != nil {
+		return err
+	}
+	for _, host := range runtime.GetAllHosts() {
+		if !report.IsReachable(host.GetName()) {
+			report.Add(host.GetName(), CheckSSH, StatusError, "failed to connect to %s, the other checks on this node are skipped", host.GetAddress())
+		}
+	}
+	return nil
+}
+
+type CheckServices struct {
+	common.KubeAction
+}
+
+func (c *CheckServices) Execute(runtime connector.Runtime) error {
+	report, err := getReport(c.PipelineCache)
+	if err != nil {
+		return err
+	}
+	host := runtime.RemoteHost()
+
+	services := NodeServices(c.KubeConf, host)
+	for _, service := range services {
+		state, err := runtime.GetRunner().SudoCmd(fmt.Sprintf("systemctl is-active %s || true", service), false)
+		if err != nil {
+			return errors.Wrap(errors.WithStack(err), fmt.Sprintf("get the state of %s failed", service))
+		}
+		state = strings.TrimSpace(state)
+		if state == "active" {
+			report.Add(host.GetName(), "service/"+service, StatusOK, "active")
+		} else {
+			report.Add(host.GetName(), "service/"+service, StatusError, "%s is %s", service, state)
+		}
+	}
+	return nil
+}
+
+// NodeServices returns the systemd services which are managed by kubekey on the host.
+func NodeServices(kubeConf *common.KubeConf, host connector.Host) []string {
+	services := make([]string, 0)
+	if host.IsRole(common.K8s) {
+		if kubeConf.Cluster.Kubernetes.Type == common.K3s {
+			services = append(services, "k3s")
+		} else {
+			services = append(services, "kubelet", container.ServiceName(kubeConf.Cluster.Kubernetes.ContainerManager))
+		}
+	}
+	if host.IsRole(common.ETCD) && kubeConf.Cluster.Etcd.IsKubeKey() {
+		services = append(services, "etcd")
+	}
+	return services
+}
+
+type CheckNodesStatus struct {
+	common.KubeAction
+}
+
+func (c *CheckNodesStatus) Execute(runtime connector.Runtime) error {
+	report, err := getReport(c.PipelineCache)
+	if err != nil {
+		return err
+	}
+	v, ok := c.PipelineCache.GetMustString(common.ClusterNodeStatus)
+	if !ok {
+		report.Add(ClusterNode, CheckNodeStatus, StatusError, "failed to get the nodes by kubectl on %s", runtime.RemoteHost().GetName())
+		return nil
+	}
+
+	// NAME    STATUS   ROLES                  AGE   VERSION
+	// node1   Ready    control-plane,master   10d   v1.21.5
+	nodes := make(map[string]string)
+	for i, line := range strings.Split(strings.TrimSpace(v), "\n") {
+		fields := strings.Fields(line)
+		if i == 0 || len(fields) < 2 {
+			continue
+		}
+		nodes[fields[0]] = fields[1]
+	}
+
+	for _, host := range runtime.GetHostsByRole(common.K8s) {
+		status, ok := nodes[host.GetName()]
+		switch {
+		case !ok:
+			report.Add(host.GetName(), CheckNodeStatus, StatusError, "the node is not found in the cluster")
+		case strings.Split(status, ",")[0] != "Ready":
+			report.Add(host.GetName(), CheckNodeStatus, StatusError, "the node is %s", status)
+		default:
+			report.Add(host.GetName(), CheckNodeStatus, StatusOK, "%s", status)
+		}
+	}
+	return nil
+}
+
+type etcdEndpointStatus struct {
+	Endpoint string `json:"Endpoint"`
+	Status   struct {
+		Header struct {
+			MemberID uint64 `json:"member_id"`
+		} `json:"header"`
+		Version string   `json:"version"`
+		DbSize  int64    `json:"dbSize"`
+		Leader  uint64   `json:"leader"`
+		Errors  []string `json:"errors"`
+	} `json:"Status"`
+}
+
+type etcdEndpointHealth struct {
+	Endpoint string `json:"endpoint"`
+	Health   bool   `json:"health"`
+	Error    string `json:"error"`
+}
+
+type CheckETCD struct {
+	common.KubeAction
+}
+
+func (c *CheckETCD) Execute(runtime connector.Runtime) error {
+	report, err := getReport(c.PipelineCache)
+	if err != nil {
+		return err
+	}
+	etcdctl := etcd.MemberEtcdctl(c.KubeConf, runtime.RemoteHost())
+
+	healthOutput, err := runtime.GetRunner().SudoCmd(fmt.Sprintf("%s endpoint health --cluster -w json 2>/dev/null || true", etcdctl), false)
+	if err != nil {
+		return errors.Wrap(errors.WithStack(err), "get the etcd endpoints health failed")
+	}
+	var healths []etcdEndpointHealth
+	if err := json.Unmarshal([]byte(healthOutput), &healths); err != nil || len(healths) == 0 {
+		report.Add(ClusterNode, CheckETCDHealth, StatusError, "failed to get the etcd endpoints health on %s", runtime.RemoteHost().GetName())
+	}
+	for _, h := range healths {
+		if h.Health {
+			report.Add(ClusterNode, CheckETCDHealth, StatusOK, "%s is healthy", h.Endpoint)
+		} else {
+			report.Add(ClusterNode, CheckETCDHealth, StatusError, "%s is unhealthy: %s", h.Endpoint, h.Error)
+		}
+	}
+
+	statusOutput, err := runtime.GetRunner().SudoCmd(fmt.Sprintf("%s endpoint status --cluster -w json 2>/dev/null || true", etcdctl), false)
+	if err != nil {
+		return errors.Wrap(errors.WithStack(err), "get the etcd endpoints status failed")
+	}
+	var statuses []etcdEndpointStatus
+	if err := json.Unmarshal([]byte(statusOutput), &statuses); err != nil || len(statuses) == 0 {
+		report.Add(ClusterNode, CheckETCDDBSize, StatusError, "failed to get the etcd endpoints status on %s", runtime.RemoteHost().GetName())
+		return nil
+	}
+
+	leaders := make(map[uint64]struct{})
+	for _, s := range statuses {
+		if s.Status.Leader != 0 {
+			leaders[s.Status.Leader] = struct{}{}
+		}
+		usage := float64(s.Status.DbSize) / ETCDQuotaBackendBytes * 100
+		status := StatusOK
+		switch {
+		case len(s.Status.Errors) > 0 || usage >= DiskErrorPercent:
+			status = StatusError
+		case usage >= DiskWarningPercent:
+			status = StatusWarning
+		}
+		msg := fmt.Sprintf("%s db size is %s (%.1f%% of the default quota %s)", s.Endpoint, humanSize(s.Status.DbSize), usage, humanSize(ETCDQuotaBackendBytes))
+		if len(s.Status.Errors) > 0 {
+			msg = fmt.Sprintf("%s, errors: %s", msg, strings.Join(s.Status.Errors, "; "))
+		}
+		report.Add(ClusterNode, CheckETCDDBSize, status, "%s", msg)
+	}
+	if len(leaders) == 1 {
+		report.Add(ClusterNode, CheckETCDLeader, StatusOK, "%d members agree on the leader", len(statuses))
+	} else {
+		report.Add(ClusterNode, CheckETCDLeader, StatusError, "%d leaders are found in %d members", len(leaders), len(statuses))
+	}
+	return nil
+}
+
+func humanSize(b int64) string {
+	const unit = 1024
+	if b < unit {
+		return fmt.Sprintf("%dB", b)
+	}
+	div, exp := int64(unit), 0
+	for n := b / unit; n >= unit; n /= unit {
+		div *= unit
+		exp++
+	}
+	return fmt.Sprintf("%.1f%ciB", float64(b)/float64(div), "KMGTPE"[exp])
+}
+
+type CheckCertsExpiration struct {
+	common.KubeAction
+}
+
+func (c *CheckCertsExpiration) Execute(runtime connector.Runtime) error {
+	report, err := getReport(c.PipelineCache)
+	if err != nil {
+		return err
+	}
+	host := runtime.RemoteHost()
+
+	v, ok := host.GetCache().Get(common.Certificate)
+	if !ok {
+		report.Add(host.GetName(), CheckCerts, StatusError, "failed to read the certificates in %s", common.KubeCertDir)
+		return nil
+	}
+	expires := make(map[string]string)
+	for _, cert := range v.([]*certs.Certificate) {
+		expires[cert.Name] = cert.Expires
+	}
+	if v, ok := host.GetCache().Get(common.CaCertificate); ok {
+		for _, cert := range v.([]*certs.CaCertificate) {
+			expires[cert.AuthorityName] = cert.Expires
+		}
+	}
+
+	var expired, expiring []string
+	earliest, earliestName := time.Time{}, ""
+	for name, e := range expires {
+		t, err := time.Parse("Jan 02, 2006 15:04 MST", e)
+		if err != nil {
+			return errors.Wrap(errors.WithStack(err), fmt.Sprintf("parse the expiration of %s failed", name))
+		}
+		d := time.Until(t)
+		switch {
+		case d <= 0:
+			expired = append(expired, name)
+		case d < CertExpirationWarning:
+			expiring = append(expiring, fmt.Sprintf("%s (%s)", name, certs.ResidualTime(t)))
+		}
+		if earliestName == "" || t.Before(earliest) {
+			earliest, earliestName = t, name
+		}
+	}
+
+	switch {
+	case len(expired) > 0:
+		report.Add(host.GetName(), CheckCerts, StatusError, "expired: %s", strings.Join(expired, ", "))
+	case len(expiring) > 0:
+		report.Add(host.GetName(), CheckCerts, StatusWarning, "expire soon: %s", strings.Join(expiring, ", "))
+	default:
+		report.Add(host.GetName(), CheckCerts, StatusOK, "the first one %s expires in %s", earliestName, certs.ResidualTime(earliest))
+	}
+	return nil
+}
+
+type CheckAPIServer struct {
+	common.KubeAction
+}
+
+func (c *CheckAPIServer) Execute(runtime connector.Runtime) error {
+	report, err := getReport(c.PipelineCache)
+	if err != nil {
+		return err
+	}
+	host := runtime.RemoteHost()
+
+	url := fmt.Sprintf("https://%s:%d/healthz", c.KubeConf.Cluster.ControlPlaneEndpoint.Domain, c.KubeConf.Cluster.ControlPlaneEndpoint.Port)
+	output, err := runtime.GetRunner().SudoCmd(fmt.Sprintf("curl -sk --max-time 5 %s 2>&1 || true", url), false)
+	if err != nil {
+		return errors.Wrap(errors.WithStack(err), "request the apiserver failed")
+	}
+	if strings.TrimSpace(output) == "ok" {
+		report.Add(host.GetName(), CheckAPIServerReachability, StatusOK, "%s is ok", url)
+	} else {
+		report.Add(host.GetName(), CheckAPIServerReachability, StatusError, "%s is unreachable: %s", url, strings.TrimSpace(output))
+	}
+	return nil
+}
+
+// CheckTimeSkew compares the time of the node with the machine where kk runs, the middle of the
+// request is used as the local time to reduce the error of the network latency.
+type CheckTimeSkew struct {
+	common.KubeAction
+}
+
+func (c *CheckTimeSkew) Execute(runtime connector.Runtime) error {
+	report, err := getReport(c.PipelineCache)
+	if err != nil {
+		return err
+	}
+	host := runtime.RemoteHost()
+
+	before := time.Now()
+	output, err := runtime.GetRunner().SudoCmd("date +%s.%N", false)
+	if err != nil {
+		return errors.Wrap(errors.WithStack(err), "get the time of the node failed")
+	}
+	local := before.Add(time.Since(before) / 2)
+
+	seconds, err := strconv.ParseFloat(strings.TrimSpace(output), 64)
+	if err != nil {
+		return errors.Wrap(errors.WithStack(err), fmt.Sprintf("parse the time %s failed", output))
+	}
+	sec, frac := math.Modf(seconds)
+	skew := time.Unix(int64(sec), int64(frac*1e9)).Sub(local)
+	abs := time.Duration(math.Abs(float64(skew)))
+
+	status := StatusOK
+	switch {
+	case abs >= TimeSkewError:
+		status = StatusError
+	case abs >= TimeSkewWarning:
+		status = StatusWarning
+	}
+	report.Add(host.GetName(), CheckTimeSkewName, status, "%s compared with the local machine", skew.Round(time.Millisecond))
+	return nil
+}
+
+type CheckDiskPressure struct {
+	common.KubeAction
+}
+
+func (c *CheckDiskPressure) Execute(runtime connector.Runtime) error {
+	report, err := getReport(c.PipelineCache)
+	if err != nil {
+		return err
+	}
+	output, err := runtime.GetRunner().SudoCmd("/usr/local/bin/kubectl get node -o json", false)
+	if err != nil {
+		report.Add(ClusterNode, CheckDisk, StatusError, "failed to get the nodes by kubectl on %s", runtime.RemoteHost().GetName())
+		return nil
+	}
+	pressures, err := DiskPressure([]byte(output))
+	if err != nil {
+		return err
+	}
+
+	// The nodes which are not found in the cluster are reported by the node status check.
+	for _, host := range runtime.GetHostsByRole(common.K8s) {
+		pressure, ok := pressures[host.GetName()]
+		switch {
+		case !ok:
+			continue
+		case pressure == corev1.ConditionFalse:
+			report.Add(host.GetName(), CheckDisk, StatusOK, "no disk pressure")
+		case pressure == corev1.ConditionTrue:
+			report.Add(host.GetName(), CheckDisk, StatusError, "the node is under disk pressure")
+		default:
+			report.Add(host.GetName(), CheckDisk, StatusWarning, "the disk pressure of the node is unknown")
+		}
+	}
+	return nil
+}
+
+// DiskPressure returns the status of the DiskPressure condition of the nodes in the output of "kubectl get node -o json".
+// The status is empty if the condition is not reported by the kubelet.
+func DiskPressure(output []byte) (map[string]corev1.ConditionStatus, error) {
+	var nodes corev1.NodeList
+	if err := json.Unmarshal(output, &nodes); err != nil {
+		return nil, errors.Wrap(errors.WithStack(err), "parse the kubernetes nodes failed")
+	}
+	pressures := make(map[string]corev1.ConditionStatus, len(nodes.Items))
+	for _, node := range nodes.Items {
+		pressures[node.Name] = ""
+		for _, condition := range node.Status.Conditions {
+			if condition.Type == corev1.NodeDiskPressure {
+				pressures[node.Name] = condition.Status
+			}
+		}
+	}
+	return pressures, nil
+}
+
+type PrintReport struct {
+	common.KubeAction
+	Output string
+	Writer io.Writer
+}
+
+func (p *PrintReport) Execute(runtime connector.Runtime) error {
+	report, err := getReport(p.PipelineCache)
+	if err != nil {
+		return err
+	}
+
+	if p.Output == common.OutputJSON {
+		err = report.PrintJSON(p.Writer)
+	} else {
+		err = report.PrintText(p.Writer)
+	}
+	if err != nil {
+		return errors.Wrap(errors.WithStack(err), "print the cluster check report failed")
+	}
+
+	if report.Failed() {
+		return errors.Errorf("the cluster check found %d problems", report.Summary.Error)
+	}
+	return nil
+}
+
+// ReportNotFound fails the cluster check if the report is not created by the ConnectivityModule.
+type ReportNotFound struct {
+	common.KubeAction
+	Err error
+}
+
+func (r *ReportNotFound) Execute(runtime connector.Runtime) error {
+	return r.Err
+}
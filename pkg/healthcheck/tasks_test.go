@@ -0,0 +1,59 @@
+/*
+ Copyright 2021 The KubeSphere Authors.
+
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+     http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package healthcheck
+
+import (
+	"reflect"
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+)
+
+func TestDiskPressure(t *testing.T) {
+	output := `{
+  "apiVersion": "v1",
+  "kind": "List",
+  "items": [
+    {"metadata": {"name": "node1"}, "status": {"conditions": [
+      {"type": "MemoryPressure", "status": "True"},
+      {"type": "DiskPressure", "status": "False"},
+      {"type": "Ready", "status": "True"}
+    ]}},
+    {"metadata": {"name": "node2"}, "status": {"conditions": [{"type": "DiskPressure", "status": "True"}]}},
+    {"metadata": {"name": "node3"}, "status": {"conditions": [{"type": "DiskPressure", "status": "Unknown"}]}},
+    {"metadata": {"name": "node4"}, "status": {}}
+  ]
+}`
+	want := map[string]corev1.ConditionStatus{
+		"node1": corev1.ConditionFalse,
+		"node2": corev1.ConditionTrue,
+		"node3": corev1.ConditionUnknown,
+		"node4": "",
+	}
+
+	got, err := DiskPressure([]byte(output))
+	if err != nil {
+		t.Fatalf("DiskPressure() error = %v", err)
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("DiskPressure() = %v, want %v", got, want)
+	}
+
+	if _, err := DiskPressure([]byte("error: the server doesn't have a resource type")); err == nil {
+		t.Errorf("DiskPressure() of an invalid output should fail")
+	}
+}
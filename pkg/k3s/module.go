@@ -29,6 +29,10 @@ type StatusModule struct {
 	common.KubeModule
 }
 
+func (s *StatusModule) IsAlwaysRun() bool {
+	return true
+}
+
 func (s *StatusModule) Init() {
 	s.Name = "StatusModule"
 	s.Desc = "Get cluster status"
@@ -327,6 +331,34 @@ func (d *DeleteClusterModule) Init() {
 	}
 }
 
+type DeleteNodeModule struct {
+	common.KubeModule
+	Skip bool
+}
+
+func (d *DeleteNodeModule) IsSkip() bool {
+	return d.Skip
+}
+
+func (d *DeleteNodeModule) Init() {
+	d.Name = "DeleteNodeModule"
+	d.Desc = "Uninstall k3s on the deleted node"
+
+	execScript := &task.RemoteTask{
+		Name:        "ExecNodeUninstallScript",
+		Desc:        "Exec k3s uninstall script on the node",
+		Hosts:       d.Runtime.GetHostsByRole(common.K8s),
+		Prepare:     new(common.OnlyNode),
+		Action:      new(ExecNodeUninstallScript),
+		Parallel:    true,
+		IgnoreError: true,
+	}
+
+	d.Tasks = []task.Interface{
+		execScript,
+	}
+}
+
 type SaveKubeConfigModule struct {
 	common.KubeModule
 }
@@ -25,6 +25,7 @@ import (
 	"github.com/kubesphere/kubekey/pkg/core/action"
 	"github.com/kubesphere/kubekey/pkg/core/connector"
 	"github.com/kubesphere/kubekey/pkg/core/util"
+	"github.com/kubesphere/kubekey/pkg/etcd"
 	"github.com/kubesphere/kubekey/pkg/files"
 	"github.com/kubesphere/kubekey/pkg/images"
 	"github.com/kubesphere/kubekey/pkg/k3s/templates"
@@ -34,6 +35,7 @@ import (
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	kube "k8s.io/client-go/kubernetes"
 	"k8s.io/client-go/tools/clientcmd"
+	"net"
 	"path/filepath"
 	"strings"
 )
@@ -196,16 +198,22 @@ func (g *GenerateK3sService) Execute(runtime connector.Runtime) error {
 	kubeletArgs, _ := util.GetArgs(defaultKubeletArs, g.KubeConf.Cluster.Kubernetes.KubeletArgs)
 	kubeProxyArgs, _ := util.GetArgs(defaultKubeProxyArgs, g.KubeConf.Cluster.Kubernetes.KubeProxyArgs)
 
+	// the node-ip contains both the IPv4 and IPv6 addresses on the dual-stack cluster
+	nodeIP := host.GetInternalAddress()
+	if hostCfg := g.KubeConf.Cluster.FindHost(host.GetName()); hostCfg != nil && g.KubeConf.Cluster.Network.IsDualStack() {
+		nodeIP = strings.Join(hostCfg.InternalAddresses(), ",")
+	}
+
 	templateAction := action.Template{
 		Template: templates.K3sService,
 		Dst:      filepath.Join("/etc/systemd/system/", templates.K3sService.Name()),
 		Data: util.Data{
 			"Server":            server,
 			"IsMaster":          host.IsRole(common.Master),
-			"NodeIP":            host.GetInternalAddress(),
+			"NodeIP":            nodeIP,
 			"HostName":          host.GetName(),
-			"PodSubnet":         g.KubeConf.Cluster.Network.KubePodsCIDR,
-			"ServiceSubnet":     g.KubeConf.Cluster.Network.KubeServiceCIDR,
+			"PodSubnet":         strings.Join(g.KubeConf.Cluster.Network.PodCIDRs(), ","),
+			"ServiceSubnet":     strings.Join(g.KubeConf.Cluster.Network.ServiceCIDRs(), ","),
 			"ClusterDns":        g.KubeConf.Cluster.CorednsClusterIP(),
 			"CertSANs":          g.KubeConf.Cluster.GenerateCertSANs(),
 			"PauseImage":        images.GetImage(runtime, g.KubeConf, "pause").ImageName(),
@@ -242,20 +250,24 @@ func (g *GenerateK3sServiceEnv) Execute(runtime connector.Runtime) error {
 	var caFile, certFile, keyFile string
 	var token string
 
-	for _, node := range runtime.GetHostsByRole(common.ETCD) {
-		endpoint := fmt.Sprintf("https://%s:%s", node.GetInternalAddress(), kubekeyapiv1alpha2.DefaultEtcdPort)
-		endpointsList = append(endpointsList, endpoint)
+	if g.KubeConf.Cluster.Etcd.IsExternal() {
+		externalEtcd = etcd.ExternalEtcd(g.KubeConf.Cluster)
+		endpointsList = externalEtcd.Endpoints
+	} else {
+		for _, node := range runtime.GetHostsByRole(common.ETCD) {
+			endpoint := fmt.Sprintf("https://%s", net.JoinHostPort(node.GetInternalAddress(), kubekeyapiv1alpha2.DefaultEtcdPort))
+			endpointsList = append(endpointsList, endpoint)
+		}
+		externalEtcd.Endpoints = endpointsList
+		externalEtcd.CaFile = "/etc/ssl/etcd/ssl/ca.pem"
+		externalEtcd.CertFile = fmt.Sprintf("/etc/ssl/etcd/ssl/node-%s.pem", runtime.GetHostsByRole(common.Master)[0].GetName())
+		externalEtcd.KeyFile = fmt.Sprintf("/etc/ssl/etcd/ssl/node-%s-key.pem", runtime.GetHostsByRole(common.Master)[0].GetName())
 	}
-	externalEtcd.Endpoints = endpointsList
 
 	externalEtcdEndpoints := strings.Join(endpointsList, ",")
-	caFile = "/etc/ssl/etcd/ssl/ca.pem"
-	certFile = fmt.Sprintf("/etc/ssl/etcd/ssl/node-%s.pem", runtime.GetHostsByRole(common.Master)[0].GetName())
-	keyFile = fmt.Sprintf("/etc/ssl/etcd/ssl/node-%s-key.pem", runtime.GetHostsByRole(common.Master)[0].GetName())
-
-	externalEtcd.CaFile = caFile
-	externalEtcd.CertFile = certFile
-	externalEtcd.KeyFile = keyFile
+	caFile = externalEtcd.CaFile
+	certFile = externalEtcd.CertFile
+	keyFile = externalEtcd.KeyFile
 
 	if !host.IsRole(common.Master) {
 		token = cluster.NodeToken
@@ -425,6 +437,26 @@ func (e *ExecUninstallScript) Execute(runtime connector.Runtime) error {
 	return nil
 }
 
+type ExecNodeUninstallScript struct {
+	common.KubeAction
+}
+
+func (e *ExecNodeUninstallScript) Execute(runtime connector.Runtime) error {
+	script := "/usr/local/bin/k3s-agent-uninstall.sh"
+	if runtime.RemoteHost().IsRole(common.Master) {
+		script = "/usr/local/bin/k3s-uninstall.sh"
+	}
+	if _, err := runtime.GetRunner().SudoCmd("systemctl daemon-reload && /usr/local/bin/k3s-killall.sh",
+		true); err != nil {
+		return errors.Wrap(errors.WithStack(err), "kill k3s processes failed")
+	}
+	if _, err := runtime.GetRunner().SudoCmd(fmt.Sprintf("systemctl daemon-reload && %s", script),
+		true); err != nil {
+		return errors.Wrap(errors.WithStack(err), "uninstall k3s failed")
+	}
+	return nil
+}
+
 type SaveKubeConfig struct {
 	common.KubeAction
 }
@@ -437,7 +469,7 @@ func (s *SaveKubeConfig) Execute(_ connector.Runtime) error {
 	cluster := status.(*K3sStatus)
 
 	oldServer := fmt.Sprintf("https://%s:%d", s.KubeConf.Cluster.ControlPlaneEndpoint.Domain, s.KubeConf.Cluster.ControlPlaneEndpoint.Port)
-	newServer := fmt.Sprintf("https://%s:%d", s.KubeConf.Cluster.ControlPlaneEndpoint.Address, s.KubeConf.Cluster.ControlPlaneEndpoint.Port)
+	newServer := fmt.Sprintf("https://%s:%d", util.URLHost(s.KubeConf.Cluster.ControlPlaneEndpoint.Address), s.KubeConf.Cluster.ControlPlaneEndpoint.Port)
 	newKubeConfigStr := strings.Replace(cluster.KubeConfig, oldServer, newServer, -1)
 	kubeConfigBase64 := base64.StdEncoding.EncodeToString([]byte(newKubeConfigStr))
 
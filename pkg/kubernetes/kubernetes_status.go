@@ -20,6 +20,7 @@ import (
 	"fmt"
 	"github.com/kubesphere/kubekey/pkg/common"
 	"github.com/kubesphere/kubekey/pkg/core/connector"
+	"github.com/kubesphere/kubekey/pkg/core/util"
 	"github.com/pkg/errors"
 	"io/ioutil"
 	"path/filepath"
@@ -140,7 +141,7 @@ func (k *KubernetesStatus) LoadKubeConfig(runtime connector.Runtime, kubeConf *c
 	kubeConfigStr := k.KubeConfig
 
 	oldServer := fmt.Sprintf("server: https://%s:%d", kubeConf.Cluster.ControlPlaneEndpoint.Domain, kubeConf.Cluster.ControlPlaneEndpoint.Port)
-	newServer := fmt.Sprintf("server: https://%s:%d", kubeConf.Cluster.ControlPlaneEndpoint.Address, kubeConf.Cluster.ControlPlaneEndpoint.Port)
+	newServer := fmt.Sprintf("server: https://%s:%d", util.URLHost(kubeConf.Cluster.ControlPlaneEndpoint.Address), kubeConf.Cluster.ControlPlaneEndpoint.Port)
 	newKubeConfigStr := strings.Replace(kubeConfigStr, oldServer, newServer, -1)
 
 	if err := ioutil.WriteFile(kubeConfigPath, []byte(newKubeConfigStr), 0644); err != nil {
@@ -162,3 +163,12 @@ func patchKubeadmSecret(runtime connector.Runtime) error {
 	}
 	return nil
 }
+
+// HasNode checks if the host has joined the cluster by its name or internal address.
+func (k *KubernetesStatus) HasNode(host connector.Host) bool {
+	if res, ok := k.NodesInfo[host.GetName()]; ok && res != "" {
+		return true
+	}
+	_, ok := k.NodesInfo[host.GetInternalAddress()]
+	return ok
+}
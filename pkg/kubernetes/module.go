@@ -21,10 +21,13 @@ import (
 	"github.com/kubesphere/kubekey/pkg/binaries"
 	"github.com/kubesphere/kubekey/pkg/common"
 	"github.com/kubesphere/kubekey/pkg/core/action"
+	"github.com/kubesphere/kubekey/pkg/core/connector"
 	"github.com/kubesphere/kubekey/pkg/core/prepare"
 	"github.com/kubesphere/kubekey/pkg/core/task"
+	"github.com/kubesphere/kubekey/pkg/etcd"
 	"github.com/kubesphere/kubekey/pkg/images"
 	"github.com/kubesphere/kubekey/pkg/kubernetes/templates"
+	"github.com/kubesphere/kubekey/pkg/plugins/network"
 	"github.com/pkg/errors"
 	"path/filepath"
 )
@@ -33,6 +36,10 @@ type StatusModule struct {
 	common.KubeModule
 }
 
+func (k *StatusModule) IsAlwaysRun() bool {
+	return true
+}
+
 func (k *StatusModule) Init() {
 	k.Name = "KubernetesStatusModule"
 	k.Desc = "Get kubernetes cluster status"
@@ -328,6 +335,134 @@ func (j *JoinNodesModule) Init() {
 	}
 }
 
+// NodeLabelsModule applies the labels and taints of the hosts in the config to the kubernetes nodes.
+type NodeLabelsModule struct {
+	common.KubeModule
+	// CheckOnly reports the drift without changing the nodes.
+	CheckOnly bool
+}
+
+func (n *NodeLabelsModule) Init() {
+	n.Name = "NodeLabelsModule"
+	n.Desc = "Reconcile the labels and taints of the nodes"
+
+	reconcile := &task.RemoteTask{
+		Name:     "ReconcileNodeLabelsAndTaints",
+		Desc:     "Reconcile the labels and taints of the nodes",
+		Hosts:    n.Runtime.GetHostsByRole(common.Master),
+		Prepare:  new(common.OnlyFirstMaster),
+		Action:   &ReconcileNodeLabelsAndTaints{CheckOnly: n.CheckOnly},
+		Parallel: true,
+		Retry:    3,
+	}
+
+	n.Tasks = []task.Interface{
+		reconcile,
+	}
+}
+
+type AddControlPlanePreCheckModule struct {
+	common.KubeModule
+}
+
+func (a *AddControlPlanePreCheckModule) Init() {
+	a.Name = "AddControlPlanePreCheckModule"
+	a.Desc = "Check the cluster before adding control-plane nodes"
+
+	checkCertSANs := &task.RemoteTask{
+		Name:  "CheckCertSANs",
+		Desc:  "Check the SANs of the apiserver cert",
+		Hosts: a.Runtime.GetHostsByRole(common.Master),
+		Prepare: &prepare.PrepareCollection{
+			new(NodeInCluster),
+			new(NewMasterExists),
+		},
+		Action:   new(CheckCertSANs),
+		Parallel: true,
+	}
+
+	a.Tasks = []task.Interface{
+		checkCertSANs,
+	}
+
+	// The members of the etcd cluster are changed by joining the control-plane nodes if it is deployed by kubeadm,
+	// or by the etcd nodes which are installed by kubekey.
+	switch {
+	case a.KubeConf.Cluster.Etcd.IsKubeadm():
+		a.Tasks = append(a.Tasks, &task.RemoteTask{
+			Name:  "ETCDClusterHealthCheck",
+			Desc:  "Check the health of all etcd members",
+			Hosts: a.Runtime.GetHostsByRole(common.Master),
+			Prepare: &prepare.PrepareCollection{
+				new(FirstMasterInCluster),
+				new(NewMasterExists),
+			},
+			Action: new(etcd.ClusterHealthCheck),
+		})
+	case a.KubeConf.Cluster.Etcd.IsKubeKey():
+		a.Tasks = append(a.Tasks, &task.RemoteTask{
+			Name:    "ETCDClusterHealthCheck",
+			Desc:    "Check the health of all etcd members",
+			Hosts:   a.Runtime.GetHostsByRole(common.ETCD),
+			Prepare: new(etcd.FirstETCDNode),
+			Action:  new(etcd.ClusterHealthCheck),
+		})
+	}
+}
+
+type UpdateCertSANsModule struct {
+	common.KubeModule
+}
+
+func (u *UpdateCertSANsModule) Init() {
+	u.Name = "UpdateCertSANsModule"
+	u.Desc = "Update the SANs of the apiserver cert"
+
+	generateKubeadmConfig := &task.RemoteTask{
+		Name:  "GenerateKubeadmConfig",
+		Desc:  "Generate kubeadm config",
+		Hosts: u.Runtime.GetHostsByRole(common.Master),
+		Prepare: &prepare.PrepareCollection{
+			new(FirstMasterInCluster),
+			new(NewMasterExists),
+		},
+		Action: &GenerateKubeadmConfig{IsInitConfiguration: true},
+	}
+
+	// The new control-plane nodes generate their apiserver cert by the ClusterConfiguration in the cluster.
+	uploadKubeadmConfig := &task.RemoteTask{
+		Name:  "UploadKubeadmConfig",
+		Desc:  "Upload kubeadm config to the cluster",
+		Hosts: u.Runtime.GetHostsByRole(common.Master),
+		Prepare: &prepare.PrepareCollection{
+			new(FirstMasterInCluster),
+			new(NewMasterExists),
+		},
+		Action: new(UploadKubeadmConfig),
+	}
+
+	renewAPIServerCert := &task.RemoteTask{
+		Name:  "RenewAPIServerCert",
+		Desc:  "Renew the apiserver cert with the new SANs",
+		Hosts: u.Runtime.GetHostsByRole(common.Master),
+		Prepare: &prepare.PrepareCollection{
+			new(NodeInCluster),
+			new(NewMasterExists),
+		},
+		Action:      new(RenewAPIServerCert),
+		Parallel:    true,
+		Rolling:     true,
+		Concurrency: 1 / float64(len(u.Runtime.GetHostsByRole(common.Master))),
+		Retry:       1,
+	}
+
+	u.Tasks = []task.Interface{
+		generateKubeadmConfig,
+		uploadKubeadmConfig,
+		renewAPIServerCert,
+	}
+}
+
 type ResetClusterModule struct {
 	common.KubeModule
 }
@@ -349,27 +484,6 @@ func (r *ResetClusterModule) Init() {
 	}
 }
 
-type CompareConfigAndClusterInfoModule struct {
-	common.KubeModule
-}
-
-func (c *CompareConfigAndClusterInfoModule) Init() {
-	c.Name = "CompareConfigAndClusterInfoModule"
-	c.Desc = "Compare config and cluster nodes info"
-
-	check := &task.RemoteTask{
-		Name:    "FindDifferences",
-		Desc:    "Find the differences between config and cluster node info",
-		Hosts:   c.Runtime.GetHostsByRole(common.Master),
-		Prepare: new(common.OnlyFirstMaster),
-		Action:  new(FindDifferences),
-	}
-
-	c.Tasks = []task.Interface{
-		check,
-	}
-}
-
 type DeleteKubeNodeModule struct {
 	common.KubeModule
 }
@@ -378,22 +492,29 @@ func (d *DeleteKubeNodeModule) Init() {
 	d.Name = "DeleteKubeNodeModule"
 	d.Desc = "Delete kubernetes node"
 
+	// The node is drained and deleted by the first master which is not going to be deleted.
+	var masters []connector.Host
+	for _, host := range d.Runtime.GetHostsByRole(common.Master) {
+		if host.GetName() != d.KubeConf.Arg.NodeName {
+			masters = append(masters, host)
+			break
+		}
+	}
+
 	drain := &task.RemoteTask{
-		Name:    "DrainNode",
-		Desc:    "Node safely evict all pods",
-		Hosts:   d.Runtime.GetHostsByRole(common.Master),
-		Prepare: new(common.OnlyFirstMaster),
-		Action:  new(DrainNode),
-		Retry:   5,
+		Name:   "DrainNode",
+		Desc:   "Node safely evict all pods",
+		Hosts:  masters,
+		Action: new(DrainNode),
+		Retry:  5,
 	}
 
 	deleteNode := &task.RemoteTask{
-		Name:    "DeleteNode",
-		Desc:    "Delete the node using kubectl",
-		Hosts:   d.Runtime.GetHostsByRole(common.Master),
-		Prepare: new(common.OnlyFirstMaster),
-		Action:  new(KubectlDeleteNode),
-		Retry:   5,
+		Name:   "DeleteNode",
+		Desc:   "Delete the node using kubectl",
+		Hosts:  masters,
+		Action: new(KubectlDeleteNode),
+		Retry:  5,
 	}
 
 	d.Tasks = []task.Interface{
@@ -402,11 +523,43 @@ func (d *DeleteKubeNodeModule) Init() {
 	}
 }
 
+type ResetNodeModule struct {
+	common.KubeModule
+	Skip bool
+}
+
+func (r *ResetNodeModule) IsSkip() bool {
+	return r.Skip
+}
+
+func (r *ResetNodeModule) Init() {
+	r.Name = "ResetNodeModule"
+	r.Desc = "Reset the deleted kubernetes node"
+
+	kubeadmReset := &task.RemoteTask{
+		Name:        "KubeadmReset",
+		Desc:        "Reset the node using kubeadm",
+		Hosts:       r.Runtime.GetHostsByRole(common.K8s),
+		Prepare:     new(common.OnlyNode),
+		Action:      new(KubeadmReset),
+		Parallel:    true,
+		IgnoreError: true,
+	}
+
+	r.Tasks = []task.Interface{
+		kubeadmReset,
+	}
+}
+
 type SetUpgradePlanModule struct {
 	common.KubeModule
 	Step UpgradeStep
 }
 
+func (s *SetUpgradePlanModule) IsAlwaysRun() bool {
+	return true
+}
+
 func (s *SetUpgradePlanModule) Init() {
 	s.Name = fmt.Sprintf("SetUpgradePlanModule %d/%d", s.Step, len(UpgradeStepList))
 	s.Desc = "Set upgrade plan"
@@ -420,6 +573,21 @@ func (s *SetUpgradePlanModule) Init() {
 	s.Tasks = []task.Interface{
 		plan,
 	}
+
+	// the first plan covers the whole upgrade path, it checks the etcd of every hop
+	if s.Step == ToKubeSphereCompatible && s.KubeConf.Cluster.Etcd.IsKubeKey() {
+		etcdVersion := &task.RemoteTask{
+			Name:     "GetETCDVersion",
+			Desc:     "Get etcd version",
+			Hosts:    s.Runtime.GetHostsByRole(common.ETCD),
+			Action:   new(etcd.GetVersion),
+			Parallel: true,
+		}
+		s.Tasks = []task.Interface{
+			etcdVersion,
+			plan,
+		}
+	}
 }
 
 type ProgressiveUpgradeModule struct {
@@ -464,6 +632,48 @@ func (p *ProgressiveUpgradeModule) Init() {
 		Retry:    2,
 	}
 
+	etcdStatus := &task.RemoteTask{
+		Name:     "GetETCDStatus",
+		Desc:     "Get etcd status",
+		Hosts:    p.Runtime.GetHostsByRole(common.ETCD),
+		Prepare:  new(NotEqualPlanVersion),
+		Action:   new(etcd.GetStatus),
+		Parallel: false,
+	}
+
+	etcdAccessAddress := &task.RemoteTask{
+		Name:  "GenerateAccessAddress",
+		Desc:  "Generate access address",
+		Hosts: p.Runtime.GetHostsByRole(common.ETCD),
+		Prepare: &prepare.PrepareCollection{
+			new(NotEqualPlanVersion),
+			new(etcd.FirstETCDNode),
+		},
+		Action:   new(etcd.GenerateAccessAddress),
+		Parallel: true,
+	}
+
+	etcdVersion := &task.RemoteTask{
+		Name:     "GetETCDVersion",
+		Desc:     "Get etcd version",
+		Hosts:    p.Runtime.GetHostsByRole(common.ETCD),
+		Prepare:  new(NotEqualPlanVersion),
+		Action:   new(etcd.GetVersion),
+		Parallel: true,
+	}
+
+	upgradeETCD := &task.RemoteTask{
+		Name:  "UpgradeETCD",
+		Desc:  "Upgrade etcd member",
+		Hosts: p.Runtime.GetHostsByRole(common.ETCD),
+		Prepare: &prepare.PrepareCollection{
+			new(NotEqualPlanVersion),
+			new(etcd.OutdatedVersion),
+		},
+		Action:   new(etcd.UpgradeMember),
+		Parallel: false,
+	}
+
 	upgradeKubeMaster := &task.RemoteTask{
 		Name:     "UpgradeClusterOnMaster",
 		Desc:     "Upgrade cluster on master",
@@ -485,6 +695,8 @@ func (p *ProgressiveUpgradeModule) Init() {
 		Parallel: false,
 	}
 
+	// the workers are drained and upgraded in batches of maxUnavailable, which defaults to one worker at a time
+	// as the upgrade did before it was configurable.
 	upgradeKubeWorker := &task.RemoteTask{
 		Name:  "UpgradeClusterOnWorker",
 		Desc:  "Upgrade cluster on worker",
@@ -493,8 +705,10 @@ func (p *ProgressiveUpgradeModule) Init() {
 			new(NotEqualPlanVersion),
 			new(common.OnlyWorker),
 		},
-		Action:   &UpgradeKubeWorker{ModuleName: p.Name},
-		Parallel: false,
+		Action:      &UpgradeKubeWorker{ModuleName: p.Name},
+		Parallel:    true,
+		Rolling:     true,
+		Concurrency: p.KubeConf.Cluster.Execution.RollingConcurrency(len(p.Runtime.GetHostsByRole(common.Worker))),
 	}
 
 	reconfigureDNS := &task.RemoteTask{
@@ -521,12 +735,34 @@ func (p *ProgressiveUpgradeModule) Init() {
 		download,
 		pull,
 		syncBinary,
+	}
+
+	// the etcd deployed by kk is upgraded before the kube-apiservers which require it
+	if p.KubeConf.Cluster.Etcd.IsKubeKey() {
+		p.Tasks = append(p.Tasks,
+			etcdStatus,
+			etcdAccessAddress,
+			etcdVersion,
+			upgradeETCD,
+		)
+	}
+
+	p.Tasks = append(p.Tasks,
 		upgradeKubeMaster,
 		clusterStatus,
 		upgradeKubeWorker,
 		reconfigureDNS,
-		currentVersion,
+	)
+
+	// the network plugin is redeployed once all the kubelets run the new version
+	for _, t := range network.UpgradeTasks(p.KubeModule) {
+		if rt, ok := t.(*task.RemoteTask); ok {
+			rt.Prepare = &prepare.PrepareCollection{new(NotEqualPlanVersion), rt.Prepare}
+		}
+		p.Tasks = append(p.Tasks, t)
 	}
+
+	p.Tasks = append(p.Tasks, currentVersion)
 }
 
 func (p *ProgressiveUpgradeModule) Until() (*bool, error) {
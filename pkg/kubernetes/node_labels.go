@@ -0,0 +1,293 @@
+/*
+ Copyright 2021 The KubeSphere Authors.
+
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+     http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package kubernetes
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+
+	kubekeyv1alpha2 "github.com/kubesphere/kubekey/apis/kubekey/v1alpha2"
+	"github.com/kubesphere/kubekey/pkg/common"
+	"github.com/kubesphere/kubekey/pkg/core/connector"
+	"github.com/kubesphere/kubekey/pkg/core/logger"
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+)
+
+const (
+	// ManagedLabelsAnnotation and ManagedTaintsAnnotation record the labels and taints applied by kubekey,
+	// so the ones removed from the config can be removed from the node without touching the others.
+	ManagedLabelsAnnotation = "kubekey.kubesphere.io/managed-labels"
+	ManagedTaintsAnnotation = "kubekey.kubesphere.io/managed-taints"
+)
+
+// kubeletLabelNamespaces are the kubernetes.io and k8s.io label namespaces which kubelet is allowed to set on itself.
+var kubeletLabelNamespaces = []string{"kubelet.kubernetes.io", "node.kubernetes.io"}
+
+// kubeletLabels are the labels under the kubernetes.io and k8s.io namespaces which kubelet is allowed to set on itself.
+var kubeletLabels = map[string]struct{}{
+	"kubernetes.io/hostname":                   {},
+	"kubernetes.io/arch":                       {},
+	"kubernetes.io/os":                         {},
+	"beta.kubernetes.io/instance-type":         {},
+	"node.kubernetes.io/instance-type":         {},
+	"failure-domain.beta.kubernetes.io/region": {},
+	"failure-domain.beta.kubernetes.io/zone":   {},
+	"topology.kubernetes.io/region":            {},
+	"topology.kubernetes.io/zone":              {},
+}
+
+// NodeDrift is the difference between the labels and taints in the config and the ones on the node.
+type NodeDrift struct {
+	Node         string
+	SetLabels    map[string]string
+	RemoveLabels []string
+	SetTaints    []kubekeyv1alpha2.Taint
+	RemoveTaints []kubekeyv1alpha2.Taint
+	// ManagedLabels and ManagedTaints are the new values of the annotations, Annotate is true if they are changed.
+	ManagedLabels string
+	ManagedTaints string
+	Annotate      bool
+}
+
+// CalculateNodeDrift compares the declared labels and taints with the node. Only the labels and taints
+// recorded in the managed annotations are removed, the ones added by others are left as they are.
+func CalculateNodeDrift(node *corev1.Node, labels map[string]string, taints []kubekeyv1alpha2.Taint) *NodeDrift {
+	d := &NodeDrift{
+		Node:      node.Name,
+		SetLabels: make(map[string]string),
+	}
+
+	for k, v := range labels {
+		if current, ok := node.Labels[k]; !ok || current != v {
+			d.SetLabels[k] = v
+		}
+	}
+	for _, k := range splitAnnotation(node.Annotations[ManagedLabelsAnnotation]) {
+		if _, ok := labels[k]; ok {
+			continue
+		}
+		if _, ok := node.Labels[k]; ok {
+			d.RemoveLabels = append(d.RemoveLabels, k)
+		}
+	}
+
+	declared := make(map[string]struct{}, len(taints))
+	for _, t := range taints {
+		declared[taintID(t.Key, t.Effect)] = struct{}{}
+		found := false
+		for _, current := range node.Spec.Taints {
+			if current.Key == t.Key && string(current.Effect) == t.Effect && current.Value == t.Value {
+				found = true
+				break
+			}
+		}
+		if !found {
+			d.SetTaints = append(d.SetTaints, t)
+		}
+	}
+	for _, id := range splitAnnotation(node.Annotations[ManagedTaintsAnnotation]) {
+		if _, ok := declared[id]; ok {
+			continue
+		}
+		for _, current := range node.Spec.Taints {
+			if taintID(current.Key, string(current.Effect)) == id {
+				d.RemoveTaints = append(d.RemoveTaints, kubekeyv1alpha2.Taint{Key: current.Key, Effect: string(current.Effect)})
+			}
+		}
+	}
+	sort.Strings(d.RemoveLabels)
+
+	managedTaints := make([]string, 0, len(declared))
+	for id := range declared {
+		managedTaints = append(managedTaints, id)
+	}
+	sort.Strings(managedTaints)
+	d.ManagedLabels = strings.Join(sortedKeys(labels), ",")
+	d.ManagedTaints = strings.Join(managedTaints, ",")
+	d.Annotate = d.ManagedLabels != node.Annotations[ManagedLabelsAnnotation] ||
+		d.ManagedTaints != node.Annotations[ManagedTaintsAnnotation]
+	return d
+}
+
+// IsDrifted returns true if the labels or taints of the node are different from the config.
+func (d *NodeDrift) IsDrifted() bool {
+	return len(d.SetLabels) != 0 || len(d.RemoveLabels) != 0 || len(d.SetTaints) != 0 || len(d.RemoveTaints) != 0
+}
+
+// Messages describes the drift in a human readable way.
+func (d *NodeDrift) Messages() []string {
+	var msgs []string
+	for _, k := range sortedKeys(d.SetLabels) {
+		msgs = append(msgs, fmt.Sprintf("label %s=%s is missing", k, d.SetLabels[k]))
+	}
+	for _, k := range d.RemoveLabels {
+		msgs = append(msgs, fmt.Sprintf("label %s is removed from the config", k))
+	}
+	for _, t := range d.SetTaints {
+		msgs = append(msgs, fmt.Sprintf("taint %s is missing", t))
+	}
+	for _, t := range d.RemoveTaints {
+		msgs = append(msgs, fmt.Sprintf("taint %s:%s is removed from the config", t.Key, t.Effect))
+	}
+	return msgs
+}
+
+// Commands returns the kubectl commands to reconcile the node.
+func (d *NodeDrift) Commands() []string {
+	var cmds []string
+	if len(d.SetLabels) != 0 || len(d.RemoveLabels) != 0 {
+		args := make([]string, 0, len(d.SetLabels)+len(d.RemoveLabels))
+		for _, k := range sortedKeys(d.SetLabels) {
+			args = append(args, fmt.Sprintf("%s=%s", k, d.SetLabels[k]))
+		}
+		for _, k := range d.RemoveLabels {
+			args = append(args, k+"-")
+		}
+		cmds = append(cmds, fmt.Sprintf("/usr/local/bin/kubectl label --overwrite node %s %s", d.Node, strings.Join(args, " ")))
+	}
+	if len(d.SetTaints) != 0 || len(d.RemoveTaints) != 0 {
+		args := make([]string, 0, len(d.SetTaints)+len(d.RemoveTaints))
+		for _, t := range d.SetTaints {
+			args = append(args, t.String())
+		}
+		for _, t := range d.RemoveTaints {
+			args = append(args, taintID(t.Key, t.Effect)+"-")
+		}
+		cmds = append(cmds, fmt.Sprintf("/usr/local/bin/kubectl taint --overwrite node %s %s", d.Node, strings.Join(args, " ")))
+	}
+	if d.Annotate {
+		cmds = append(cmds, fmt.Sprintf("/usr/local/bin/kubectl annotate --overwrite node %s %s %s", d.Node,
+			annotationArg(ManagedLabelsAnnotation, d.ManagedLabels), annotationArg(ManagedTaintsAnnotation, d.ManagedTaints)))
+	}
+	return cmds
+}
+
+func annotationArg(key, value string) string {
+	if value == "" {
+		return key + "-"
+	}
+	return fmt.Sprintf("%s=%s", key, value)
+}
+
+func taintID(key, effect string) string {
+	return fmt.Sprintf("%s:%s", key, effect)
+}
+
+func splitAnnotation(value string) []string {
+	if value == "" {
+		return nil
+	}
+	return strings.Split(value, ",")
+}
+
+// KubeletNodeLabels returns the value of the kubelet --node-labels flag. The labels under the kubernetes.io
+// and k8s.io namespaces which kubelet is not allowed to set, such as node-role.kubernetes.io/*, are left out,
+// they are applied by kubectl after the node joins.
+func KubeletNodeLabels(labels map[string]string) string {
+	args := make([]string, 0, len(labels))
+	for _, k := range sortedKeys(labels) {
+		if isKubeletLabel(k) {
+			args = append(args, fmt.Sprintf("%s=%s", k, labels[k]))
+		}
+	}
+	return strings.Join(args, ",")
+}
+
+// KubeletNodeTaints returns the value of the kubelet --register-with-taints flag.
+func KubeletNodeTaints(taints []kubekeyv1alpha2.Taint) string {
+	args := make([]string, 0, len(taints))
+	for _, t := range taints {
+		args = append(args, t.String())
+	}
+	return strings.Join(args, ",")
+}
+
+func isKubeletLabel(key string) bool {
+	i := strings.Index(key, "/")
+	if i < 0 {
+		return true
+	}
+	namespace := key[:i]
+	if !isKubernetesNamespace(namespace, "kubernetes.io") && !isKubernetesNamespace(namespace, "k8s.io") {
+		return true
+	}
+	for _, ns := range kubeletLabelNamespaces {
+		if isKubernetesNamespace(namespace, ns) {
+			return true
+		}
+	}
+	_, ok := kubeletLabels[key]
+	return ok
+}
+
+func isKubernetesNamespace(namespace, domain string) bool {
+	return namespace == domain || strings.HasSuffix(namespace, "."+domain)
+}
+
+type ReconcileNodeLabelsAndTaints struct {
+	common.KubeAction
+	CheckOnly bool
+}
+
+func (r *ReconcileNodeLabelsAndTaints) Execute(runtime connector.Runtime) error {
+	drifted := 0
+	for _, host := range runtime.GetHostsByRole(common.K8s) {
+		var labels map[string]string
+		var taints []kubekeyv1alpha2.Taint
+		if cfg := r.KubeConf.Cluster.FindHost(host.GetName()); cfg != nil {
+			labels, taints = cfg.Labels, cfg.Taints
+		}
+
+		output, err := runtime.GetRunner().SudoCmd(
+			fmt.Sprintf("/usr/local/bin/kubectl get node %s -o json", host.GetName()), false)
+		if err != nil {
+			if r.CheckOnly {
+				return errors.Wrapf(errors.WithStack(err), "get node %s failed", host.GetName())
+			}
+			logger.Log.Warningf("skip reconciling the labels and taints of %s, it is not found in the cluster", host.GetName())
+			continue
+		}
+		node := new(corev1.Node)
+		if err := json.Unmarshal([]byte(output), node); err != nil {
+			return errors.Wrapf(errors.WithStack(err), "parse node %s failed", host.GetName())
+		}
+
+		drift := CalculateNodeDrift(node, labels, taints)
+		if drift.IsDrifted() {
+			drifted++
+			for _, msg := range drift.Messages() {
+				logger.Log.Messagef(host.GetName(), "%s", msg)
+			}
+		}
+		if r.CheckOnly {
+			continue
+		}
+		for _, cmd := range drift.Commands() {
+			if _, err := runtime.GetRunner().SudoCmd(cmd, false); err != nil {
+				return errors.Wrapf(errors.WithStack(err), "reconcile the labels and taints of %s failed", host.GetName())
+			}
+		}
+	}
+
+	if r.CheckOnly && drifted != 0 {
+		return errors.Errorf("the labels or taints of %d node(s) drifted from the config", drifted)
+	}
+	return nil
+}
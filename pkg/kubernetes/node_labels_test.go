@@ -0,0 +1,134 @@
+/*
+ Copyright 2021 The KubeSphere Authors.
+
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+     http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package kubernetes
+
+import (
+	"reflect"
+	"testing"
+
+	kubekeyv1alpha2 "github.com/kubesphere/kubekey/apis/kubekey/v1alpha2"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestCalculateNodeDrift(t *testing.T) {
+	node := &corev1.Node{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: "node1",
+			Labels: map[string]string{
+				"kubernetes.io/hostname": "node1",
+				"gpu":                    "false",
+				"storage":                "ssd",
+				"team":                   "infra",
+			},
+			Annotations: map[string]string{
+				ManagedLabelsAnnotation: "gpu,storage",
+				ManagedTaintsAnnotation: "dedicated:NoSchedule,storage:NoSchedule",
+			},
+		},
+		Spec: corev1.NodeSpec{
+			Taints: []corev1.Taint{
+				{Key: "dedicated", Value: "gpu", Effect: corev1.TaintEffectNoSchedule},
+				{Key: "storage", Effect: corev1.TaintEffectNoSchedule},
+				{Key: "node.kubernetes.io/unreachable", Effect: corev1.TaintEffectNoExecute},
+			},
+		},
+	}
+	labels := map[string]string{"gpu": "true", "zone": "a"}
+	taints := []kubekeyv1alpha2.Taint{
+		{Key: "dedicated", Value: "gpu", Effect: "NoSchedule"},
+		{Key: "gpu", Effect: "PreferNoSchedule"},
+	}
+
+	d := CalculateNodeDrift(node, labels, taints)
+	if !d.IsDrifted() {
+		t.Fatal("expected the node to be drifted")
+	}
+	if want := map[string]string{"gpu": "true", "zone": "a"}; !reflect.DeepEqual(d.SetLabels, want) {
+		t.Errorf("SetLabels = %v, want %v", d.SetLabels, want)
+	}
+	// team is not managed by kubekey, it is kept.
+	if want := []string{"storage"}; !reflect.DeepEqual(d.RemoveLabels, want) {
+		t.Errorf("RemoveLabels = %v, want %v", d.RemoveLabels, want)
+	}
+	if want := []kubekeyv1alpha2.Taint{{Key: "gpu", Effect: "PreferNoSchedule"}}; !reflect.DeepEqual(d.SetTaints, want) {
+		t.Errorf("SetTaints = %v, want %v", d.SetTaints, want)
+	}
+	if want := []kubekeyv1alpha2.Taint{{Key: "storage", Effect: "NoSchedule"}}; !reflect.DeepEqual(d.RemoveTaints, want) {
+		t.Errorf("RemoveTaints = %v, want %v", d.RemoveTaints, want)
+	}
+
+	want := []string{
+		"/usr/local/bin/kubectl label --overwrite node node1 gpu=true zone=a storage-",
+		"/usr/local/bin/kubectl taint --overwrite node node1 gpu:PreferNoSchedule storage:NoSchedule-",
+		"/usr/local/bin/kubectl annotate --overwrite node node1 kubekey.kubesphere.io/managed-labels=gpu,zone " +
+			"kubekey.kubesphere.io/managed-taints=dedicated:NoSchedule,gpu:PreferNoSchedule",
+	}
+	if got := d.Commands(); !reflect.DeepEqual(got, want) {
+		t.Errorf("Commands() = %v, want %v", got, want)
+	}
+
+	// After the commands are applied, there is nothing to do.
+	node.Labels = map[string]string{"gpu": "true", "zone": "a", "team": "infra"}
+	node.Annotations = map[string]string{
+		ManagedLabelsAnnotation: "gpu,zone",
+		ManagedTaintsAnnotation: "dedicated:NoSchedule,gpu:PreferNoSchedule",
+	}
+	node.Spec.Taints = []corev1.Taint{
+		{Key: "dedicated", Value: "gpu", Effect: corev1.TaintEffectNoSchedule},
+		{Key: "gpu", Effect: corev1.TaintEffectPreferNoSchedule},
+	}
+	d = CalculateNodeDrift(node, labels, taints)
+	if d.IsDrifted() || len(d.Commands()) != 0 {
+		t.Errorf("expected no drift, got %v", d.Messages())
+	}
+
+	// Removing all the labels and taints from the config removes the annotations as well.
+	d = CalculateNodeDrift(node, nil, nil)
+	want = []string{
+		"/usr/local/bin/kubectl label --overwrite node node1 gpu- zone-",
+		"/usr/local/bin/kubectl taint --overwrite node node1 dedicated:NoSchedule- gpu:PreferNoSchedule-",
+		"/usr/local/bin/kubectl annotate --overwrite node node1 kubekey.kubesphere.io/managed-labels- kubekey.kubesphere.io/managed-taints-",
+	}
+	if got := d.Commands(); !reflect.DeepEqual(got, want) {
+		t.Errorf("Commands() = %v, want %v", got, want)
+	}
+}
+
+func TestKubeletNodeLabels(t *testing.T) {
+	labels := map[string]string{
+		"gpu":                             "true",
+		"example.com/rack":                "r1",
+		"node-role.kubernetes.io/gpu":     "",
+		"topology.kubernetes.io/zone":     "a",
+		"node.kubernetes.io/exclude":      "true",
+		"custom.k8s.io/disk":              "ssd",
+		"kubelet.kubernetes.io/something": "x",
+	}
+	want := "example.com/rack=r1,gpu=true,kubelet.kubernetes.io/something=x,node.kubernetes.io/exclude=true,topology.kubernetes.io/zone=a"
+	if got := KubeletNodeLabels(labels); got != want {
+		t.Errorf("KubeletNodeLabels() = %q, want %q", got, want)
+	}
+
+	taints := []kubekeyv1alpha2.Taint{
+		{Key: "dedicated", Value: "gpu", Effect: "NoSchedule"},
+		{Key: "storage", Effect: "NoExecute"},
+	}
+	if got, want := KubeletNodeTaints(taints), "dedicated=gpu:NoSchedule,storage:NoExecute"; got != want {
+		t.Errorf("KubeletNodeTaints() = %q, want %q", got, want)
+	}
+}
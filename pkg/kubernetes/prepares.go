@@ -47,15 +47,10 @@ func (n *NodeInCluster) PreCheck(runtime connector.Runtime) (bool, error) {
 	host := runtime.RemoteHost()
 	if v, ok := n.PipelineCache.Get(common.ClusterStatus); ok {
 		cluster := v.(*KubernetesStatus)
-		var versionOk bool
-		if res, ok := cluster.NodesInfo[host.GetName()]; ok && res != "" {
-			versionOk = true
-		}
-		_, ipOk := cluster.NodesInfo[host.GetInternalAddress()]
 		if n.Not {
-			return !(versionOk || ipOk), nil
+			return !cluster.HasNode(host), nil
 		}
-		return versionOk || ipOk, nil
+		return cluster.HasNode(host), nil
 	} else {
 		return false, errors.New("get kubernetes cluster status by pipeline cache failed")
 	}
@@ -130,3 +125,41 @@ func (n *NotEqualDesiredVersion) PreCheck(runtime connector.Runtime) (bool, erro
 	}
 	return true, nil
 }
+
+// NewMasterExists checks if there are masters which have not joined the cluster yet.
+type NewMasterExists struct {
+	common.KubePrepare
+}
+
+func (n *NewMasterExists) PreCheck(runtime connector.Runtime) (bool, error) {
+	v, ok := n.PipelineCache.Get(common.ClusterStatus)
+	if !ok {
+		return false, errors.New("get kubernetes cluster status by pipeline cache failed")
+	}
+	cluster := v.(*KubernetesStatus)
+	for _, host := range runtime.GetHostsByRole(common.Master) {
+		if !cluster.HasNode(host) {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// FirstMasterInCluster matches the first master which has joined the cluster.
+type FirstMasterInCluster struct {
+	common.KubePrepare
+}
+
+func (f *FirstMasterInCluster) PreCheck(runtime connector.Runtime) (bool, error) {
+	v, ok := f.PipelineCache.Get(common.ClusterStatus)
+	if !ok {
+		return false, errors.New("get kubernetes cluster status by pipeline cache failed")
+	}
+	cluster := v.(*KubernetesStatus)
+	for _, host := range runtime.GetHostsByRole(common.Master) {
+		if cluster.HasNode(host) {
+			return host.GetName() == runtime.RemoteHost().GetName(), nil
+		}
+	}
+	return false, nil
+}
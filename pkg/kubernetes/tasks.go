@@ -18,11 +18,13 @@ package kubernetes
 
 import (
 	"context"
+	"crypto/x509"
 	"encoding/base64"
+	"encoding/pem"
 	"fmt"
+	"net"
 	"os"
 	"path/filepath"
-	"sort"
 	"strings"
 	"time"
 
@@ -34,10 +36,12 @@ import (
 	"github.com/kubesphere/kubekey/pkg/core/prepare"
 	"github.com/kubesphere/kubekey/pkg/core/task"
 	"github.com/kubesphere/kubekey/pkg/core/util"
+	"github.com/kubesphere/kubekey/pkg/etcd"
 	"github.com/kubesphere/kubekey/pkg/files"
 	"github.com/kubesphere/kubekey/pkg/images"
 	"github.com/kubesphere/kubekey/pkg/kubernetes/templates"
 	"github.com/kubesphere/kubekey/pkg/kubernetes/templates/v1beta2"
+	"github.com/kubesphere/kubekey/pkg/kubernetes/templates/v1beta3"
 	"github.com/kubesphere/kubekey/pkg/plugins/dns"
 	dnsTemplates "github.com/kubesphere/kubekey/pkg/plugins/dns/templates"
 	"github.com/kubesphere/kubekey/pkg/utils"
@@ -45,6 +49,7 @@ import (
 	corev1 "k8s.io/api/core/v1"
 	kubeerrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/validation"
 	versionutil "k8s.io/apimachinery/pkg/util/version"
 	kube "k8s.io/client-go/kubernetes"
 	"k8s.io/client-go/tools/clientcmd"
@@ -186,7 +191,7 @@ func (g *GenerateKubeletEnv) Execute(runtime connector.Runtime) error {
 		Template: templates.KubeletEnv,
 		Dst:      filepath.Join("/etc/systemd/system/kubelet.service.d", templates.KubeletEnv.Name()),
 		Data: util.Data{
-			"NodeIP":           host.GetInternalAddress(),
+			"NodeIP":           nodeIP(g.KubeConf, host),
 			"Hostname":         host.GetName(),
 			"ContainerRuntime": "",
 		},
@@ -199,6 +204,22 @@ func (g *GenerateKubeletEnv) Execute(runtime connector.Runtime) error {
 	return nil
 }
 
+// nodeIP returns the node-ip of the kubelet, which contains both the IPv4 and IPv6 addresses on the dual-stack cluster.
+func nodeIP(kubeConf *common.KubeConf, host connector.Host) string {
+	if hostCfg := kubeConf.Cluster.FindHost(host.GetName()); hostCfg != nil && kubeConf.Cluster.Network.IsDualStack() {
+		return strings.Join(hostCfg.InternalAddresses(), ",")
+	}
+	return host.GetInternalAddress()
+}
+
+// nodeCidrMaskSize returns the mask size of the primary pod CIDR allocated to each node.
+func nodeCidrMaskSize(kubeConf *common.KubeConf) int {
+	if kubeConf.Cluster.Network.IsIPv6Only() {
+		return kubeConf.Cluster.Kubernetes.NodeCidrMaskSizeIPv6
+	}
+	return kubeConf.Cluster.Kubernetes.NodeCidrMaskSize
+}
+
 type GenerateKubeadmConfig struct {
 	common.KubeAction
 	IsInitConfiguration bool
@@ -214,27 +235,41 @@ func (g *GenerateKubeadmConfig) Execute(runtime connector.Runtime) error {
 			return errors.Wrap(errors.WithStack(err), "scp local kubeadm config failed")
 		}
 	} else {
-		// generate etcd configuration
+		// generate etcd configuration, the local etcd of kubeadm needs nothing more than the image
 		var externalEtcd kubekeyv1alpha2.ExternalEtcd
-		var endpointsList []string
-		var caFile, certFile, keyFile string
+		if g.KubeConf.Cluster.Etcd.IsExternal() {
+			externalEtcd = etcd.ExternalEtcd(g.KubeConf.Cluster)
+		} else if g.KubeConf.Cluster.Etcd.IsKubeKey() {
+			var endpointsList []string
+			var caFile, certFile, keyFile string
+
+			for _, host := range runtime.GetHostsByRole(common.ETCD) {
+				endpoint := fmt.Sprintf("https://%s", net.JoinHostPort(host.GetInternalAddress(), kubekeyv1alpha2.DefaultEtcdPort))
+				endpointsList = append(endpointsList, endpoint)
+			}
+			externalEtcd.Endpoints = endpointsList
 
-		for _, host := range runtime.GetHostsByRole(common.ETCD) {
-			endpoint := fmt.Sprintf("https://%s:%s", host.GetInternalAddress(), kubekeyv1alpha2.DefaultEtcdPort)
-			endpointsList = append(endpointsList, endpoint)
-		}
-		externalEtcd.Endpoints = endpointsList
+			caFile = "/etc/ssl/etcd/ssl/ca.pem"
+			certFile = fmt.Sprintf("/etc/ssl/etcd/ssl/node-%s.pem", host.GetName())
+			keyFile = fmt.Sprintf("/etc/ssl/etcd/ssl/node-%s-key.pem", host.GetName())
 
-		caFile = "/etc/ssl/etcd/ssl/ca.pem"
-		certFile = fmt.Sprintf("/etc/ssl/etcd/ssl/node-%s.pem", host.GetName())
-		keyFile = fmt.Sprintf("/etc/ssl/etcd/ssl/node-%s-key.pem", host.GetName())
+			externalEtcd.CaFile = caFile
+			externalEtcd.CertFile = certFile
+			externalEtcd.KeyFile = keyFile
+		}
 
-		externalEtcd.CaFile = caFile
-		externalEtcd.CertFile = certFile
-		externalEtcd.KeyFile = keyFile
+		// kubeadm prefers the v1beta3 configuration since v1.22, and the v1beta2 is removed in later releases
+		kubeadmConfig := v1beta2.KubeadmConfig
+		controllerManagerArgs := v1beta2.ControllermanagerArgs
+		criSock := g.KubeConf.Cluster.Kubernetes.ContainerRuntimeEndpoint
+		if v1beta3.Supported(g.KubeConf.Cluster.Kubernetes.Version) {
+			kubeadmConfig = v1beta3.KubeadmConfig
+			controllerManagerArgs = v1beta3.ControllermanagerArgs
+			criSock = v1beta3.CriSocket(criSock)
+		}
 
 		_, ApiServerArgs := util.GetArgs(v1beta2.ApiServerArgs, g.KubeConf.Cluster.Kubernetes.ApiServerArgs)
-		_, ControllerManagerArgs := util.GetArgs(v1beta2.ControllermanagerArgs, g.KubeConf.Cluster.Kubernetes.ControllerManagerArgs)
+		_, ControllerManagerArgs := util.GetArgs(controllerManagerArgs, g.KubeConf.Cluster.Kubernetes.ControllerManagerArgs)
 		_, SchedulerArgs := util.GetArgs(v1beta2.SchedulerArgs, g.KubeConf.Cluster.Kubernetes.SchedulerArgs)
 
 		checkCgroupDriver, err := v1beta2.GetKubeletCgroupDriver(runtime, g.KubeConf)
@@ -256,9 +291,15 @@ func (g *GenerateKubeadmConfig) Execute(runtime connector.Runtime) error {
 			}
 		}
 
+		var nodeLabels, nodeTaints string
+		if hostCfg := g.KubeConf.Cluster.FindHost(host.GetName()); hostCfg != nil {
+			nodeLabels = KubeletNodeLabels(hostCfg.Labels)
+			nodeTaints = KubeletNodeTaints(hostCfg.Taints)
+		}
+
 		templateAction := action.Template{
-			Template: v1beta2.KubeadmConfig,
-			Dst:      filepath.Join(common.KubeConfigDir, v1beta2.KubeadmConfig.Name()),
+			Template: kubeadmConfig,
+			Dst:      filepath.Join(common.KubeConfigDir, kubeadmConfig.Name()),
 			Data: util.Data{
 				"IsInitCluster":          g.IsInitConfiguration,
 				"ImageRepo":              strings.TrimSuffix(images.GetImage(runtime, g.KubeConf, "kube-apiserver").ImageRepo(), "/kube-apiserver"),
@@ -270,12 +311,17 @@ func (g *GenerateKubeadmConfig) Execute(runtime connector.Runtime) error {
 				"AdvertiseAddress":       host.GetInternalAddress(),
 				"ControlPlanPort":        g.KubeConf.Cluster.ControlPlaneEndpoint.Port,
 				"ControlPlaneEndpoint":   fmt.Sprintf("%s:%d", g.KubeConf.Cluster.ControlPlaneEndpoint.Domain, g.KubeConf.Cluster.ControlPlaneEndpoint.Port),
-				"PodSubnet":              g.KubeConf.Cluster.Network.KubePodsCIDR,
-				"ServiceSubnet":          g.KubeConf.Cluster.Network.KubeServiceCIDR,
+				"PodSubnet":              strings.Join(g.KubeConf.Cluster.Network.PodCIDRs(), ","),
+				"ServiceSubnet":          strings.Join(g.KubeConf.Cluster.Network.ServiceCIDRs(), ","),
 				"CertSANs":               g.KubeConf.Cluster.GenerateCertSANs(),
+				"LocalEtcd":              g.KubeConf.Cluster.Etcd.IsKubeadm(),
+				"EtcdRepo":               strings.TrimSuffix(images.GetImage(runtime, g.KubeConf, "etcd").ImageRepo(), "/etcd"),
+				"EtcdTag":                images.GetImage(runtime, g.KubeConf, "etcd").Tag,
 				"ExternalEtcd":           externalEtcd,
-				"NodeCidrMaskSize":       g.KubeConf.Cluster.Kubernetes.NodeCidrMaskSize,
-				"CriSock":                g.KubeConf.Cluster.Kubernetes.ContainerRuntimeEndpoint,
+				"NodeCidrMaskSize":       nodeCidrMaskSize(g.KubeConf),
+				"NodeCidrMaskSizeIPv6":   g.KubeConf.Cluster.Kubernetes.NodeCidrMaskSizeIPv6,
+				"DualStack":              g.KubeConf.Cluster.Network.IsDualStack(),
+				"CriSock":                criSock,
 				"ApiServerArgs":          v1beta2.UpdateFeatureGatesConfiguration(ApiServerArgs, g.KubeConf),
 				"ControllerManagerArgs":  v1beta2.UpdateFeatureGatesConfiguration(ControllerManagerArgs, g.KubeConf),
 				"SchedulerArgs":          v1beta2.UpdateFeatureGatesConfiguration(SchedulerArgs, g.KubeConf),
@@ -285,6 +331,8 @@ func (g *GenerateKubeadmConfig) Execute(runtime connector.Runtime) error {
 				"CgroupDriver":           checkCgroupDriver,
 				"BootstrapToken":         bootstrapToken,
 				"CertificateKey":         certificateKey,
+				"NodeLabels":             nodeLabels,
+				"NodeTaints":             nodeTaints,
 			},
 		}
 
@@ -301,20 +349,38 @@ type KubeadmInit struct {
 }
 
 func (k *KubeadmInit) Execute(runtime connector.Runtime) error {
-	if _, err := runtime.GetRunner().SudoCmd("/usr/local/bin/kubeadm init "+
-		"--config=/etc/kubernetes/kubeadm-config.yaml "+
-		"--ignore-preflight-errors=FileExisting-crictl", true); err != nil {
+	initCmd := "/usr/local/bin/kubeadm init " +
+		"--config=/etc/kubernetes/kubeadm-config.yaml " +
+		"--ignore-preflight-errors=FileExisting-crictl"
+	if k.KubeConf.Cluster.ControlPlaneEndpoint.IsInternalLBEnabledVip() {
+		initCmd = initCmd + "," + kubevipPreflightError
+	}
+	if _, err := runtime.GetRunner().SudoCmd(initCmd, true); err != nil {
 		// kubeadm reset and then retry
-		resetCmd := "/usr/local/bin/kubeadm reset -f"
-		if k.KubeConf.Cluster.Kubernetes.ContainerRuntimeEndpoint != "" {
-			resetCmd = resetCmd + " --cri-socket " + k.KubeConf.Cluster.Kubernetes.ContainerRuntimeEndpoint
-		}
-		_, _ = runtime.GetRunner().SudoCmd(resetCmd, true)
+		_, _ = runtime.GetRunner().SudoCmd(kubeadmResetCmd(k.KubeConf), true)
 		return errors.Wrap(errors.WithStack(err), "init kubernetes cluster failed")
 	}
 	return nil
 }
 
+// kubevipPreflightError is ignored because the kube-vip manifest is generated before the kubeadm init or join.
+const kubevipPreflightError = "DirAvailable--etc-kubernetes-manifests"
+
+// kubeadmResetCmd returns the command to clean up the node after a failed kubeadm init or join.
+// The kube-vip manifest is kept, otherwise the retry would run without the VIP.
+func kubeadmResetCmd(kubeConf *common.KubeConf) string {
+	resetCmd := "/usr/local/bin/kubeadm reset -f"
+	if kubeConf.Cluster.Kubernetes.ContainerRuntimeEndpoint != "" {
+		resetCmd = resetCmd + " --cri-socket " + kubeConf.Cluster.Kubernetes.ContainerRuntimeEndpoint
+	}
+	if !kubeConf.Cluster.ControlPlaneEndpoint.IsInternalLBEnabledVip() {
+		return resetCmd
+	}
+	manifest := filepath.Join(common.KubeManifestDir, "kube-vip.yaml")
+	backup := filepath.Join(common.TmpDir, "kube-vip.yaml")
+	return fmt.Sprintf("cp -f %s %s; %s; mkdir -p %s && mv -f %s %s", manifest, backup, resetCmd, common.KubeManifestDir, backup, manifest)
+}
+
 type CopyKubeConfigForControlPlane struct {
 	common.KubeAction
 }
@@ -385,13 +451,12 @@ type JoinNode struct {
 }
 
 func (j *JoinNode) Execute(runtime connector.Runtime) error {
-	if _, err := runtime.GetRunner().SudoCmd("/usr/local/bin/kubeadm join --config=/etc/kubernetes/kubeadm-config.yaml",
-		true); err != nil {
-		resetCmd := "/usr/local/bin/kubeadm reset -f"
-		if j.KubeConf.Cluster.Kubernetes.ContainerRuntimeEndpoint != "" {
-			resetCmd = resetCmd + " --cri-socket " + j.KubeConf.Cluster.Kubernetes.ContainerRuntimeEndpoint
-		}
-		_, _ = runtime.GetRunner().SudoCmd(resetCmd, true)
+	joinCmd := "/usr/local/bin/kubeadm join --config=/etc/kubernetes/kubeadm-config.yaml"
+	if j.KubeConf.Cluster.ControlPlaneEndpoint.IsInternalLBEnabledVip() {
+		joinCmd = joinCmd + " --ignore-preflight-errors=" + kubevipPreflightError
+	}
+	if _, err := runtime.GetRunner().SudoCmd(joinCmd, true); err != nil {
+		_, _ = runtime.GetRunner().SudoCmd(kubeadmResetCmd(j.KubeConf), true)
 		return errors.Wrap(errors.WithStack(err), "join node failed")
 	}
 	return nil
@@ -446,54 +511,13 @@ func (k *KubeadmReset) Execute(runtime connector.Runtime) error {
 	return nil
 }
 
-type FindDifferences struct {
-	common.KubeAction
-}
-
-func (f *FindDifferences) Execute(runtime connector.Runtime) error {
-	var node string
-	var resArr []string
-	res, err := runtime.GetRunner().Cmd(
-		"sudo -E /usr/local/bin/kubectl get nodes | grep -v NAME | grep -v 'master' | awk '{print $1}'",
-		true)
-	if err != nil {
-		return errors.Wrap(errors.WithStack(err), "kubectl get nodes failed")
-	}
-
-	if !strings.Contains(res, "\r\n") {
-		resArr = append(resArr, res)
-	} else {
-		resArr = strings.Split(res, "\r\n")
-	}
-
-	var workerNameStr string
-	for j := 0; j < len(runtime.GetHostsByRole(common.Worker)); j++ {
-		workerNameStr += runtime.GetHostsByRole(common.Worker)[j].GetName()
-	}
-	for i := 0; i < len(resArr); i++ {
-		if strings.Contains(workerNameStr, resArr[i]) {
-			continue
-		} else {
-			node = resArr[i]
-			break
-		}
-	}
-
-	f.PipelineCache.Set("dstNode", node)
-	return nil
-}
-
 type DrainNode struct {
 	common.KubeAction
 }
 
 func (d *DrainNode) Execute(runtime connector.Runtime) error {
-	nodeName, ok := d.PipelineCache.Get("dstNode")
-	if !ok {
-		return errors.New("get dstNode failed by pipeline cache")
-	}
 	if _, err := runtime.GetRunner().SudoCmd(fmt.Sprintf(
-		"/usr/local/bin/kubectl drain %s --delete-emptydir-data --ignore-daemonsets", nodeName),
+		"/usr/local/bin/kubectl drain %s --delete-emptydir-data --ignore-daemonsets", d.KubeConf.Arg.NodeName),
 		true); err != nil {
 		return errors.Wrap(err, "drain the node failed")
 	}
@@ -505,12 +529,8 @@ type KubectlDeleteNode struct {
 }
 
 func (k *KubectlDeleteNode) Execute(runtime connector.Runtime) error {
-	nodeName, ok := k.PipelineCache.Get("dstNode")
-	if !ok {
-		return errors.New("get dstNode failed by pipeline cache")
-	}
 	if _, err := runtime.GetRunner().SudoCmd(fmt.Sprintf(
-		"/usr/local/bin/kubectl delete node %s", nodeName),
+		"/usr/local/bin/kubectl delete node %s", k.KubeConf.Arg.NodeName),
 		true); err != nil {
 		return errors.Wrap(err, "delete the node failed")
 	}
@@ -522,7 +542,7 @@ type SetUpgradePlan struct {
 	Step UpgradeStep
 }
 
-func (s *SetUpgradePlan) Execute(_ connector.Runtime) error {
+func (s *SetUpgradePlan) Execute(runtime connector.Runtime) error {
 	currentVersion, ok := s.PipelineCache.GetMustString(common.K8sVersion)
 	if !ok {
 		return errors.New("get current Kubernetes version failed by pipeline cache")
@@ -542,16 +562,34 @@ func (s *SetUpgradePlan) Execute(_ connector.Runtime) error {
 		os.Exit(0)
 	}
 
-	if s.Step == ToV121 {
-		v122 := versionutil.MustParseSemantic("v1.22.0")
-		atLeast := versionutil.MustParseSemantic(desiredVersion).AtLeast(v122)
-		cmp, err := versionutil.MustParseSemantic(currentVersion).Compare("v1.21.5")
-		if err != nil {
-			return err
+	hops, err := CalculateUpgradeHops(currentVersion, desiredVersion, SupportedUpgradeVersions())
+	if err != nil {
+		return err
+	}
+
+	if s.Step == ToKubeSphereCompatible {
+		apiservers, kubelets := nodesK8sVersion(runtime)
+		if err := ValidateVersionSkew(apiservers, kubelets, hops); err != nil {
+			return errors.Wrap(err, "the upgrade plan violates the version skew policy")
 		}
-		if atLeast && cmp <= 0 {
-			desiredVersion = "v1.21.5"
+		if s.KubeConf.Cluster.Etcd.IsKubeKey() {
+			if err := ValidateETCDSkew(nodesETCDVersion(runtime), hops); err != nil {
+				return errors.Wrap(err, "the upgrade plan violates the etcd upgrade policy")
+			}
+		}
+		if err := s.validateChecksums(hops); err != nil {
+			return errors.Wrap(err, "the upgrade plan contains unverifiable binaries")
+		}
+		if len(hops) > 0 {
+			logger.Log.Messagef(common.LocalHost, "Upgrade plan: %s -> %s", currentVersion, strings.Join(hops, " -> "))
+		}
+
+		installedKs, _ := s.PipelineCache.GetMustString(common.KubeSphereVersion)
+		desiredKs := ""
+		if s.KubeConf.Cluster.KubeSphere.Enabled {
+			desiredKs = s.KubeConf.Cluster.KubeSphere.Version
 		}
+		desiredVersion = KubeSphereCompatibleVersion(currentVersion, hops, installedKs, desiredKs)
 	}
 
 	s.PipelineCache.Set(common.PlanK8sVersion, desiredVersion)
@@ -571,38 +609,62 @@ func (c *CalculateNextVersion) Execute(_ connector.Runtime) error {
 	if !ok {
 		return errors.New("get upgrade plan Kubernetes version failed by pipeline cache")
 	}
-	nextVersionStr := calculateNextStr(currentVersion, planVersion)
-	c.KubeConf.Cluster.Kubernetes.Version = nextVersionStr
+	hops, err := CalculateUpgradeHops(currentVersion, planVersion, SupportedUpgradeVersions())
+	if err != nil {
+		return err
+	}
+	if len(hops) == 0 {
+		return errors.Errorf("no version to upgrade from %s to %s", currentVersion, planVersion)
+	}
+	c.KubeConf.Cluster.Kubernetes.Version = hops[0]
 	return nil
 }
 
-func calculateNextStr(currentVersion, desiredVersion string) string {
-	current := versionutil.MustParseSemantic(currentVersion)
-	target := versionutil.MustParseSemantic(desiredVersion)
-	var nextVersionMinor uint
-	if target.Minor() == current.Minor() {
-		nextVersionMinor = current.Minor()
-	} else {
-		nextVersionMinor = current.Minor() + 1
+func (s *SetUpgradePlan) validateChecksums(hops []string) error {
+	c := s.KubeConf.Cluster.Checksums
+	sources, err := files.NewChecksumSources(c.File, !c.DisableUpstream)
+	if err != nil {
+		return err
 	}
-
-	if nextVersionMinor == target.Minor() {
-		return desiredVersion
-	} else {
-		nextVersionPatchList := make([]int, 0)
-		for supportVersionStr := range files.FileSha256["kubeadm"]["amd64"] {
-			supportVersion := versionutil.MustParseSemantic(supportVersionStr)
-			if supportVersion.Minor() == nextVersionMinor {
-				nextVersionPatchList = append(nextVersionPatchList, int(supportVersion.Patch()))
-			}
+	archMap := make(map[string]bool)
+	var arches []string
+	for _, host := range s.KubeConf.Cluster.Hosts {
+		if !archMap[host.Arch] {
+			archMap[host.Arch] = true
+			arches = append(arches, host.Arch)
 		}
-		sort.Ints(nextVersionPatchList)
+	}
+	return ValidateUpgradeChecksums(hops, arches, sources, s.KubeConf.Arg.DownloadCommand)
+}
 
-		nextVersion := current.WithMinor(nextVersionMinor)
-		nextVersion = nextVersion.WithPatch(uint(nextVersionPatchList[len(nextVersionPatchList)-1]))
+// nodesETCDVersion returns the etcd versions of the etcd nodes, which are collected by the upgrade plan.
+func nodesETCDVersion(runtime connector.Runtime) map[string]string {
+	versions := make(map[string]string)
+	for _, host := range runtime.GetHostsByRole(common.ETCD) {
+		if version, ok := host.GetCache().GetMustString(common.ETCDVersion); ok {
+			versions[host.GetName()] = version
+		}
+	}
+	return versions
+}
 
-		return fmt.Sprintf("v%s", nextVersion.String())
+// nodesK8sVersion returns the kube-apiserver versions of the masters and the kubelet versions of all the nodes,
+// including the masters, which are collected by the cluster pre-check.
+func nodesK8sVersion(runtime connector.Runtime) (map[string]string, map[string]string) {
+	apiservers := make(map[string]string)
+	kubelets := make(map[string]string)
+	for _, host := range runtime.GetHostsByRole(common.K8s) {
+		if version, ok := host.GetCache().GetMustString(common.KubeletVersion); ok {
+			kubelets[host.GetName()] = version
+		}
+		if !host.IsRole(common.Master) {
+			continue
+		}
+		if version, ok := host.GetCache().GetMustString(common.NodeK8sVersion); ok {
+			apiservers[host.GetName()] = version
+		}
 	}
+	return apiservers, kubelets
 }
 
 type UpgradeKubeMaster struct {
@@ -716,10 +778,10 @@ func (k *KubeadmUpgrade) Execute(runtime connector.Runtime) error {
 			"--ignore-preflight-errors=all "+
 			"--allow-experimental-upgrades "+
 			"--allow-release-candidate-upgrades "+
-			"--etcd-upgrade=false "+
+			"--etcd-upgrade=%t "+
 			"--certificate-renewal=true "+
 			"--force",
-		k.KubeConf.Cluster.Kubernetes.Version), false); err != nil {
+		k.KubeConf.Cluster.Kubernetes.Version, k.KubeConf.Cluster.Etcd.IsKubeadm()), false); err != nil {
 		return errors.Wrap(errors.WithStack(err), fmt.Sprintf("upgrade master failed: %s", host.GetName()))
 	}
 
@@ -985,7 +1047,7 @@ func (s *SaveKubeConfig) Execute(runtime connector.Runtime) error {
 	}
 
 	oldServer := fmt.Sprintf("https://%s:%d", s.KubeConf.Cluster.ControlPlaneEndpoint.Domain, s.KubeConf.Cluster.ControlPlaneEndpoint.Port)
-	newServer := fmt.Sprintf("https://%s:%d", clusterPublicAddress, s.KubeConf.Cluster.ControlPlaneEndpoint.Port)
+	newServer := fmt.Sprintf("https://%s:%d", util.URLHost(clusterPublicAddress), s.KubeConf.Cluster.ControlPlaneEndpoint.Port)
 	newKubeConfigStr := strings.Replace(kubeConfigStr, oldServer, newServer, -1)
 	kubeConfigBase64 := base64.StdEncoding.EncodeToString([]byte(newKubeConfigStr))
 
@@ -1050,3 +1112,140 @@ func (s *SaveKubeConfig) Execute(runtime connector.Runtime) error {
 	}
 	return nil
 }
+
+type CheckCertSANs struct {
+	common.KubeAction
+}
+
+func (c *CheckCertSANs) Execute(runtime connector.Runtime) error {
+	host := runtime.RemoteHost()
+
+	desired := append(c.KubeConf.Cluster.GenerateCertSANs(), host.GetName(), host.GetInternalAddress())
+	var invalid []string
+	for _, san := range desired {
+		if san == "" || net.ParseIP(san) != nil {
+			continue
+		}
+		validate := validation.IsDNS1123Subdomain
+		if strings.HasPrefix(san, "*.") {
+			validate = validation.IsWildcardDNS1123Subdomain
+		}
+		if errs := validate(san); len(errs) != 0 {
+			invalid = append(invalid, fmt.Sprintf("%s (%s)", san, strings.Join(errs, "; ")))
+		}
+	}
+	if len(invalid) != 0 {
+		return errors.Errorf("invalid apiserver cert SANs: %s", strings.Join(invalid, ", "))
+	}
+
+	output, err := runtime.GetRunner().SudoCmd("cat /etc/kubernetes/pki/apiserver.crt", false)
+	if err != nil {
+		return errors.Wrap(errors.WithStack(err), "read the apiserver cert failed")
+	}
+	block, _ := pem.Decode([]byte(output))
+	if block == nil {
+		return errors.New("decode the apiserver cert failed")
+	}
+	cert, err := x509.ParseCertificate(block.Bytes)
+	if err != nil {
+		return errors.Wrap(errors.WithStack(err), "parse the apiserver cert failed")
+	}
+
+	current := make(map[string]struct{})
+	for _, name := range cert.DNSNames {
+		current[name] = struct{}{}
+	}
+	for _, ip := range cert.IPAddresses {
+		current[ip.String()] = struct{}{}
+	}
+
+	var missing []string
+	for _, san := range desired {
+		if san == "" {
+			continue
+		}
+		if ip := net.ParseIP(san); ip != nil {
+			san = ip.String()
+		}
+		if _, ok := current[san]; !ok {
+			current[san] = struct{}{}
+			missing = append(missing, san)
+		}
+	}
+
+	if len(missing) != 0 {
+		logger.Log.Messagef(host.GetName(), "the SANs %s will be added to the apiserver cert", strings.Join(missing, ", "))
+	}
+	// type: []string
+	host.GetCache().Set(common.MissingCertSANs, missing)
+	return nil
+}
+
+type UploadKubeadmConfig struct {
+	common.KubeAction
+}
+
+func (u *UploadKubeadmConfig) Execute(runtime connector.Runtime) error {
+	if _, err := runtime.GetRunner().SudoCmd(
+		"/usr/local/bin/kubeadm init phase upload-config kubeadm --config /etc/kubernetes/kubeadm-config.yaml", true); err != nil {
+		return errors.Wrap(errors.WithStack(err), "upload kubeadm config failed")
+	}
+	return nil
+}
+
+type RenewAPIServerCert struct {
+	common.KubeAction
+}
+
+func (r *RenewAPIServerCert) Execute(runtime connector.Runtime) error {
+	host := runtime.RemoteHost()
+	if _, ok := host.GetCache().Get(common.MissingCertSANs); !ok {
+		check := &CheckCertSANs{KubeAction: r.KubeAction}
+		if err := check.Execute(runtime); err != nil {
+			return err
+		}
+	}
+	if missing, ok := host.GetCache().Get(common.MissingCertSANs); !ok || len(missing.([]string)) == 0 {
+		return nil
+	}
+
+	var sans []string
+	for _, san := range r.KubeConf.Cluster.GenerateCertSANs() {
+		if san != "" {
+			sans = append(sans, san)
+		}
+	}
+	renewCmds := []string{
+		"mv -f /etc/kubernetes/pki/apiserver.crt /etc/kubernetes/pki/apiserver.crt.bak",
+		"mv -f /etc/kubernetes/pki/apiserver.key /etc/kubernetes/pki/apiserver.key.bak",
+		fmt.Sprintf("/usr/local/bin/kubeadm init phase certs apiserver "+
+			"--apiserver-advertise-address=%s --control-plane-endpoint=%s:%d "+
+			"--service-cidr=%s --service-dns-domain=%s --apiserver-cert-extra-sans=%s",
+			host.GetInternalAddress(), r.KubeConf.Cluster.ControlPlaneEndpoint.Domain, r.KubeConf.Cluster.ControlPlaneEndpoint.Port,
+			r.KubeConf.Cluster.Network.KubeServiceCIDR, r.KubeConf.Cluster.Kubernetes.DNSDomain, strings.Join(sans, ",")),
+	}
+	if _, err := runtime.GetRunner().SudoCmd(strings.Join(renewCmds, " && "), true); err != nil {
+		_, _ = runtime.GetRunner().SudoCmd("mv -f /etc/kubernetes/pki/apiserver.crt.bak /etc/kubernetes/pki/apiserver.crt && "+
+			"mv -f /etc/kubernetes/pki/apiserver.key.bak /etc/kubernetes/pki/apiserver.key", false)
+		return errors.Wrap(errors.WithStack(err), "renew the apiserver cert failed")
+	}
+
+	// kubelet recreates the kube-apiserver static pod with the new cert.
+	restartCmd := "crictl pods --namespace kube-system --name kube-apiserver -q | xargs --no-run-if-empty crictl rmp -f"
+	if r.KubeConf.Cluster.Kubernetes.ContainerManager == "" || r.KubeConf.Cluster.Kubernetes.ContainerManager == common.Docker {
+		restartCmd = "docker ps -af name=k8s_kube-apiserver* -q | xargs --no-run-if-empty docker rm -f"
+	}
+	if _, err := runtime.GetRunner().SudoCmd(restartCmd, false); err != nil {
+		return errors.Wrap(errors.WithStack(err), "restart kube-apiserver failed")
+	}
+
+	healthCheckCmd := fmt.Sprintf("/usr/local/bin/kubectl --kubeconfig /etc/kubernetes/admin.conf --server https://127.0.0.1:%d get --raw=/healthz",
+		r.KubeConf.Cluster.ControlPlaneEndpoint.Port)
+	for i := 0; i < 30; i++ {
+		if _, err := runtime.GetRunner().SudoCmd(healthCheckCmd, false); err == nil {
+			return nil
+		}
+		time.Sleep(10 * time.Second)
+	}
+	return errors.Errorf("kube-apiserver on %s is not healthy after the cert is renewed", host.GetName())
+}
@@ -31,7 +31,7 @@ import (
 )
 
 var (
-	funcMap = template.FuncMap{"toYaml": toYAML, "indent": Indent}
+	funcMap = template.FuncMap{"toYaml": ToYAML, "indent": Indent}
 	// KubeadmConfig defines the template of kubeadm configuration file.
 	KubeadmConfig = template.Must(template.New("kubeadm-config.yaml").Funcs(funcMap).Parse(
 		dedent.Dedent(`
@@ -40,6 +40,12 @@ var (
 apiVersion: kubeadm.k8s.io/v1beta2
 kind: ClusterConfiguration
 etcd:
+{{- if .LocalEtcd }}
+  local:
+    imageRepository: {{ .EtcdRepo }}
+    imageTag: {{ .EtcdTag }}
+    dataDir: /var/lib/etcd
+{{- else }}
   external:
     endpoints:
     {{- range .ExternalEtcd.Endpoints }}
@@ -48,6 +54,7 @@ etcd:
     caFile: {{ .ExternalEtcd.CaFile }}
     certFile: {{ .ExternalEtcd.CertFile }}
     keyFile: {{ .ExternalEtcd.KeyFile }}
+{{- end }}
 dns:
   type: CoreDNS
   imageRepository: {{ .CorednsRepo }}
@@ -70,7 +77,12 @@ apiServer:
     {{- end }}
 controllerManager:
   extraArgs:
+{{- if .DualStack }}
+    node-cidr-mask-size-ipv4: "{{ .NodeCidrMaskSize }}"
+    node-cidr-mask-size-ipv6: "{{ .NodeCidrMaskSizeIPv6 }}"
+{{- else }}
     node-cidr-mask-size: "{{ .NodeCidrMaskSize }}"
+{{- end }}
 {{ toYaml .ControllerManagerArgs | indent 4 }}
   extraVolumes:
   - name: host-time
@@ -93,6 +105,12 @@ nodeRegistration:
 {{- end }}
   kubeletExtraArgs:
     cgroup-driver: {{ .CgroupDriver }}
+{{- if .NodeLabels }}
+    node-labels: "{{ .NodeLabels }}"
+{{- end }}
+{{- if .NodeTaints }}
+    register-with-taints: "{{ .NodeTaints }}"
+{{- end }}
 ---
 apiVersion: kubeproxy.config.k8s.io/v1alpha1
 kind: KubeProxyConfiguration
@@ -125,6 +143,12 @@ nodeRegistration:
 {{- end }}
   kubeletExtraArgs:
     cgroup-driver: {{ .CgroupDriver }}
+{{- if .NodeLabels }}
+    node-labels: "{{ .NodeLabels }}"
+{{- end }}
+{{- if .NodeTaints }}
+    register-with-taints: "{{ .NodeTaints }}"
+{{- end }}
 
 {{- end }}
     `)))
@@ -293,7 +317,7 @@ func GetKubeletCgroupDriver(runtime connector.Runtime, kubeConf *common.KubeConf
 
 func GetKubeProxyConfiguration(kubeConf *common.KubeConf) map[string]interface{} {
 	defaultKubeProxyConfiguration := map[string]interface{}{
-		"clusterCIDR": kubeConf.Cluster.Network.KubePodsCIDR,
+		"clusterCIDR": strings.Join(kubeConf.Cluster.Network.PodCIDRs(), ","),
 		"mode":        kubeConf.Cluster.Kubernetes.ProxyMode,
 		"iptables": map[string]interface{}{
 			"masqueradeAll": kubeConf.Cluster.Kubernetes.MasqueradeAll,
@@ -333,7 +357,7 @@ func GetKubeProxyConfiguration(kubeConf *common.KubeConf) map[string]interface{}
 	return kubeProxyConfiguration
 }
 
-func toYAML(v interface{}) string {
+func ToYAML(v interface{}) string {
 	data, err := yaml.Marshal(v)
 	if err != nil {
 		// Swallow errors inside of a template.
@@ -0,0 +1,181 @@
+/*
+ Copyright 2021 The KubeSphere Authors.
+
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+     http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package v1beta3
+
+import (
+	"strings"
+	"text/template"
+
+	"github.com/kubesphere/kubekey/pkg/kubernetes/templates/v1beta2"
+	"github.com/lithammer/dedent"
+	versionutil "k8s.io/apimachinery/pkg/util/version"
+)
+
+// MinKubernetesVersion is the first Kubernetes version whose kubeadm supports the v1beta3 configuration.
+const MinKubernetesVersion = "v1.22.0"
+
+var (
+	funcMap = template.FuncMap{"toYaml": v1beta2.ToYAML, "indent": v1beta2.Indent}
+	// KubeadmConfig defines the template of kubeadm configuration file.
+	// Compared with v1beta2, dns.type is removed, the cgroup driver is only set in the KubeletConfiguration and
+	// the criSocket is a URL.
+	KubeadmConfig = template.Must(template.New("kubeadm-config.yaml").Funcs(funcMap).Parse(
+		dedent.Dedent(`
+{{- if .IsInitCluster -}}
+---
+apiVersion: kubeadm.k8s.io/v1beta3
+kind: ClusterConfiguration
+etcd:
+{{- if .LocalEtcd }}
+  local:
+    imageRepository: {{ .EtcdRepo }}
+    imageTag: {{ .EtcdTag }}
+    dataDir: /var/lib/etcd
+{{- else }}
+  external:
+    endpoints:
+    {{- range .ExternalEtcd.Endpoints }}
+    - {{ . }}
+    {{- end }}
+    caFile: {{ .ExternalEtcd.CaFile }}
+    certFile: {{ .ExternalEtcd.CertFile }}
+    keyFile: {{ .ExternalEtcd.KeyFile }}
+{{- end }}
+dns:
+  imageRepository: {{ .CorednsRepo }}
+  imageTag: {{ .CorednsTag }}
+imageRepository: {{ .ImageRepo }}
+kubernetesVersion: {{ .Version }}
+certificatesDir: /etc/kubernetes/pki
+clusterName: {{ .ClusterName }}
+controlPlaneEndpoint: {{ .ControlPlaneEndpoint }}
+networking:
+  dnsDomain: {{ .DNSDomain }}
+  podSubnet: {{ .PodSubnet }}
+  serviceSubnet: {{ .ServiceSubnet }}
+apiServer:
+  extraArgs:
+{{ toYaml .ApiServerArgs | indent 4}}
+  certSANs:
+    {{- range .CertSANs }}
+    - {{ . }}
+    {{- end }}
+controllerManager:
+  extraArgs:
+{{- if .DualStack }}
+    node-cidr-mask-size-ipv4: "{{ .NodeCidrMaskSize }}"
+    node-cidr-mask-size-ipv6: "{{ .NodeCidrMaskSizeIPv6 }}"
+{{- else }}
+    node-cidr-mask-size: "{{ .NodeCidrMaskSize }}"
+{{- end }}
+{{ toYaml .ControllerManagerArgs | indent 4 }}
+  extraVolumes:
+  - name: host-time
+    hostPath: /etc/localtime
+    mountPath: /etc/localtime
+    readOnly: true
+scheduler:
+  extraArgs:
+{{ toYaml .SchedulerArgs | indent 4 }}
+
+---
+apiVersion: kubeadm.k8s.io/v1beta3
+kind: InitConfiguration
+localAPIEndpoint:
+  advertiseAddress: {{ .AdvertiseAddress }}
+  bindPort: {{ .ControlPlanPort }}
+nodeRegistration:
+{{- if .CriSock }}
+  criSocket: {{ .CriSock }}
+{{- end }}
+{{- if or .NodeLabels .NodeTaints }}
+  kubeletExtraArgs:
+{{- if .NodeLabels }}
+    node-labels: "{{ .NodeLabels }}"
+{{- end }}
+{{- if .NodeTaints }}
+    register-with-taints: "{{ .NodeTaints }}"
+{{- end }}
+{{- end }}
+---
+apiVersion: kubeproxy.config.k8s.io/v1alpha1
+kind: KubeProxyConfiguration
+{{ toYaml .KubeProxyConfiguration }}
+---
+apiVersion: kubelet.config.k8s.io/v1beta1
+kind: KubeletConfiguration
+{{ toYaml .KubeletConfiguration }}
+
+{{- else -}}
+---
+apiVersion: kubeadm.k8s.io/v1beta3
+kind: JoinConfiguration
+discovery:
+  bootstrapToken:
+    apiServerEndpoint: {{ .ControlPlaneEndpoint }}
+    token: "{{ .BootstrapToken }}"
+    unsafeSkipCAVerification: true
+  tlsBootstrapToken: "{{ .BootstrapToken }}"
+{{- if .IsControlPlane }}
+controlPlane:
+  localAPIEndpoint:
+    advertiseAddress: {{ .AdvertiseAddress }}
+    bindPort: {{ .ControlPlanPort }}
+  certificateKey: {{ .CertificateKey }}
+{{- end }}
+nodeRegistration:
+{{- if .CriSock }}
+  criSocket: {{ .CriSock }}
+{{- end }}
+{{- if or .NodeLabels .NodeTaints }}
+  kubeletExtraArgs:
+{{- if .NodeLabels }}
+    node-labels: "{{ .NodeLabels }}"
+{{- end }}
+{{- if .NodeTaints }}
+    register-with-taints: "{{ .NodeTaints }}"
+{{- end }}
+{{- end }}
+
+{{- end }}
+    `)))
+)
+
+var (
+	// ControllermanagerArgs replaces the experimental-cluster-signing-duration which is removed since v1.25.
+	ControllermanagerArgs = map[string]string{
+		"bind-address":             "0.0.0.0",
+		"cluster-signing-duration": "87600h",
+	}
+)
+
+// Supported reports whether the kubeadm of the Kubernetes version uses the v1beta3 configuration.
+func Supported(version string) bool {
+	v, err := versionutil.ParseSemantic(version)
+	if err != nil {
+		return false
+	}
+	return v.AtLeast(versionutil.MustParseSemantic(MinKubernetesVersion))
+}
+
+// CriSocket returns the criSocket in the URL form which is required by v1beta3.
+func CriSocket(endpoint string) string {
+	if endpoint == "" || strings.Contains(endpoint, "://") {
+		return endpoint
+	}
+	return "unix://" + endpoint
+}
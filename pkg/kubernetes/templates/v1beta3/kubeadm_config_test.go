@@ -0,0 +1,124 @@
+/*
+ Copyright 2021 The KubeSphere Authors.
+
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+     http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package v1beta3
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func TestSupported(t *testing.T) {
+	for version, want := range map[string]bool{
+		"v1.21.5": false,
+		"v1.22.1": true,
+		"v1.23.0": true,
+		"invalid": false,
+	} {
+		if got := Supported(version); got != want {
+			t.Errorf("Supported(%s) = %v, want %v", version, got, want)
+		}
+	}
+}
+
+func TestCriSocket(t *testing.T) {
+	for endpoint, want := range map[string]string{
+		"":                                       "",
+		"/run/containerd/containerd.sock":        "unix:///run/containerd/containerd.sock",
+		"unix:///var/run/crio/crio.sock":         "unix:///var/run/crio/crio.sock",
+		"npipe:////./pipe/containerd-containerd": "npipe:////./pipe/containerd-containerd",
+	} {
+		if got := CriSocket(endpoint); got != want {
+			t.Errorf("CriSocket(%s) = %s, want %s", endpoint, got, want)
+		}
+	}
+}
+
+func TestKubeadmInitConfig(t *testing.T) {
+	var buf bytes.Buffer
+	err := KubeadmConfig.Execute(&buf, map[string]interface{}{
+		"IsInitCluster":          true,
+		"LocalEtcd":              true,
+		"EtcdRepo":               "kubesphere",
+		"EtcdTag":                "3.5.0-0",
+		"CorednsRepo":            "coredns",
+		"CorednsTag":             "1.8.0",
+		"ImageRepo":              "kubesphere",
+		"Version":                "v1.22.1",
+		"ClusterName":            "cluster.local",
+		"ControlPlaneEndpoint":   "lb.kubesphere.local:6443",
+		"DNSDomain":              "cluster.local",
+		"PodSubnet":              "10.233.64.0/18",
+		"ServiceSubnet":          "10.233.0.0/18",
+		"ApiServerArgs":          map[string]string{"bind-address": "0.0.0.0"},
+		"CertSANs":               []string{"lb.kubesphere.local", "172.16.0.2"},
+		"NodeCidrMaskSize":       24,
+		"ControllerManagerArgs":  ControllermanagerArgs,
+		"SchedulerArgs":          map[string]string{"bind-address": "0.0.0.0"},
+		"AdvertiseAddress":       "172.16.0.2",
+		"ControlPlanPort":        6443,
+		"CriSock":                "unix:///run/containerd/containerd.sock",
+		"KubeProxyConfiguration": map[string]interface{}{"mode": "ipvs"},
+		"KubeletConfiguration":   map[string]interface{}{"cgroupDriver": "systemd"},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	config := buf.String()
+	for _, want := range []string{
+		"apiVersion: kubeadm.k8s.io/v1beta3",
+		"kind: ClusterConfiguration",
+		"kind: InitConfiguration",
+		"kubernetesVersion: v1.22.1",
+		"imageTag: 3.5.0-0",
+		"node-cidr-mask-size: \"24\"",
+		"criSocket: unix:///run/containerd/containerd.sock",
+		"cgroupDriver: systemd",
+	} {
+		if !strings.Contains(config, want) {
+			t.Errorf("%q is not found in the init configuration:\n%s", want, config)
+		}
+	}
+	for _, unwanted := range []string{"type: CoreDNS", "kind: JoinConfiguration", "cgroup-driver"} {
+		if strings.Contains(config, unwanted) {
+			t.Errorf("%q should not be in the init configuration:\n%s", unwanted, config)
+		}
+	}
+}
+
+func TestKubeadmConfig(t *testing.T) {
+	var buf bytes.Buffer
+	err := KubeadmConfig.Execute(&buf, map[string]interface{}{
+		"IsInitCluster":  false,
+		"IsControlPlane": true,
+		"CriSock":        "unix:///run/containerd/containerd.sock",
+		"BootstrapToken": "abcdef.0123456789abcdef",
+		"CertificateKey": "key",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	config := buf.String()
+	for _, want := range []string{"apiVersion: kubeadm.k8s.io/v1beta3", "kind: JoinConfiguration", "criSocket: unix:///run/containerd/containerd.sock"} {
+		if !strings.Contains(config, want) {
+			t.Errorf("%q is not found in the join configuration:\n%s", want, config)
+		}
+	}
+	if strings.Contains(config, "cgroup-driver") {
+		t.Errorf("the cgroup driver should not be set by the kubelet flag:\n%s", config)
+	}
+}
@@ -0,0 +1,234 @@
+/*
+ Copyright 2021 The KubeSphere Authors.
+
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+     http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package kubernetes
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/kubesphere/kubekey/pkg/etcd"
+	"github.com/kubesphere/kubekey/pkg/files"
+	"github.com/kubesphere/kubekey/pkg/version/kubesphere"
+	"github.com/pkg/errors"
+	versionutil "k8s.io/apimachinery/pkg/util/version"
+)
+
+// MaxKubeletSkew is the number of minor versions the kubelet is allowed to be older than the kube-apiserver.
+const MaxKubeletSkew = 2
+
+// SupportedUpgradeVersions returns all the versions whose binaries are known by kk.
+func SupportedUpgradeVersions() []string {
+	versions := make([]string, 0, len(files.FileSha256["kubeadm"]["amd64"]))
+	for v := range files.FileSha256["kubeadm"]["amd64"] {
+		versions = append(versions, v)
+	}
+	return versions
+}
+
+// CalculateUpgradeHops returns the versions the cluster goes through from the current version to the desired one.
+// kubeadm only upgrades one minor version at a time, so every intermediate minor version uses its latest supported
+// patch release. The desired version is always the last hop, and no hop is returned if they are the same.
+func CalculateUpgradeHops(currentVersion, desiredVersion string, supported []string) ([]string, error) {
+	current, err := versionutil.ParseSemantic(currentVersion)
+	if err != nil {
+		return nil, errors.Wrapf(err, "parse the current version %s failed", currentVersion)
+	}
+	desired, err := versionutil.ParseSemantic(desiredVersion)
+	if err != nil {
+		return nil, errors.Wrapf(err, "parse the desired version %s failed", desiredVersion)
+	}
+	if current.Major() != desired.Major() {
+		return nil, errors.Errorf("upgrade across major versions (%s to %s) is not supported", currentVersion, desiredVersion)
+	}
+	if desired.LessThan(current) {
+		return nil, errors.Errorf("the desired version %s is lower than the current version %s", desiredVersion, currentVersion)
+	}
+
+	latestPatch := make(map[uint]*versionutil.Version)
+	for _, s := range supported {
+		v, err := versionutil.ParseSemantic(s)
+		if err != nil || v.Major() != current.Major() {
+			continue
+		}
+		if latest, ok := latestPatch[v.Minor()]; !ok || latest.LessThan(v) {
+			latestPatch[v.Minor()] = v
+		}
+	}
+
+	hops := make([]string, 0)
+	for minor := current.Minor() + 1; minor < desired.Minor(); minor++ {
+		v, ok := latestPatch[minor]
+		if !ok {
+			return nil, errors.Errorf("no supported release of v%d.%d is found to upgrade from %s to %s",
+				current.Major(), minor, currentVersion, desiredVersion)
+		}
+		hops = append(hops, fmt.Sprintf("v%s", v))
+	}
+	if current.LessThan(desired) {
+		hops = append(hops, desiredVersion)
+	}
+	return hops, nil
+}
+
+// ValidateVersionSkew checks the version skew policy against the versions of the kube-apiservers and the kubelets
+// (both keyed by node name) before the upgrade, and at every hop: the kube-apiserver is upgraded first, the kubelets
+// are upgraded to the same hop afterwards, so they must never be newer than the kube-apiserver of the hop or older
+// than MaxKubeletSkew minor versions.
+func ValidateVersionSkew(apiservers, kubelets map[string]string, hops []string) error {
+	if len(apiservers) == 0 {
+		return errors.New("no kube-apiserver version is found")
+	}
+
+	var oldestAPIServer, newestAPIServer *versionutil.Version
+	for _, name := range sortedKeys(apiservers) {
+		v, err := versionutil.ParseSemantic(apiservers[name])
+		if err != nil {
+			return errors.Wrapf(err, "parse the kube-apiserver version of the node %s failed", name)
+		}
+		if oldestAPIServer == nil || v.LessThan(oldestAPIServer) {
+			oldestAPIServer = v
+		}
+		if newestAPIServer == nil || newestAPIServer.LessThan(v) {
+			newestAPIServer = v
+		}
+	}
+	if newestAPIServer.Minor()-oldestAPIServer.Minor() > 1 {
+		return errors.Errorf("the kube-apiservers are more than one minor version apart (v%s and v%s)", oldestAPIServer, newestAPIServer)
+	}
+
+	var oldestKubelet *versionutil.Version
+	for _, name := range sortedKeys(kubelets) {
+		v, err := versionutil.ParseSemantic(kubelets[name])
+		if err != nil {
+			return errors.Wrapf(err, "parse the kubelet version of the node %s failed", name)
+		}
+		if v.Minor() > oldestAPIServer.Minor() {
+			return errors.Errorf("the kubelet v%s of the node %s is newer than the kube-apiserver v%s", v, name, oldestAPIServer)
+		}
+		if oldestKubelet == nil || v.LessThan(oldestKubelet) {
+			oldestKubelet = v
+		}
+	}
+	if oldestKubelet == nil {
+		oldestKubelet = oldestAPIServer
+	}
+
+	for _, hop := range hops {
+		v, err := versionutil.ParseSemantic(hop)
+		if err != nil {
+			return errors.Wrapf(err, "parse the upgrade version %s failed", hop)
+		}
+		if v.Minor() < newestAPIServer.Minor() {
+			return errors.Errorf("the kube-apiserver v%s can not be downgraded to %s", newestAPIServer, hop)
+		}
+		if v.Minor()-oldestKubelet.Minor() > MaxKubeletSkew {
+			return errors.Errorf("the kubelet v%s is more than %d minor versions older than the kube-apiserver %s",
+				oldestKubelet, MaxKubeletSkew, hop)
+		}
+		oldestKubelet = v
+	}
+	return nil
+}
+
+// ValidateETCDSkew checks that the etcd deployed by kk (keyed by node name) can be upgraded along with the hops:
+// etcd is upgraded to the version every hop requires one minor version at a time, it is never downgraded.
+func ValidateETCDSkew(etcds map[string]string, hops []string) error {
+	for _, name := range sortedKeys(etcds) {
+		current, err := versionutil.ParseSemantic(etcds[name])
+		if err != nil {
+			return errors.Wrapf(err, "parse the etcd version of the node %s failed", name)
+		}
+		for _, hop := range hops {
+			required := etcd.RequiredVersion(hop)
+			if required == "" {
+				continue
+			}
+			v := versionutil.MustParseSemantic(required)
+			if !current.LessThan(v) {
+				continue
+			}
+			if v.Major() != current.Major() || v.Minor() > current.Minor()+1 {
+				return errors.Errorf("the etcd v%s of the node %s can not be upgraded to %s for Kubernetes %s, "+
+					"etcd is upgraded one minor version at a time", current, name, required, hop)
+			}
+			current = v
+		}
+	}
+	return nil
+}
+
+// upgradeBinaries are the binaries which are downloaded at every hop with the version of the hop.
+var upgradeBinaries = []string{"kubeadm", "kubelet", "kubectl"}
+
+// ValidateUpgradeChecksums checks that the checksum sources know the binaries of every hop for all the
+// architectures of the cluster, so the upgrade does not stop half way at a hop whose binaries can not be verified.
+func ValidateUpgradeChecksums(hops, arches []string, sources []files.ChecksumSource, getCmd func(path, url string) string) error {
+	var missing []string
+	for _, hop := range hops {
+		for _, arch := range arches {
+			for _, name := range upgradeBinaries {
+				b := files.NewKubeBinary(name, arch, hop, "", getCmd)
+				b.ChecksumSources = sources
+				sum, err := b.ExpectedSha256()
+				if err != nil {
+					return err
+				}
+				if sum == "" {
+					missing = append(missing, fmt.Sprintf("%s %s %s", name, hop, arch))
+				}
+			}
+		}
+	}
+	if len(missing) > 0 {
+		return errors.Errorf("no SHA256 is known for %s, add them to the checksums file of the cluster config",
+			strings.Join(missing, ", "))
+	}
+	return nil
+}
+
+// KubeSphereCompatibleVersion returns the last version of the upgrade chain which is still supported by the installed
+// KubeSphere, KubeSphere has to be upgraded there before the cluster moves on to the versions it does not support.
+// The desired version is returned if KubeSphere is not going to be upgraded or the installed version is unknown.
+func KubeSphereCompatibleVersion(currentVersion string, hops []string, installedKs, desiredKs string) string {
+	if len(hops) == 0 {
+		return currentVersion
+	}
+	desiredVersion := hops[len(hops)-1]
+	installer, ok := kubesphere.VersionMap[installedKs]
+	if !ok || installedKs == desiredKs || desiredKs == "" {
+		return desiredVersion
+	}
+
+	compatible := currentVersion
+	for _, hop := range hops {
+		if !installer.K8sSupport(hop) {
+			break
+		}
+		compatible = hop
+	}
+	return compatible
+}
+
+func sortedKeys(m map[string]string) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
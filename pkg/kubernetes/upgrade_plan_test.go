@@ -0,0 +1,140 @@
+/*
+ Copyright 2021 The KubeSphere Authors.
+
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+     http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package kubernetes
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/kubesphere/kubekey/pkg/files"
+)
+
+var supportedVersions = []string{"v1.19.8", "v1.19.9", "v1.20.4", "v1.20.10", "v1.21.4", "v1.21.5", "v1.22.1"}
+
+func TestCalculateUpgradeHops(t *testing.T) {
+	tests := []struct {
+		current string
+		desired string
+		want    []string
+		wantErr bool
+	}{
+		{current: "v1.19.8", desired: "v1.22.1", want: []string{"v1.20.10", "v1.21.5", "v1.22.1"}},
+		{current: "v1.21.4", desired: "v1.21.5", want: []string{"v1.21.5"}},
+		{current: "v1.20.4", desired: "v1.21.4", want: []string{"v1.21.4"}},
+		{current: "v1.22.1", desired: "v1.22.1", want: []string{}},
+		{current: "v1.18.8", desired: "v1.21.5", want: []string{"v1.19.9", "v1.20.10", "v1.21.5"}},
+		{current: "v1.17.9", desired: "v1.19.8", wantErr: true},
+		{current: "v1.21.5", desired: "v1.20.4", wantErr: true},
+	}
+	for _, tt := range tests {
+		got, err := CalculateUpgradeHops(tt.current, tt.desired, supportedVersions)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("%s -> %s: unexpected error %v", tt.current, tt.desired, err)
+			continue
+		}
+		if !tt.wantErr && !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("%s -> %s: got %v, want %v", tt.current, tt.desired, got, tt.want)
+		}
+	}
+}
+
+func TestValidateVersionSkew(t *testing.T) {
+	apiservers := map[string]string{"master1": "v1.20.4", "master2": "v1.20.4"}
+	hops := []string{"v1.21.5", "v1.22.1"}
+
+	if err := ValidateVersionSkew(apiservers, map[string]string{"node1": "v1.20.4", "node2": "v1.19.8"}, hops); err != nil {
+		t.Errorf("the kubelets within the skew should pass: %v", err)
+	}
+	if err := ValidateVersionSkew(apiservers, map[string]string{"node1": "v1.18.8"}, hops); err == nil {
+		t.Errorf("the kubelet v1.18.8 is three minor versions older than the kube-apiserver v1.21.5")
+	}
+	if err := ValidateVersionSkew(apiservers, map[string]string{"node1": "v1.21.5"}, hops); err == nil {
+		t.Errorf("the kubelet should not be newer than the kube-apiserver")
+	}
+	if err := ValidateVersionSkew(apiservers, map[string]string{"master1": "v1.18.8", "node1": "v1.20.4"}, hops); err == nil {
+		t.Errorf("the kubelet of the master should be checked as well")
+	}
+	if err := ValidateVersionSkew(map[string]string{"master1": "v1.22.1"}, nil, hops); err == nil {
+		t.Errorf("the kube-apiserver should not be downgraded")
+	}
+}
+
+func TestValidateETCDSkew(t *testing.T) {
+	hops := []string{"v1.17.9", "v1.18.8"}
+
+	if err := ValidateETCDSkew(map[string]string{"node1": "v3.3.12", "node2": "v3.4.13"}, hops); err != nil {
+		t.Errorf("etcd v3.3 should be upgraded to v3.4: %v", err)
+	}
+	if err := ValidateETCDSkew(map[string]string{"node1": "v3.2.24"}, hops); err == nil {
+		t.Errorf("etcd v3.2 can not be upgraded to v3.4 at once")
+	}
+	if err := ValidateETCDSkew(map[string]string{"node1": "v3.2.24"}, []string{"v1.16.15"}); err != nil {
+		t.Errorf("Kubernetes v1.16 does not upgrade etcd: %v", err)
+	}
+	if err := ValidateETCDSkew(map[string]string{"node1": "v3.5.0"}, hops); err != nil {
+		t.Errorf("etcd should not be downgraded: %v", err)
+	}
+}
+
+type fakeChecksums map[string]string
+
+func (f fakeChecksums) Sha256(b *files.KubeBinary) (string, error) {
+	return f[b.ID+" "+b.Version+" "+b.Arch], nil
+}
+
+func (f fakeChecksums) Verify(_ *files.KubeBinary) error {
+	return nil
+}
+
+func (f fakeChecksums) String() string {
+	return "fake checksums"
+}
+
+func TestValidateUpgradeChecksums(t *testing.T) {
+	sources := []files.ChecksumSource{fakeChecksums{
+		"kubeadm v1.21.5 amd64": "a", "kubelet v1.21.5 amd64": "b", "kubectl v1.21.5 amd64": "c",
+		"kubeadm v1.22.1 amd64": "d", "kubelet v1.22.1 amd64": "e", "kubectl v1.22.1 amd64": "f",
+		"kubeadm v1.21.5 arm64": "g", "kubelet v1.21.5 arm64": "h",
+	}}
+
+	if err := ValidateUpgradeChecksums([]string{"v1.21.5", "v1.22.1"}, []string{"amd64"}, sources, nil); err != nil {
+		t.Errorf("all the binaries are known: %v", err)
+	}
+	err := ValidateUpgradeChecksums([]string{"v1.21.5", "v1.22.1"}, []string{"amd64", "arm64"}, sources, nil)
+	if err == nil {
+		t.Fatalf("the arm64 binaries are missing")
+	}
+	for _, missing := range []string{"kubectl v1.21.5 arm64", "kubeadm v1.22.1 arm64"} {
+		if !strings.Contains(err.Error(), missing) {
+			t.Errorf("%s should be reported: %v", missing, err)
+		}
+	}
+}
+
+func TestKubeSphereCompatibleVersion(t *testing.T) {
+	hops := []string{"v1.20.10", "v1.21.5", "v1.22.1"}
+	if got := KubeSphereCompatibleVersion("v1.19.8", hops, "v3.1.1", "v3.2.1"); got != "v1.20.10" {
+		t.Errorf("KubeSphere v3.1.1 supports up to v1.20, got %s", got)
+	}
+	if got := KubeSphereCompatibleVersion("v1.19.8", hops, "v3.2.0", "v3.2.0"); got != "v1.22.1" {
+		t.Errorf("KubeSphere is not upgraded, got %s", got)
+	}
+	if got := KubeSphereCompatibleVersion("v1.19.8", hops, "", "v3.2.1"); got != "v1.22.1" {
+		t.Errorf("KubeSphere is not installed, got %s", got)
+	}
+}
@@ -19,21 +19,23 @@ package kubernetes
 type UpgradeStep int
 
 const (
-	ToV121 UpgradeStep = iota + 1
-	ToV122
+	// ToKubeSphereCompatible upgrades the cluster to the last version which is supported by the installed KubeSphere.
+	ToKubeSphereCompatible UpgradeStep = iota + 1
+	// ToDesired upgrades the cluster to the desired version after KubeSphere is upgraded.
+	ToDesired
 )
 
 var UpgradeStepList = []UpgradeStep{
-	ToV121,
-	ToV122,
+	ToKubeSphereCompatible,
+	ToDesired,
 }
 
 func (u UpgradeStep) String() string {
 	switch u {
-	case ToV121:
-		return "to v1.21"
-	case ToV122:
-		return "to v1.22"
+	case ToKubeSphereCompatible:
+		return "to the version supported by KubeSphere"
+	case ToDesired:
+		return "to the desired version"
 	default:
 		return "invalid option"
 	}
@@ -23,6 +23,7 @@ import (
 	"github.com/kubesphere/kubekey/pkg/common"
 	"github.com/kubesphere/kubekey/pkg/core/connector"
 	"github.com/kubesphere/kubekey/pkg/core/logger"
+	"github.com/kubesphere/kubekey/pkg/etcd"
 	ksv2 "github.com/kubesphere/kubekey/pkg/kubesphere/v2"
 	ksv3 "github.com/kubesphere/kubekey/pkg/kubesphere/v3"
 	"github.com/kubesphere/kubekey/pkg/version/kubesphere"
@@ -80,8 +81,16 @@ func (s *Setup) Execute(runtime connector.Runtime) error {
 	filePath := filepath.Join(common.KubeAddonsDir, templates.KsInstaller.Name())
 
 	var addrList []string
-	for _, host := range runtime.GetHostsByRole(common.ETCD) {
-		addrList = append(addrList, host.GetInternalAddress())
+	if s.KubeConf.Cluster.Etcd.IsExternal() {
+		addrList = etcd.ExternalEndpointHosts(s.KubeConf.Cluster)
+	} else if s.KubeConf.Cluster.Etcd.IsKubeadm() {
+		for _, host := range runtime.GetHostsByRole(common.Master) {
+			addrList = append(addrList, host.GetInternalAddress())
+		}
+	} else {
+		for _, host := range runtime.GetHostsByRole(common.ETCD) {
+			addrList = append(addrList, host.GetInternalAddress())
+		}
 	}
 	etcdEndPoint := strings.Join(addrList, ",")
 	if _, err := runtime.GetRunner().SudoCmd(
@@ -130,9 +139,19 @@ func (s *Setup) Execute(runtime connector.Runtime) error {
 				s.KubeConf.Cluster.Kubernetes.ContainerManager, s.KubeConf.Cluster.Kubernetes.ContainerManager))
 	}
 
-	caFile := "/etc/ssl/etcd/ssl/ca.pem"
-	certFile := fmt.Sprintf("/etc/ssl/etcd/ssl/node-%s.pem", runtime.GetHostsByRole(common.ETCD)[0].GetName())
-	keyFile := fmt.Sprintf("/etc/ssl/etcd/ssl/node-%s-key.pem", runtime.GetHostsByRole(common.ETCD)[0].GetName())
+	var caFile, certFile, keyFile string
+	if s.KubeConf.Cluster.Etcd.IsExternal() {
+		externalEtcd := etcd.ExternalEtcd(s.KubeConf.Cluster)
+		caFile, certFile, keyFile = externalEtcd.CaFile, externalEtcd.CertFile, externalEtcd.KeyFile
+	} else if s.KubeConf.Cluster.Etcd.IsKubeadm() {
+		caFile = filepath.Join(common.KubeCertDir, "etcd/ca.crt")
+		certFile = filepath.Join(common.KubeCertDir, "apiserver-etcd-client.crt")
+		keyFile = filepath.Join(common.KubeCertDir, "apiserver-etcd-client.key")
+	} else {
+		caFile = "/etc/ssl/etcd/ssl/ca.pem"
+		certFile = fmt.Sprintf("/etc/ssl/etcd/ssl/node-%s.pem", runtime.GetHostsByRole(common.ETCD)[0].GetName())
+		keyFile = fmt.Sprintf("/etc/ssl/etcd/ssl/node-%s-key.pem", runtime.GetHostsByRole(common.ETCD)[0].GetName())
+	}
 	if output, err := runtime.GetRunner().SudoCmd(
 		fmt.Sprintf("/usr/local/bin/kubectl -n kubesphere-monitoring-system create secret generic kube-etcd-client-certs "+
 			"--from-file=etcd-client-ca.crt=%s "+
@@ -18,4 +18,6 @@ package loadbalancer
 
 const (
 	LocalServer = "server: https://127.0.0.1"
+	// HealthCheckPort is the port of the health check frontend of haproxy on the workers.
+	HealthCheckPort = 8081
 )
@@ -51,7 +51,7 @@ func (h *HaproxyModule) Init() {
 			Data: util.Data{
 				"MasterNodes":                          templates.MasterNodeStr(h.Runtime, h.KubeConf),
 				"LoadbalancerApiserverPort":            h.KubeConf.Cluster.ControlPlaneEndpoint.Port,
-				"LoadbalancerApiserverHealthcheckPort": 8081,
+				"LoadbalancerApiserverHealthcheckPort": HealthCheckPort,
 				"KubernetesType":                       h.KubeConf.Cluster.Kubernetes.Type,
 			},
 		},
@@ -158,7 +158,7 @@ func (k *K3sHaproxyModule) Init() {
 			Data: util.Data{
 				"MasterNodes":                          templates.MasterNodeStr(k.Runtime, k.KubeConf),
 				"LoadbalancerApiserverPort":            k.KubeConf.Cluster.ControlPlaneEndpoint.Port,
-				"LoadbalancerApiserverHealthcheckPort": 8081,
+				"LoadbalancerApiserverHealthcheckPort": HealthCheckPort,
 				"KubernetesType":                       k.KubeConf.Cluster.Kubernetes.Type,
 			},
 		},
@@ -220,3 +220,42 @@ func (k *K3sHaproxyModule) Init() {
 		updateHostsFile,
 	}
 }
+
+type KubevipModule struct {
+	common.KubeModule
+	Skip bool
+}
+
+func (k *KubevipModule) IsSkip() bool {
+	return k.Skip
+}
+
+func (k *KubevipModule) Init() {
+	k.Name = "InternalLoadbalancerModule"
+	k.Desc = "Install internal load balancer"
+
+	getInterface := &task.RemoteTask{
+		Name:     "GetNodeInterface",
+		Desc:     "Get the network interface of the control plane node",
+		Hosts:    k.Runtime.GetHostsByRole(common.Master),
+		Prepare:  new(common.OnlyKubernetes),
+		Action:   new(GetInterface),
+		Parallel: true,
+	}
+
+	// The kube-vip is deployed as a static pod in the control plane nodes, so it should be generated
+	// before the kubeadm init or join. And it advertises the VIP which is the address of the controlPlaneEndpoint by ARP.
+	kubevipManifest := &task.RemoteTask{
+		Name:     "GenerateKubevipManifest",
+		Desc:     "Generate kube-vip manifest",
+		Hosts:    k.Runtime.GetHostsByRole(common.Master),
+		Prepare:  new(common.OnlyKubernetes),
+		Action:   new(GenerateKubevipManifest),
+		Parallel: true,
+	}
+
+	k.Tasks = []task.Interface{
+		getInterface,
+		kubevipManifest,
+	}
+}
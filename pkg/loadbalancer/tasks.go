@@ -165,3 +165,83 @@ func (g *GenerateK3sHaproxyManifest) Execute(runtime connector.Runtime) error {
 	}
 	return nil
 }
+
+type GetInterface struct {
+	common.KubeAction
+}
+
+func (g *GetInterface) Execute(runtime connector.Runtime) error {
+	host := runtime.RemoteHost()
+	address := vipHostAddress(g.KubeConf, host)
+	iface, err := runtime.GetRunner().SudoCmd(interfaceCmd(address), false)
+	if err != nil {
+		return errors.Wrap(errors.WithStack(err), "get the network interface failed")
+	}
+	if iface == "" {
+		return errors.Errorf("the network interface of the address %s is not found on %s", address, host.GetName())
+	}
+	// type: string
+	host.GetCache().Set("interface", iface)
+	return nil
+}
+
+// vipHostAddress returns the internal address of the host in the IP family of the VIP,
+// the VIP is advertised on the interface of this address.
+func vipHostAddress(kubeConf *common.KubeConf, host connector.Host) string {
+	vipIPv6 := util.IsIPv6(kubeConf.Cluster.ControlPlaneEndpoint.Address)
+	if hostCfg := kubeConf.Cluster.FindHost(host.GetName()); hostCfg != nil {
+		for _, addr := range hostCfg.InternalAddresses() {
+			if util.IsIPv6(addr) == vipIPv6 {
+				return addr
+			}
+		}
+	}
+	return host.GetInternalAddress()
+}
+
+// interfaceCmd prints the name of the network interface which the address is assigned to.
+func interfaceCmd(address string) string {
+	family, inet := "-4", "inet"
+	if util.IsIPv6(address) {
+		family, inet = "-6", "inet6"
+	}
+	return fmt.Sprintf("ip -o %s addr show | grep -w '%s %s' | head -1 | cut -d' ' -f2", family, inet, address)
+}
+
+// vipCIDR returns the prefix length of the VIP, which is a single address of its IP family.
+func vipCIDR(vip string) string {
+	if util.IsIPv6(vip) {
+		return "128"
+	}
+	return "32"
+}
+
+type GenerateKubevipManifest struct {
+	common.KubeAction
+}
+
+func (g *GenerateKubevipManifest) Execute(runtime connector.Runtime) error {
+	host := runtime.RemoteHost()
+	iface, ok := host.GetCache().GetMustString("interface")
+	if !ok {
+		return errors.New("get the network interface by host label failed")
+	}
+
+	templateAction := action.Template{
+		Template: templates.KubevipManifest,
+		Dst:      filepath.Join(common.KubeManifestDir, templates.KubevipManifest.Name()),
+		Data: util.Data{
+			"KubevipImage": images.GetImage(runtime, g.KubeConf, "kubevip").ImageName(),
+			"Interface":    iface,
+			"Address":      g.KubeConf.Cluster.ControlPlaneEndpoint.Address,
+			"VipCIDR":      vipCIDR(g.KubeConf.Cluster.ControlPlaneEndpoint.Address),
+			"Port":         g.KubeConf.Cluster.ControlPlaneEndpoint.Port,
+		},
+	}
+
+	templateAction.Init(nil, nil)
+	if err := templateAction.Execute(runtime); err != nil {
+		return err
+	}
+	return nil
+}
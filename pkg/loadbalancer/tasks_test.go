@@ -0,0 +1,80 @@
+/*
+ Copyright 2021 The KubeSphere Authors.
+
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+     http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package loadbalancer
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	kubekeyv1alpha2 "github.com/kubesphere/kubekey/apis/kubekey/v1alpha2"
+	"github.com/kubesphere/kubekey/pkg/common"
+	"github.com/kubesphere/kubekey/pkg/core/connector"
+	"github.com/kubesphere/kubekey/pkg/loadbalancer/templates"
+)
+
+func TestVipHostAddress(t *testing.T) {
+	cluster := &kubekeyv1alpha2.ClusterSpec{
+		Hosts: []kubekeyv1alpha2.HostCfg{
+			{Name: "node1", InternalAddress: "172.16.0.2,fd00:172:16::2"},
+		},
+	}
+	kubeConf := &common.KubeConf{Cluster: cluster}
+	host := &connector.BaseHost{Name: "node1", InternalAddress: "172.16.0.2"}
+
+	for vip, want := range map[string]string{
+		"172.16.0.10":    "172.16.0.2",
+		"fd00:172:16::a": "fd00:172:16::2",
+	} {
+		cluster.ControlPlaneEndpoint.Address = vip
+		if got := vipHostAddress(kubeConf, host); got != want {
+			t.Errorf("vipHostAddress() with the VIP %s = %s, want %s", vip, got, want)
+		}
+	}
+}
+
+func TestInterfaceCmd(t *testing.T) {
+	for address, want := range map[string]string{
+		"172.16.0.2":     "ip -o -4 addr show | grep -w 'inet 172.16.0.2'",
+		"fd00:172:16::2": "ip -o -6 addr show | grep -w 'inet6 fd00:172:16::2'",
+	} {
+		if got := interfaceCmd(address); !strings.HasPrefix(got, want) {
+			t.Errorf("interfaceCmd(%s) = %s, want the prefix %s", address, got, want)
+		}
+	}
+}
+
+func TestKubevipManifest(t *testing.T) {
+	for vip, want := range map[string]string{
+		"172.16.0.10":    "32",
+		"fd00:172:16::a": "128",
+	} {
+		var buf bytes.Buffer
+		if err := templates.KubevipManifest.Execute(&buf, map[string]interface{}{
+			"KubevipImage": "plndr/kube-vip:v0.4.2",
+			"Interface":    "eth0",
+			"Address":      vip,
+			"VipCIDR":      vipCIDR(vip),
+			"Port":         6443,
+		}); err != nil {
+			t.Fatal(err)
+		}
+		if !strings.Contains(buf.String(), "- name: vip_cidr\n      value: \""+want+"\"") {
+			t.Errorf("the VIP %s should be advertised with the prefix %s:\n%s", vip, want, buf.String())
+		}
+	}
+}
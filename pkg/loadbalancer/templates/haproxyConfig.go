@@ -20,6 +20,7 @@ import (
 	"github.com/kubesphere/kubekey/pkg/common"
 	"github.com/kubesphere/kubekey/pkg/core/connector"
 	"github.com/lithammer/dedent"
+	"net"
 	"strconv"
 	"text/template"
 )
@@ -74,7 +75,7 @@ backend kube_api_backend
 func MasterNodeStr(runtime connector.ModuleRuntime, conf *common.KubeConf) []string {
 	masterNodes := make([]string, len(runtime.GetHostsByRole(common.Master)))
 	for i, node := range runtime.GetHostsByRole(common.Master) {
-		masterNodes[i] = node.GetName() + " " + node.GetAddress() + ":" + strconv.Itoa(conf.Cluster.ControlPlaneEndpoint.Port)
+		masterNodes[i] = node.GetName() + " " + net.JoinHostPort(node.GetAddress(), strconv.Itoa(conf.Cluster.ControlPlaneEndpoint.Port))
 	}
 	return masterNodes
 }
@@ -22,6 +22,7 @@ import (
 	"github.com/kubesphere/kubekey/pkg/artifact"
 	"github.com/kubesphere/kubekey/pkg/binaries"
 	"github.com/kubesphere/kubekey/pkg/bootstrap/confirm"
+	"github.com/kubesphere/kubekey/pkg/bootstrap/firewall"
 	"github.com/kubesphere/kubekey/pkg/bootstrap/os"
 	"github.com/kubesphere/kubekey/pkg/bootstrap/precheck"
 	"github.com/kubesphere/kubekey/pkg/bootstrap/registry"
@@ -41,6 +42,8 @@ import (
 
 func NewAddNodesPipeline(runtime *common.KubeRuntime) error {
 	noArtifact := runtime.Arg.Artifact == ""
+	kubekeyEtcd := runtime.Cluster.Etcd.IsKubeKey()
+	externalEtcd := runtime.Cluster.Etcd.IsExternal()
 
 	m := []module.Module{
 		&precheck.NodePreCheckModule{},
@@ -49,26 +52,40 @@ func NewAddNodesPipeline(runtime *common.KubeRuntime) error {
 		&os.RepositoryModule{Skip: noArtifact || !runtime.Arg.InstallPackages},
 		&binaries.NodeBinariesModule{},
 		&os.ConfigureOSModule{},
+		&firewall.ConfigureFirewallModule{Skip: !runtime.Cluster.System.IsFirewallManaged()},
 		&registry.RegistryCertsModule{Skip: len(runtime.GetHostsByRole(common.Registry)) == 0},
 		&kubernetes.StatusModule{},
 		&container.InstallContainerModule{},
 		&images.PullModule{Skip: runtime.Arg.SkipPullImages},
-		&etcd.PreCheckModule{},
-		&etcd.CertsModule{},
-		&etcd.InstallETCDBinaryModule{},
-		&etcd.ConfigureModule{},
-		&etcd.BackupModule{},
+		&etcd.PreCheckModule{Skip: !kubekeyEtcd},
+		&kubernetes.AddControlPlanePreCheckModule{},
+		&etcd.CertsModule{Skip: !kubekeyEtcd},
+		&etcd.ExternalCertsModule{Skip: !externalEtcd},
+		&etcd.InstallETCDBinaryModule{Skip: !kubekeyEtcd},
+		&etcd.ConfigureModule{Skip: !kubekeyEtcd},
+		&etcd.BackupModule{Skip: !kubekeyEtcd},
 		&kubernetes.InstallKubeBinariesModule{},
+		&kubernetes.UpdateCertSANsModule{},
+		&loadbalancer.KubevipModule{Skip: !runtime.Cluster.ControlPlaneEndpoint.IsInternalLBEnabledVip()},
 		&kubernetes.JoinNodesModule{},
+		&kubernetes.NodeLabelsModule{},
 		&loadbalancer.HaproxyModule{Skip: !runtime.Cluster.ControlPlaneEndpoint.IsInternalLBEnabled()},
 		&filesystem.ChownModule{},
 		&certs.AutoRenewCertsModule{},
 	}
 
+	fingerprint, err := runtime.Fingerprint()
+	if err != nil {
+		return err
+	}
+
 	p := pipeline.Pipeline{
 		Name:            "AddNodesPipeline",
 		Modules:         m,
 		Runtime:         runtime,
+		Fingerprint:     fingerprint,
+		Resume:          runtime.Arg.Resume,
+		DryRun:          runtime.Arg.DryRun,
 		ModulePostHooks: []module.PostHookInterface{&hooks.UpdateCRStatusHook{}},
 	}
 	if err := p.Start(); err != nil {
@@ -82,6 +99,9 @@ func NewAddNodesPipeline(runtime *common.KubeRuntime) error {
 		}
 		return err
 	}
+	if runtime.Arg.DryRun {
+		return nil
+	}
 
 	if runtime.Arg.InCluster {
 		if err := kubekeycontroller.PatchNodeImportStatus(runtime, kubekeycontroller.Success); err != nil {
@@ -96,26 +116,40 @@ func NewAddNodesPipeline(runtime *common.KubeRuntime) error {
 }
 
 func NewK3sAddNodesPipeline(runtime *common.KubeRuntime) error {
+	kubekeyEtcd := runtime.Cluster.Etcd.IsKubeKey()
+	externalEtcd := runtime.Cluster.Etcd.IsExternal()
+
 	m := []module.Module{
 		&binaries.K3sNodeBinariesModule{},
 		&os.ConfigureOSModule{},
+		&firewall.ConfigureFirewallModule{Skip: !runtime.Cluster.System.IsFirewallManaged()},
 		&k3s.StatusModule{},
-		&etcd.PreCheckModule{},
-		&etcd.CertsModule{},
-		&etcd.InstallETCDBinaryModule{},
-		&etcd.ConfigureModule{},
-		&etcd.BackupModule{},
+		&etcd.PreCheckModule{Skip: !kubekeyEtcd},
+		&etcd.CertsModule{Skip: !kubekeyEtcd},
+		&etcd.ExternalCertsModule{Skip: !externalEtcd},
+		&etcd.InstallETCDBinaryModule{Skip: !kubekeyEtcd},
+		&etcd.ConfigureModule{Skip: !kubekeyEtcd},
+		&etcd.BackupModule{Skip: !kubekeyEtcd},
 		&k3s.InstallKubeBinariesModule{},
 		&k3s.JoinNodesModule{},
+		&kubernetes.NodeLabelsModule{},
 		&loadbalancer.K3sHaproxyModule{Skip: !runtime.Cluster.ControlPlaneEndpoint.IsInternalLBEnabled()},
 		&filesystem.ChownModule{},
 		&certs.AutoRenewCertsModule{},
 	}
 
+	fingerprint, err := runtime.Fingerprint()
+	if err != nil {
+		return err
+	}
+
 	p := pipeline.Pipeline{
 		Name:            "AddNodesPipeline",
 		Modules:         m,
 		Runtime:         runtime,
+		Fingerprint:     fingerprint,
+		Resume:          runtime.Arg.Resume,
+		DryRun:          runtime.Arg.DryRun,
 		ModulePostHooks: []module.PostHookInterface{&hooks.UpdateCRStatusHook{}},
 	}
 	if err := p.Start(); err != nil {
@@ -129,6 +163,9 @@ func NewK3sAddNodesPipeline(runtime *common.KubeRuntime) error {
 		}
 		return err
 	}
+	if runtime.Arg.DryRun {
+		return nil
+	}
 
 	if runtime.Arg.InCluster {
 		if err := kubekeycontroller.PatchNodeImportStatus(runtime, kubekeycontroller.Success); err != nil {
@@ -153,6 +190,8 @@ func AddNodes(args common.Argument, downloadCmd string) error {
 	var loaderType string
 	if args.FilePath != "" {
 		loaderType = common.File
+	} else if args.InCluster {
+		loaderType = common.Operator
 	} else {
 		loaderType = common.AllInOne
 	}
@@ -0,0 +1,111 @@
+/*
+ Copyright 2021 The KubeSphere Authors.
+
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+     http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package pipelines
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/kubesphere/kubekey/pkg/common"
+	"github.com/kubesphere/kubekey/pkg/core/module"
+	"github.com/kubesphere/kubekey/pkg/core/pipeline"
+	"github.com/kubesphere/kubekey/pkg/etcd"
+	"github.com/pkg/errors"
+)
+
+func NewBackupETCDPipeline(runtime *common.KubeRuntime) error {
+	m := []module.Module{
+		&etcd.PreCheckModule{},
+		&etcd.SnapshotModule{LocalDir: snapshotDir(runtime)},
+	}
+
+	p := pipeline.Pipeline{
+		Name:    "BackupETCDPipeline",
+		Modules: m,
+		Runtime: runtime,
+		DryRun:  runtime.Arg.DryRun,
+	}
+	if err := p.Start(); err != nil {
+		return err
+	}
+	return nil
+}
+
+func BackupETCD(args common.Argument) error {
+	var loaderType string
+	if args.FilePath != "" {
+		loaderType = common.File
+	} else {
+		loaderType = common.AllInOne
+	}
+
+	runtime, err := common.NewKubeRuntime(loaderType, args)
+	if err != nil {
+		return err
+	}
+
+	if !runtime.Cluster.Etcd.IsKubeKey() {
+		return errors.Errorf("only the etcd installed by kubekey can be backed up, but the etcd type is %s", runtime.Cluster.Etcd.Type)
+	}
+
+	if err := NewBackupETCDPipeline(runtime); err != nil {
+		return err
+	}
+	return nil
+}
+
+// ListETCDBackups prints the etcd snapshots which have been fetched to the local snapshot dir.
+func ListETCDBackups(args common.Argument) error {
+	var loaderType string
+	if args.FilePath != "" {
+		loaderType = common.File
+	} else {
+		loaderType = common.AllInOne
+	}
+
+	runtime, err := common.NewKubeRuntime(loaderType, args)
+	if err != nil {
+		return err
+	}
+
+	dir := snapshotDir(runtime)
+	fileInfos, err := ioutil.ReadDir(dir)
+	if err != nil && !os.IsNotExist(err) {
+		return errors.Wrapf(err, "read etcd snapshot dir %s failed", dir)
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 10, 4, 3, ' ', 0)
+	_, _ = fmt.Fprintln(w, "SNAPSHOT\tSIZE\tCREATED")
+	for _, f := range fileInfos {
+		if f.IsDir() || !strings.HasSuffix(f.Name(), ".db") {
+			continue
+		}
+		_, _ = fmt.Fprintf(w, "%s\t%d\t%s\n", filepath.Join(dir, f.Name()), f.Size(), f.ModTime().Format("2006-01-02 15:04:05"))
+	}
+	return w.Flush()
+}
+
+func snapshotDir(runtime *common.KubeRuntime) string {
+	if runtime.Arg.SnapshotDir != "" {
+		return runtime.Arg.SnapshotDir
+	}
+	return filepath.Join(runtime.GetWorkDir(), "etcd-backups")
+}
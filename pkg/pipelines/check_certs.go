@@ -33,6 +33,7 @@ func CheckCertsPipeline(runtime *common.KubeRuntime) error {
 		Name:    "CheckCertsPipeline",
 		Modules: m,
 		Runtime: runtime,
+		DryRun:  runtime.Arg.DryRun,
 	}
 	if err := p.Start(); err != nil {
 		return err
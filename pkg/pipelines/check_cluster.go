@@ -0,0 +1,78 @@
+/*
+ Copyright 2021 The KubeSphere Authors.
+
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+     http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package pipelines
+
+import (
+	"github.com/kubesphere/kubekey/pkg/common"
+	"github.com/kubesphere/kubekey/pkg/core/module"
+	"github.com/kubesphere/kubekey/pkg/core/pipeline"
+	"github.com/kubesphere/kubekey/pkg/healthcheck"
+	"github.com/pkg/errors"
+	"io"
+	"os"
+)
+
+func NewCheckClusterPipeline(runtime *common.KubeRuntime, output string, w io.Writer) error {
+	m := []module.Module{
+		&healthcheck.ConnectivityModule{},
+		&healthcheck.ClusterCheckModule{},
+		&healthcheck.ReportModule{Output: output, Writer: w},
+	}
+
+	p := pipeline.Pipeline{
+		Name:    "CheckClusterPipeline",
+		Modules: m,
+		Runtime: runtime,
+	}
+	if err := p.Start(); err != nil {
+		return err
+	}
+	return nil
+}
+
+func CheckCluster(args common.Argument, output string) error {
+	switch output {
+	case "", common.OutputText:
+	case common.OutputJSON:
+	default:
+		return errors.Errorf("unsupported output format %s, it should be %s or %s", output, common.OutputText, common.OutputJSON)
+	}
+
+	var loaderType string
+	if args.FilePath != "" {
+		loaderType = common.File
+	} else {
+		loaderType = common.AllInOne
+	}
+
+	runtime, err := common.NewKubeRuntime(loaderType, args)
+	if err != nil {
+		return err
+	}
+
+	// Keep the stdout for the report only, the other outputs are redirected to the stderr.
+	w := os.Stdout
+	if output == common.OutputJSON {
+		os.Stdout = os.Stderr
+		defer func() { os.Stdout = w }()
+	}
+
+	if err := NewCheckClusterPipeline(runtime, output, w); err != nil {
+		return err
+	}
+	return nil
+}
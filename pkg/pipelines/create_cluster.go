@@ -21,6 +21,7 @@ import (
 	"fmt"
 	"github.com/kubesphere/kubekey/pkg/artifact"
 	"github.com/kubesphere/kubekey/pkg/bootstrap/confirm"
+	"github.com/kubesphere/kubekey/pkg/bootstrap/firewall"
 	"github.com/kubesphere/kubekey/pkg/bootstrap/precheck"
 	"github.com/kubesphere/kubekey/pkg/certs"
 	"github.com/kubesphere/kubekey/pkg/container"
@@ -50,6 +51,8 @@ import (
 
 func NewCreateClusterPipeline(runtime *common.KubeRuntime) error {
 	noArtifact := runtime.Arg.Artifact == ""
+	kubekeyEtcd := runtime.Cluster.Etcd.IsKubeKey()
+	externalEtcd := runtime.Cluster.Etcd.IsExternal()
 	skipPushImages := runtime.Arg.SKipPushImages || noArtifact || (!noArtifact && runtime.Cluster.Registry.PrivateRegistry == "")
 	skipLocalStorage := true
 	if runtime.Arg.DeployLocalStorage != nil {
@@ -65,20 +68,24 @@ func NewCreateClusterPipeline(runtime *common.KubeRuntime) error {
 		&os.RepositoryModule{Skip: noArtifact || !runtime.Arg.InstallPackages},
 		&binaries.NodeBinariesModule{},
 		&os.ConfigureOSModule{},
+		&firewall.ConfigureFirewallModule{Skip: !runtime.Cluster.System.IsFirewallManaged()},
 		&kubernetes.StatusModule{},
 		&container.InstallContainerModule{},
 		&images.PushModule{Skip: skipPushImages},
 		&images.PullModule{Skip: runtime.Arg.SkipPullImages},
-		&etcd.PreCheckModule{},
-		&etcd.CertsModule{},
-		&etcd.InstallETCDBinaryModule{},
-		&etcd.ConfigureModule{},
-		&etcd.BackupModule{},
+		&etcd.PreCheckModule{Skip: !kubekeyEtcd},
+		&etcd.CertsModule{Skip: !kubekeyEtcd},
+		&etcd.ExternalCertsModule{Skip: !externalEtcd},
+		&etcd.InstallETCDBinaryModule{Skip: !kubekeyEtcd},
+		&etcd.ConfigureModule{Skip: !kubekeyEtcd},
+		&etcd.BackupModule{Skip: !kubekeyEtcd},
 		&kubernetes.InstallKubeBinariesModule{},
+		&loadbalancer.KubevipModule{Skip: !runtime.Cluster.ControlPlaneEndpoint.IsInternalLBEnabledVip()},
 		&kubernetes.InitKubernetesModule{},
 		&dns.ClusterDNSModule{},
 		&kubernetes.StatusModule{},
 		&kubernetes.JoinNodesModule{},
+		&kubernetes.NodeLabelsModule{},
 		&loadbalancer.HaproxyModule{Skip: !runtime.Cluster.ControlPlaneEndpoint.IsInternalLBEnabled()},
 		&network.DeployNetworkPluginModule{},
 		&filesystem.ChownModule{},
@@ -91,15 +98,26 @@ func NewCreateClusterPipeline(runtime *common.KubeRuntime) error {
 		&kubesphere.CheckResultModule{Skip: !runtime.Cluster.KubeSphere.Enabled},
 	}
 
+	fingerprint, err := runtime.Fingerprint()
+	if err != nil {
+		return err
+	}
+
 	p := pipeline.Pipeline{
 		Name:            "CreateClusterPipeline",
 		Modules:         m,
 		Runtime:         runtime,
+		Fingerprint:     fingerprint,
+		Resume:          runtime.Arg.Resume,
+		DryRun:          runtime.Arg.DryRun,
 		ModulePostHooks: []module.PostHookInterface{&hooks.UpdateCRStatusHook{}},
 	}
 	if err := p.Start(); err != nil {
 		return err
 	}
+	if runtime.Arg.DryRun {
+		return nil
+	}
 
 	if runtime.Cluster.KubeSphere.Enabled {
 
@@ -143,6 +161,8 @@ Please check the result using the command:
 }
 
 func NewK3sCreateClusterPipeline(runtime *common.KubeRuntime) error {
+	kubekeyEtcd := runtime.Cluster.Etcd.IsKubeKey()
+	externalEtcd := runtime.Cluster.Etcd.IsExternal()
 	skipLocalStorage := true
 	if runtime.Arg.DeployLocalStorage != nil {
 		skipLocalStorage = !*runtime.Arg.DeployLocalStorage
@@ -153,16 +173,19 @@ func NewK3sCreateClusterPipeline(runtime *common.KubeRuntime) error {
 	m := []module.Module{
 		&binaries.K3sNodeBinariesModule{},
 		&os.ConfigureOSModule{},
+		&firewall.ConfigureFirewallModule{Skip: !runtime.Cluster.System.IsFirewallManaged()},
 		&k3s.StatusModule{},
-		&etcd.PreCheckModule{},
-		&etcd.CertsModule{},
-		&etcd.InstallETCDBinaryModule{},
-		&etcd.ConfigureModule{},
-		&etcd.BackupModule{},
+		&etcd.PreCheckModule{Skip: !kubekeyEtcd},
+		&etcd.CertsModule{Skip: !kubekeyEtcd},
+		&etcd.ExternalCertsModule{Skip: !externalEtcd},
+		&etcd.InstallETCDBinaryModule{Skip: !kubekeyEtcd},
+		&etcd.ConfigureModule{Skip: !kubekeyEtcd},
+		&etcd.BackupModule{Skip: !kubekeyEtcd},
 		&k3s.InstallKubeBinariesModule{},
 		&k3s.InitClusterModule{},
 		&k3s.StatusModule{},
 		&k3s.JoinNodesModule{},
+		&kubernetes.NodeLabelsModule{},
 		&loadbalancer.K3sHaproxyModule{Skip: !runtime.Cluster.ControlPlaneEndpoint.IsInternalLBEnabled()},
 		&network.DeployNetworkPluginModule{},
 		&filesystem.ChownModule{},
@@ -173,15 +196,26 @@ func NewK3sCreateClusterPipeline(runtime *common.KubeRuntime) error {
 		&kubesphere.CheckResultModule{Skip: !runtime.Cluster.KubeSphere.Enabled},
 	}
 
+	fingerprint, err := runtime.Fingerprint()
+	if err != nil {
+		return err
+	}
+
 	p := pipeline.Pipeline{
 		Name:            "K3sCreateClusterPipeline",
 		Modules:         m,
 		Runtime:         runtime,
+		Fingerprint:     fingerprint,
+		Resume:          runtime.Arg.Resume,
+		DryRun:          runtime.Arg.DryRun,
 		ModulePostHooks: []module.PostHookInterface{&hooks.UpdateCRStatusHook{}},
 	}
 	if err := p.Start(); err != nil {
 		return err
 	}
+	if runtime.Arg.DryRun {
+		return nil
+	}
 
 	if runtime.Cluster.KubeSphere.Enabled {
 
@@ -235,6 +269,8 @@ func CreateCluster(args common.Argument, downloadCmd string) error {
 	var loaderType string
 	if args.FilePath != "" {
 		loaderType = common.File
+	} else if args.InCluster {
+		loaderType = common.Operator
 	} else {
 		loaderType = common.AllInOne
 	}
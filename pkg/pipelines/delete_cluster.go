@@ -18,6 +18,7 @@ package pipelines
 
 import (
 	"github.com/kubesphere/kubekey/pkg/bootstrap/confirm"
+	"github.com/kubesphere/kubekey/pkg/bootstrap/firewall"
 	"github.com/kubesphere/kubekey/pkg/bootstrap/os"
 	"github.com/kubesphere/kubekey/pkg/certs"
 	"github.com/kubesphere/kubekey/pkg/common"
@@ -31,6 +32,7 @@ func NewDeleteClusterPipeline(runtime *common.KubeRuntime) error {
 	m := []module.Module{
 		&confirm.DeleteClusterConfirmModule{},
 		&kubernetes.ResetClusterModule{},
+		&firewall.ClearFirewallModule{Skip: !runtime.Cluster.System.IsFirewallManaged()},
 		&os.ClearOSEnvironmentModule{},
 		&certs.UninstallAutoRenewCertsModule{},
 	}
@@ -39,6 +41,7 @@ func NewDeleteClusterPipeline(runtime *common.KubeRuntime) error {
 		Name:    "DeleteClusterPipeline",
 		Modules: m,
 		Runtime: runtime,
+		DryRun:  runtime.Arg.DryRun,
 	}
 	if err := p.Start(); err != nil {
 		return err
@@ -50,6 +53,7 @@ func NewK3sDeleteClusterPipeline(runtime *common.KubeRuntime) error {
 	m := []module.Module{
 		&confirm.DeleteClusterConfirmModule{},
 		&k3s.DeleteClusterModule{},
+		&firewall.ClearFirewallModule{Skip: !runtime.Cluster.System.IsFirewallManaged()},
 		&os.ClearOSEnvironmentModule{},
 		&certs.UninstallAutoRenewCertsModule{},
 	}
@@ -58,6 +62,7 @@ func NewK3sDeleteClusterPipeline(runtime *common.KubeRuntime) error {
 		Name:    "K3sDeleteClusterPipeline",
 		Modules: m,
 		Runtime: runtime,
+		DryRun:  runtime.Arg.DryRun,
 	}
 	if err := p.Start(); err != nil {
 		return err
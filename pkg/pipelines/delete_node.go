@@ -19,24 +19,64 @@ package pipelines
 import (
 	"github.com/kubesphere/kubekey/pkg/bootstrap/config"
 	"github.com/kubesphere/kubekey/pkg/bootstrap/confirm"
+	"github.com/kubesphere/kubekey/pkg/bootstrap/firewall"
+	"github.com/kubesphere/kubekey/pkg/bootstrap/os"
 	"github.com/kubesphere/kubekey/pkg/common"
+	"github.com/kubesphere/kubekey/pkg/core/connector"
 	"github.com/kubesphere/kubekey/pkg/core/module"
 	"github.com/kubesphere/kubekey/pkg/core/pipeline"
+	"github.com/kubesphere/kubekey/pkg/etcd"
+	"github.com/kubesphere/kubekey/pkg/k3s"
 	"github.com/kubesphere/kubekey/pkg/kubernetes"
+	"github.com/kubesphere/kubekey/pkg/loadbalancer"
+	"github.com/pkg/errors"
 )
 
 func DeleteNodePipeline(runtime *common.KubeRuntime) error {
+	var node connector.Host
+	for _, host := range runtime.GetAllHosts() {
+		if host.GetName() == runtime.Arg.NodeName {
+			node = host
+		}
+	}
+	if node == nil {
+		return errors.Errorf("node %s is not found in the config", runtime.Arg.NodeName)
+	}
+
+	isMaster := node.IsRole(common.Master)
+	if isMaster && len(runtime.GetHostsByRole(common.Master)) == 1 {
+		return errors.Errorf("node %s is the last master of the cluster", node.GetName())
+	}
+	k3sType := runtime.Cluster.Kubernetes.Type == common.K3s
+	kubekeyEtcd := runtime.Cluster.Etcd.IsKubeKey()
+	// The etcd member on the node has to be removed if the etcd is installed by kubekey on it,
+	// or runs as a static pod on the master.
+	etcdMember := (kubekeyEtcd && node.IsRole(common.ETCD)) || (runtime.Cluster.Etcd.IsKubeadm() && isMaster)
+	if kubekeyEtcd && etcdMember && len(runtime.GetHostsByRole(common.ETCD)) == 1 {
+		return errors.Errorf("node %s is the last member of the etcd cluster", node.GetName())
+	}
+	lbEnabled := isMaster && runtime.Cluster.ControlPlaneEndpoint.IsInternalLBEnabled()
+
 	m := []module.Module{
-		&confirm.DeleteNodeConfirmModule{},
-		&config.ModifyConfigModule{},
-		&kubernetes.CompareConfigAndClusterInfoModule{},
+		&confirm.DeleteNodeConfirmModule{Skip: runtime.Arg.SkipConfirmCheck},
+		&etcd.RemoveMemberPreCheckModule{Skip: !etcdMember},
 		&kubernetes.DeleteKubeNodeModule{},
+		&etcd.RemoveMemberModule{Skip: !etcdMember},
+		&kubernetes.ResetNodeModule{Skip: k3sType},
+		&k3s.DeleteNodeModule{Skip: !k3sType},
+		&firewall.ClearNodeFirewallModule{Skip: !runtime.Cluster.System.IsFirewallManaged()},
+		&os.ClearNodeOSModule{},
+		&config.ModifyConfigModule{},
+		&etcd.RefreshConfigModule{Skip: !kubekeyEtcd || !etcdMember},
+		&loadbalancer.HaproxyModule{Skip: k3sType || !lbEnabled},
+		&loadbalancer.K3sHaproxyModule{Skip: !k3sType || !lbEnabled},
 	}
 
 	p := pipeline.Pipeline{
 		Name:    "DeleteNodePipeline",
 		Modules: m,
 		Runtime: runtime,
+		DryRun:  runtime.Arg.DryRun,
 	}
 	if err := p.Start(); err != nil {
 		return err
@@ -32,6 +32,7 @@ func NewInitDependenciesPipeline(runtime *common.KubeRuntime) error {
 		Name:    "InitDependenciesPipeline",
 		Modules: m,
 		Runtime: runtime,
+		DryRun:  runtime.Arg.DryRun,
 	}
 	if err := p.Start(); err != nil {
 		return err
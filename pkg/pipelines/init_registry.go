@@ -20,6 +20,7 @@ import (
 	"fmt"
 	"github.com/kubesphere/kubekey/pkg/artifact"
 	"github.com/kubesphere/kubekey/pkg/binaries"
+	"github.com/kubesphere/kubekey/pkg/bootstrap/firewall"
 	"github.com/kubesphere/kubekey/pkg/bootstrap/os"
 	"github.com/kubesphere/kubekey/pkg/bootstrap/registry"
 	"github.com/kubesphere/kubekey/pkg/common"
@@ -34,6 +35,7 @@ func NewInitRegistryPipeline(runtime *common.KubeRuntime) error {
 		&artifact.UnArchiveModule{Skip: noArtifact},
 		&binaries.RegistryPackageModule{},
 		&os.ConfigureOSModule{},
+		&firewall.ConfigureFirewallModule{Skip: !runtime.Cluster.System.IsFirewallManaged()},
 		&registry.RegistryCertsModule{},
 		&registry.InstallRegistryModule{},
 	}
@@ -42,6 +44,7 @@ func NewInitRegistryPipeline(runtime *common.KubeRuntime) error {
 		Name:    "InitRegistryPipeline",
 		Modules: m,
 		Runtime: runtime,
+		DryRun:  runtime.Arg.DryRun,
 	}
 	if err := p.Start(); err != nil {
 		return err
@@ -34,6 +34,7 @@ func RenewCertsPipeline(runtime *common.KubeRuntime) error {
 		Name:    "RenewCertsPipeline",
 		Modules: m,
 		Runtime: runtime,
+		DryRun:  runtime.Arg.DryRun,
 	}
 	if err := p.Start(); err != nil {
 		return err
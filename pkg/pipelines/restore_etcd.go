@@ -0,0 +1,68 @@
+/*
+ Copyright 2021 The KubeSphere Authors.
+
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+     http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package pipelines
+
+import (
+	"github.com/kubesphere/kubekey/pkg/bootstrap/confirm"
+	"github.com/kubesphere/kubekey/pkg/common"
+	"github.com/kubesphere/kubekey/pkg/core/module"
+	"github.com/kubesphere/kubekey/pkg/core/pipeline"
+	"github.com/kubesphere/kubekey/pkg/etcd"
+	"github.com/pkg/errors"
+)
+
+func NewRestoreETCDPipeline(runtime *common.KubeRuntime) error {
+	m := []module.Module{
+		&confirm.RestoreETCDConfirmModule{Skip: runtime.Arg.SkipConfirmCheck, Snapshot: runtime.Arg.Snapshot},
+		&etcd.PreCheckModule{},
+		&etcd.RestoreModule{Snapshot: runtime.Arg.Snapshot},
+	}
+
+	p := pipeline.Pipeline{
+		Name:    "RestoreETCDPipeline",
+		Modules: m,
+		Runtime: runtime,
+		DryRun:  runtime.Arg.DryRun,
+	}
+	if err := p.Start(); err != nil {
+		return err
+	}
+	return nil
+}
+
+func RestoreETCD(args common.Argument) error {
+	var loaderType string
+	if args.FilePath != "" {
+		loaderType = common.File
+	} else {
+		loaderType = common.AllInOne
+	}
+
+	runtime, err := common.NewKubeRuntime(loaderType, args)
+	if err != nil {
+		return err
+	}
+
+	if !runtime.Cluster.Etcd.IsKubeKey() {
+		return errors.Errorf("only the etcd installed by kubekey can be restored, but the etcd type is %s", runtime.Cluster.Etcd.Type)
+	}
+
+	if err := NewRestoreETCDPipeline(runtime); err != nil {
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,78 @@
+/*
+ Copyright 2021 The KubeSphere Authors.
+
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+     http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package pipelines
+
+import (
+	"fmt"
+	"github.com/kubesphere/kubekey/pkg/common"
+	"github.com/kubesphere/kubekey/pkg/core/module"
+	"github.com/kubesphere/kubekey/pkg/core/pipeline"
+	"github.com/kubesphere/kubekey/pkg/core/util"
+	"github.com/kubesphere/kubekey/pkg/supportbundle"
+	"github.com/pkg/errors"
+	"path/filepath"
+	"time"
+)
+
+func NewSupportBundlePipeline(runtime *common.KubeRuntime, since time.Duration, bundleFile string) error {
+	name := supportbundle.BundleName(time.Now())
+	localDir := filepath.Join(runtime.GetWorkDir(), name)
+	if err := util.CreateDir(localDir); err != nil {
+		return errors.Wrap(errors.WithStack(err), fmt.Sprintf("create local dir %s failed", localDir))
+	}
+	if bundleFile == "" {
+		bundleFile = fmt.Sprintf("%s.tar.gz", name)
+	}
+
+	m := []module.Module{
+		&supportbundle.CollectModule{Since: since, LocalDir: localDir},
+		&supportbundle.ArchiveModule{LocalDir: localDir, BundleFile: bundleFile},
+	}
+
+	p := pipeline.Pipeline{
+		Name:    "SupportBundlePipeline",
+		Modules: m,
+		Runtime: runtime,
+	}
+	if err := p.Start(); err != nil {
+		return err
+	}
+	return nil
+}
+
+func SupportBundle(args common.Argument, since time.Duration, bundleFile string) error {
+	if since <= 0 {
+		return errors.Errorf("the time range of the logs %s should be positive", since)
+	}
+
+	var loaderType string
+	if args.FilePath != "" {
+		loaderType = common.File
+	} else {
+		loaderType = common.AllInOne
+	}
+
+	runtime, err := common.NewKubeRuntime(loaderType, args)
+	if err != nil {
+		return err
+	}
+
+	if err := NewSupportBundlePipeline(runtime, since, bundleFile); err != nil {
+		return err
+	}
+	return nil
+}
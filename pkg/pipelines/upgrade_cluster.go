@@ -20,6 +20,7 @@ import (
 	"fmt"
 	"github.com/kubesphere/kubekey/pkg/artifact"
 	"github.com/kubesphere/kubekey/pkg/bootstrap/confirm"
+	"github.com/kubesphere/kubekey/pkg/bootstrap/firewall"
 	"github.com/kubesphere/kubekey/pkg/bootstrap/os"
 	"github.com/kubesphere/kubekey/pkg/bootstrap/precheck"
 	"github.com/kubesphere/kubekey/pkg/certs"
@@ -41,23 +42,32 @@ func NewUpgradeClusterPipeline(runtime *common.KubeRuntime) error {
 		&confirm.UpgradeConfirmModule{Skip: runtime.Arg.SkipConfirmCheck},
 		&artifact.UnArchiveModule{Skip: noArtifact},
 		&os.ConfigureOSModule{},
-		&kubernetes.SetUpgradePlanModule{Step: kubernetes.ToV121},
-		&kubernetes.ProgressiveUpgradeModule{Step: kubernetes.ToV121},
+		&firewall.ConfigureFirewallModule{Skip: !runtime.Cluster.System.IsFirewallManaged()},
+		&kubernetes.SetUpgradePlanModule{Step: kubernetes.ToKubeSphereCompatible},
+		&kubernetes.ProgressiveUpgradeModule{Step: kubernetes.ToKubeSphereCompatible},
 		&loadbalancer.HaproxyModule{Skip: !runtime.Cluster.ControlPlaneEndpoint.IsInternalLBEnabled()},
 		&kubesphere.CleanClusterConfigurationModule{},
 		&kubesphere.ConvertModule{},
 		&kubesphere.DeployModule{},
 		&kubesphere.CheckResultModule{},
-		&kubernetes.SetUpgradePlanModule{Step: kubernetes.ToV122},
-		&kubernetes.ProgressiveUpgradeModule{Step: kubernetes.ToV122},
+		&kubernetes.SetUpgradePlanModule{Step: kubernetes.ToDesired},
+		&kubernetes.ProgressiveUpgradeModule{Step: kubernetes.ToDesired},
 		&filesystem.ChownModule{},
 		&certs.AutoRenewCertsModule{},
 	}
 
+	fingerprint, err := runtime.Fingerprint()
+	if err != nil {
+		return err
+	}
+
 	p := pipeline.Pipeline{
-		Name:    "UpgradeClusterPipeline",
-		Modules: m,
-		Runtime: runtime,
+		Name:        "UpgradeClusterPipeline",
+		Modules:     m,
+		Runtime:     runtime,
+		Fingerprint: fingerprint,
+		Resume:      runtime.Arg.Resume,
+		DryRun:      runtime.Arg.DryRun,
 	}
 	if err := p.Start(); err != nil {
 		return err
@@ -55,6 +55,38 @@ func (d *DeployNetworkPluginModule) Init() {
 	}
 }
 
+// UpgradeTasks returns the tasks which redeploy the network plugin in an upgrade hop, once the kubelets are upgraded.
+// The plugin is only redeployed if CheckPluginVersion finds it outdated.
+func UpgradeTasks(m common.KubeModule) []task.Interface {
+	d := &DeployNetworkPluginModule{KubeModule: m}
+	d.Init()
+	if len(d.Tasks) == 0 {
+		return nil
+	}
+
+	check := &task.RemoteTask{
+		Name:     "CheckNetworkPlugin",
+		Desc:     "Check whether the network plugin needs to be upgraded",
+		Hosts:    m.Runtime.GetHostsByRole(common.Master),
+		Prepare:  new(common.OnlyFirstMaster),
+		Action:   new(CheckPluginVersion),
+		Parallel: true,
+	}
+
+	tasks := []task.Interface{check}
+	for _, t := range d.Tasks {
+		if rt, ok := t.(*task.RemoteTask); ok {
+			if rt.Prepare == nil {
+				rt.Prepare = new(PluginOutdated)
+			} else {
+				rt.Prepare = &prepare.PrepareCollection{new(PluginOutdated), rt.Prepare}
+			}
+		}
+		tasks = append(tasks, t)
+	}
+	return tasks
+}
+
 func deployMultus(d *DeployNetworkPluginModule) []task.Interface {
 	generateMultus := &task.RemoteTask{
 		Name:  "GenerateMultus",
@@ -128,7 +160,8 @@ func deployCalico(d *DeployNetworkPluginModule) []task.Interface {
 			Template: templates.CalicoNew,
 			Dst:      filepath.Join(common.KubeConfigDir, templates.CalicoNew.Name()),
 			Data: util.Data{
-				"KubePodsCIDR":            d.KubeConf.Cluster.Network.KubePodsCIDR,
+				"IPv4PodsCIDR":            d.KubeConf.Cluster.Network.PodCIDR(false),
+				"IPv6PodsCIDR":            d.KubeConf.Cluster.Network.PodCIDR(true),
 				"CalicoCniImage":          images.GetImage(d.Runtime, d.KubeConf, "calico-cni").ImageName(),
 				"CalicoNodeImage":         images.GetImage(d.Runtime, d.KubeConf, "calico-node").ImageName(),
 				"CalicoFlexvolImage":      images.GetImage(d.Runtime, d.KubeConf, "calico-flexvol").ImageName(),
@@ -22,13 +22,22 @@ import (
 	versionutil "k8s.io/apimachinery/pkg/util/version"
 )
 
+// manifestBoundary is the Kubernetes version from which the plugins use the new manifests.
+const manifestBoundary = "v1.16.0"
+
+// manifestChanged reports whether the upgrade from one version to the other crosses the manifest boundary.
+func manifestChanged(from, to string) bool {
+	boundary := versionutil.MustParseSemantic(manifestBoundary)
+	return versionutil.MustParseSemantic(from).LessThan(boundary) && versionutil.MustParseSemantic(to).AtLeast(boundary)
+}
+
 type OldK8sVersion struct {
 	common.KubePrepare
 	Not bool
 }
 
 func (o *OldK8sVersion) PreCheck(_ connector.Runtime) (bool, error) {
-	cmp, err := versionutil.MustParseSemantic(o.KubeConf.Cluster.Kubernetes.Version).Compare("v1.16.0")
+	cmp, err := versionutil.MustParseSemantic(o.KubeConf.Cluster.Kubernetes.Version).Compare(manifestBoundary)
 	if err != nil {
 		return false, err
 	}
@@ -50,3 +59,13 @@ func (e *EnableSSL) PreCheck(_ connector.Runtime) (bool, error) {
 	}
 	return false, nil
 }
+
+// PluginOutdated checks the result of CheckPluginVersion in the upgrade hop.
+type PluginOutdated struct {
+	common.KubePrepare
+}
+
+func (p *PluginOutdated) PreCheck(_ connector.Runtime) (bool, error) {
+	outdated, ok := p.PipelineCache.GetMustBool(common.NetworkPluginOutdated)
+	return ok && outdated, nil
+}
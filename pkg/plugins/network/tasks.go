@@ -27,6 +27,7 @@ import (
 	"github.com/kubesphere/kubekey/pkg/plugins/network/templates"
 	"github.com/pkg/errors"
 	"path/filepath"
+	"strings"
 )
 
 type DeployNetworkPlugin struct {
@@ -233,3 +234,53 @@ func (c *ChmodKubectlKo) Execute(runtime connector.Runtime) error {
 	}
 	return nil
 }
+
+// pluginDaemonSet is the DaemonSet of a network plugin, and the image of its first container.
+type pluginDaemonSet struct {
+	name  string
+	image string
+}
+
+var pluginDaemonSets = map[string]pluginDaemonSet{
+	common.Calico:  {name: "calico-node", image: "calico-node"},
+	common.Flannel: {name: "kube-flannel-ds", image: "flannel"},
+	common.Cilium:  {name: "cilium", image: "cilium"},
+	common.Kubeovn: {name: "kube-ovn-cni", image: "kubeovn"},
+}
+
+// CheckPluginVersion decides whether the network plugin is redeployed in the upgrade hop. It is, if the hop needs the
+// new manifests, or the image of the running plugin differs from the one KubeKey deploys.
+type CheckPluginVersion struct {
+	common.KubeAction
+}
+
+func (c *CheckPluginVersion) Execute(runtime connector.Runtime) error {
+	outdated, err := c.outdated(runtime)
+	if err != nil {
+		return err
+	}
+	c.PipelineCache.Set(common.NetworkPluginOutdated, outdated)
+	return nil
+}
+
+func (c *CheckPluginVersion) outdated(runtime connector.Runtime) (bool, error) {
+	currentVersion, ok := c.PipelineCache.GetMustString(common.K8sVersion)
+	if !ok {
+		return false, errors.New("get current Kubernetes version failed by pipeline cache")
+	}
+	if manifestChanged(currentVersion, c.KubeConf.Cluster.Kubernetes.Version) {
+		return true, nil
+	}
+
+	ds, ok := pluginDaemonSets[c.KubeConf.Cluster.Network.Plugin]
+	if !ok {
+		return false, nil
+	}
+	image, err := runtime.GetRunner().SudoCmd(fmt.Sprintf(
+		"/usr/local/bin/kubectl -n kube-system get ds %s --ignore-not-found -o jsonpath='{.spec.template.spec.containers[0].image}'",
+		ds.name), false)
+	if err != nil {
+		return false, errors.Wrap(errors.WithStack(err), fmt.Sprintf("get the image of %s failed", ds.name))
+	}
+	return strings.TrimSpace(image) != images.GetImage(runtime, c.KubeConf, ds.image).ImageName(), nil
+}
@@ -57,7 +57,9 @@ data:
           "nodename": "__KUBERNETES_NODE_NAME__",
           "mtu": __CNI_MTU__,
           "ipam": {
-              "type": "calico-ipam"
+              "type": "calico-ipam"{{ if .IPv6PodsCIDR }},
+              "assign_ipv4": "{{ if .IPv4PodsCIDR }}true{{ else }}false{{ end }}",
+              "assign_ipv6": "true"{{ end }}
           },
           "policy": {
               "type": "k8s"
@@ -4026,7 +4028,14 @@ spec:
             - name: IP_AUTODETECTION_METHOD
               value: "can-reach=$(NODEIP)"
             - name: IP
+              value: "{{ if .IPv4PodsCIDR }}autodetect{{ else }}none{{ end }}"
+{{- if .IPv6PodsCIDR }}
+            # Auto-detect the IPv6 address, the NODEIP is the IPv4 one on the dual-stack cluster.
+            - name: IP6
               value: "autodetect"
+            - name: IP6_AUTODETECTION_METHOD
+              value: "{{ if .IPv4PodsCIDR }}first-found{{ else }}can-reach=$(NODEIP){{ end }}"
+{{- end }}
             # Enable IPIP
             - name: CALICO_IPV4POOL_IPIP
               value: "{{ .IPIPMode }}"
@@ -4054,18 +4063,25 @@ spec:
             # The default IPv4 pool to create on startup if none exists. Pod IPs will be
             # chosen from this range. Changing this value after installation will have
             # no effect.
+{{- if .IPv4PodsCIDR }}
             - name: CALICO_IPV4POOL_CIDR
-              value: "{{ .KubePodsCIDR }}"
+              value: "{{ .IPv4PodsCIDR }}"
             - name: CALICO_IPV4POOL_BLOCK_SIZE
               value: "{{ .NodeCidrMaskSize }}"
+{{- end }}
+{{- if .IPv6PodsCIDR }}
+            # The default IPv6 pool to create on startup if none exists.
+            - name: CALICO_IPV6POOL_CIDR
+              value: "{{ .IPv6PodsCIDR }}"
+{{- end }}
             - name: CALICO_DISABLE_FILE_LOGGING
               value: "true"
             # Set Felix endpoint to host default action to ACCEPT.
             - name: FELIX_DEFAULTENDPOINTTOHOSTACTION
               value: "ACCEPT"
-            # Disable IPv6 on Kubernetes.
+            # Enable IPv6 on Kubernetes only if there is an IPv6 pod CIDR.
             - name: FELIX_IPV6SUPPORT
-              value: "false"
+              value: "{{ if .IPv6PodsCIDR }}true{{ else }}false{{ end }}"
             - name: FELIX_HEALTHENABLED
               value: "true"
           securityContext:
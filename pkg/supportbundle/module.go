@@ -0,0 +1,70 @@
+/*
+ Copyright 2021 The KubeSphere Authors.
+
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+     http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package supportbundle
+
+import (
+	"github.com/kubesphere/kubekey/pkg/common"
+	"github.com/kubesphere/kubekey/pkg/core/task"
+	"time"
+)
+
+type CollectModule struct {
+	common.KubeModule
+	Since    time.Duration
+	LocalDir string
+}
+
+func (c *CollectModule) Init() {
+	c.Name = "CollectModule"
+	c.Desc = "Collect the logs, configs and status of the nodes"
+
+	// The error is ignored, so the bundle is still created when some of the nodes are broken.
+	collect := &task.RemoteTask{
+		Name:        "CollectNodeInfo",
+		Desc:        "Collect the logs, configs and status of the node",
+		Hosts:       c.Runtime.GetAllHosts(),
+		Action:      &CollectNodeInfo{Since: c.Since, LocalDir: c.LocalDir},
+		Parallel:    true,
+		Retry:       1,
+		IgnoreError: true,
+	}
+
+	c.Tasks = []task.Interface{
+		collect,
+	}
+}
+
+type ArchiveModule struct {
+	common.KubeModule
+	LocalDir   string
+	BundleFile string
+}
+
+func (a *ArchiveModule) Init() {
+	a.Name = "ArchiveModule"
+	a.Desc = "Archive the support bundle"
+
+	archive := &task.LocalTask{
+		Name:   "ArchiveBundle",
+		Desc:   "Redact the secrets and archive the support bundle",
+		Action: &ArchiveBundle{LocalDir: a.LocalDir, BundleFile: a.BundleFile},
+	}
+
+	a.Tasks = []task.Interface{
+		archive,
+	}
+}
@@ -0,0 +1,73 @@
+/*
+ Copyright 2021 The KubeSphere Authors.
+
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+     http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package supportbundle
+
+import (
+	"os"
+	"path/filepath"
+	"regexp"
+)
+
+const Redacted = "REDACTED"
+
+var (
+	// password: xxx, {name: node1, password: "xxx"}, "auth": "xxx", client-key-data: xxx, token: xxx
+	secretFieldRegexp = regexp.MustCompile(`(?i)("?[\w.-]*(?:password|passwd|token|secret|key-data|privatekey|certificatekey|auth)"?\s*:[ \t]*)("[^"\n]*"|'[^'\n]*'|[^\s,}\]]+)`)
+	// ETCD_PASSWORD=xxx
+	secretEnvRegexp = regexp.MustCompile(`(?m)^(\s*(?:export\s+)?\w*(?:PASSWORD|TOKEN|SECRET)\s*=\s*)(\S+)`)
+	// --token=xxx, --etcd-password xxx
+	secretFlagRegexp = regexp.MustCompile(`(?i)(--[\w-]*(?:password|token|secret|certificate-key))(=|\s+)[^\s"'\\]+`)
+	privateKeyRegexp = regexp.MustCompile(`(?s)-----BEGIN ([A-Z ]*)PRIVATE KEY-----.*?-----END ([A-Z ]*)PRIVATE KEY-----`)
+)
+
+// Redact replaces the values of the secrets in the content, such as the passwords, tokens and private keys.
+func Redact(content []byte) []byte {
+	content = privateKeyRegexp.ReplaceAll(content, []byte("-----BEGIN ${1}PRIVATE KEY-----\n"+Redacted+"\n-----END ${2}PRIVATE KEY-----"))
+	content = secretFieldRegexp.ReplaceAllFunc(content, func(field []byte) []byte {
+		sub := secretFieldRegexp.FindSubmatch(field)
+		value := Redacted
+		// keep the quotes, so the yaml or json is still valid
+		if q := sub[2][0]; q == '"' || q == '\'' {
+			value = string(q) + Redacted + string(q)
+		}
+		return append(append([]byte{}, sub[1]...), value...)
+	})
+	content = secretEnvRegexp.ReplaceAll(content, []byte("${1}"+Redacted))
+	content = secretFlagRegexp.ReplaceAll(content, []byte("${1}${2}"+Redacted))
+	return content
+}
+
+// RedactDir redacts all of the regular files in the dir in place.
+func RedactDir(dir string) error {
+	return filepath.Walk(dir, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		if !info.Mode().IsRegular() {
+			return nil
+		}
+		return RedactFile(path)
+	})
+}
+
+func RedactFile(path string) error {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, Redact(content), 0600)
+}
@@ -0,0 +1,214 @@
+/*
+ Copyright 2021 The KubeSphere Authors.
+
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+     http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package supportbundle
+
+import (
+	"fmt"
+	"github.com/kubesphere/kubekey/pkg/common"
+	"github.com/kubesphere/kubekey/pkg/core/connector"
+	"github.com/kubesphere/kubekey/pkg/core/logger"
+	"github.com/kubesphere/kubekey/pkg/core/util"
+	"github.com/kubesphere/kubekey/pkg/healthcheck"
+	"github.com/kubesphere/kubekey/pkg/utils"
+	"github.com/pkg/errors"
+	"io"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+var (
+	// configFiles are the configs which are generated by kubekey, the secrets in them are redacted before archived.
+	// The private keys in /etc/kubernetes/pki are never collected.
+	configFiles = []string{
+		"/etc/kubernetes",
+		"/etc/etcd.env",
+		"/etc/systemd/system/etcd.service",
+		"/etc/systemd/system/kubelet.service",
+		"/etc/systemd/system/kubelet.service.d",
+		"/var/lib/kubelet/config.yaml",
+		"/var/lib/kubelet/kubeadm-flags.env",
+		"/etc/systemd/system/k3s.service",
+		"/etc/rancher/k3s",
+		"/etc/kubekey/haproxy",
+		"/etc/docker/daemon.json",
+		"/etc/containerd/config.toml",
+		"/etc/crio/crio.conf",
+		"/etc/isulad/daemon.json",
+		"/etc/hosts",
+		"/etc/resolv.conf",
+	}
+
+	systemCommands = map[string]string{
+		"uname":      "uname -a",
+		"os-release": "cat /etc/os-release",
+		"uptime":     "uptime",
+		"df":         "df -h",
+		"free":       "free -m",
+		"ip-addr":    "ip addr",
+		"ip-route":   "ip route",
+		"containers": "crictl ps -a",
+		"ps":         "ps aux",
+		"sysctl":     "sysctl -a",
+	}
+)
+
+type CollectNodeInfo struct {
+	common.KubeAction
+	Since    time.Duration
+	LocalDir string
+}
+
+func (c *CollectNodeInfo) Execute(runtime connector.Runtime) error {
+	host := runtime.RemoteHost()
+	stage := filepath.Join(common.TmpDir, "support-bundle")
+	remoteFile := filepath.Join(common.TmpDir, "support-bundle.tar.gz")
+
+	if err := utils.ResetTmpDir(runtime); err != nil {
+		return err
+	}
+	// The bundle holds the kubeconfigs, tokens and certificates of the node, keep it only readable by root.
+	if _, err := runtime.GetRunner().SudoCmd(fmt.Sprintf("umask 077 && mkdir -p %s/logs %s/status %s/configs %s/system", stage, stage, stage, stage), false); err != nil {
+		return errors.Wrap(errors.WithStack(err), "create support bundle dir failed")
+	}
+	defer func() {
+		if _, err := runtime.GetRunner().SudoCmd(fmt.Sprintf("rm -rf %s %s", stage, remoteFile), false); err != nil {
+			logger.Log.Warnf("clean support bundle on %s failed: %v", host.GetName(), err)
+		}
+	}()
+
+	// The failures of the commands are ignored, the outputs are kept in the bundle.
+	cmds := []string{
+		fmt.Sprintf("dmesg -T > %s/logs/dmesg.log 2>&1", stage),
+	}
+	for _, service := range healthcheck.NodeServices(c.KubeConf, host) {
+		cmds = append(cmds,
+			fmt.Sprintf("systemctl status %s --no-pager -l > %s/status/%s.txt 2>&1", service, stage, service),
+			fmt.Sprintf("journalctl -u %s --since=-%ds --no-pager > %s/logs/%s.log 2>&1", service, int(c.Since.Seconds()), stage, service))
+	}
+	for name, cmd := range systemCommands {
+		cmds = append(cmds, fmt.Sprintf("%s > %s/system/%s.txt 2>&1", cmd, stage, name))
+	}
+	for _, path := range configFiles {
+		cmds = append(cmds, fmt.Sprintf("if [ -e %s ]; then cp -rL --parents %s %s/configs; fi", path, path, stage))
+	}
+	cmds = append(cmds, fmt.Sprintf("rm -rf %s/configs/etc/kubernetes/pki", stage))
+	for _, cmd := range cmds {
+		if _, err := runtime.GetRunner().SudoCmd(cmd+" || true", false); err != nil {
+			return errors.Wrap(errors.WithStack(err), fmt.Sprintf("exec %s failed", cmd))
+		}
+	}
+
+	if _, err := runtime.GetRunner().SudoCmd(fmt.Sprintf("umask 077 && tar -czf %s -C %s .", remoteFile, stage), false); err != nil {
+		return errors.Wrap(errors.WithStack(err), "archive support bundle failed")
+	}
+
+	localFile := filepath.Join(c.LocalDir, fmt.Sprintf("%s.tar.gz", host.GetName()))
+	if err := runtime.GetRunner().Fetch(localFile, remoteFile); err != nil {
+		return errors.Wrap(errors.WithStack(err), "fetch support bundle failed")
+	}
+	defer os.Remove(localFile)
+
+	// The secrets are redacted right after the extraction, they are never left on the disk if kk stops later.
+	nodeDir := filepath.Join(c.LocalDir, host.GetName())
+	if err := util.Untar(localFile, nodeDir); err != nil {
+		_ = os.RemoveAll(nodeDir)
+		return errors.Wrap(errors.WithStack(err), fmt.Sprintf("extract %s failed", localFile))
+	}
+	if err := RedactDir(nodeDir); err != nil {
+		_ = os.RemoveAll(nodeDir)
+		return errors.Wrap(errors.WithStack(err), fmt.Sprintf("redact the support bundle of %s failed", host.GetName()))
+	}
+	return nil
+}
+
+type ArchiveBundle struct {
+	common.KubeAction
+	LocalDir   string
+	BundleFile string
+}
+
+func (a *ArchiveBundle) Execute(runtime connector.Runtime) error {
+	kkDir := filepath.Join(a.LocalDir, common.KubeKey)
+	if err := util.CreateDir(kkDir); err != nil {
+		return errors.Wrap(errors.WithStack(err), fmt.Sprintf("create local dir %s failed", kkDir))
+	}
+
+	// The logs of kk in the work dir, and the config file which the cluster is created by.
+	logDir := filepath.Join(runtime.GetWorkDir(), "logs")
+	entries, err := os.ReadDir(logDir)
+	if err != nil && !os.IsNotExist(err) {
+		return errors.Wrap(errors.WithStack(err), fmt.Sprintf("read dir %s failed", logDir))
+	}
+	for _, entry := range entries {
+		if !entry.Type().IsRegular() {
+			continue
+		}
+		if err := copyFile(filepath.Join(logDir, entry.Name()), filepath.Join(kkDir, "logs", entry.Name())); err != nil {
+			return err
+		}
+	}
+	if a.KubeConf.Arg.FilePath != "" {
+		if err := copyFile(a.KubeConf.Arg.FilePath, filepath.Join(kkDir, filepath.Base(a.KubeConf.Arg.FilePath))); err != nil {
+			return err
+		}
+	}
+
+	for _, host := range runtime.GetAllHosts() {
+		if !util.IsExist(filepath.Join(a.LocalDir, host.GetName())) {
+			logger.Log.Warningf("the support bundle of %s is not collected", host.GetName())
+		}
+	}
+
+	// The bundles of the nodes have been redacted when they were collected.
+	if err := RedactDir(kkDir); err != nil {
+		return errors.Wrap(errors.WithStack(err), "redact the logs of kk failed")
+	}
+	if err := util.TarWithPerm(a.LocalDir, a.BundleFile, filepath.Dir(a.LocalDir), 0600); err != nil {
+		return errors.Wrap(errors.WithStack(err), fmt.Sprintf("archive %s failed", a.BundleFile))
+	}
+	if err := os.RemoveAll(a.LocalDir); err != nil {
+		return errors.Wrap(errors.WithStack(err), fmt.Sprintf("remove %s failed", a.LocalDir))
+	}
+	logger.Log.Infof("support bundle is saved to %s", a.BundleFile)
+	return nil
+}
+
+func copyFile(src, dst string) error {
+	if err := util.CreateDir(filepath.Dir(dst)); err != nil {
+		return errors.Wrap(errors.WithStack(err), fmt.Sprintf("create local dir %s failed", filepath.Dir(dst)))
+	}
+	in, err := os.Open(src)
+	if err != nil {
+		return errors.Wrap(errors.WithStack(err), fmt.Sprintf("open %s failed", src))
+	}
+	defer in.Close()
+	out, err := os.OpenFile(dst, os.O_CREATE|os.O_TRUNC|os.O_WRONLY, 0600)
+	if err != nil {
+		return errors.Wrap(errors.WithStack(err), fmt.Sprintf("create %s failed", dst))
+	}
+	defer out.Close()
+	if _, err := io.Copy(out, in); err != nil {
+		return errors.Wrap(errors.WithStack(err), fmt.Sprintf("copy %s to %s failed", src, dst))
+	}
+	return nil
+}
+
+// BundleName returns the name of the support bundle which is created at the time.
+func BundleName(t time.Time) string {
+	return fmt.Sprintf("support-bundle-%s", t.Format("20060102150405"))
+}